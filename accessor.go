@@ -0,0 +1,482 @@
+package gltf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// elementSize returns the number of bytes used by an element,
+// including the padding required to align each matrix column to 4 bytes.
+func elementSize(componentType ComponentType, accessorType AccessorType) uint32 {
+	size := componentType.ByteSize()
+	switch accessorType {
+	case Mat2:
+		return 2 * padding4(2*size)
+	case Mat3:
+		return 3 * padding4(3*size)
+	}
+	return size * accessorType.Components()
+}
+
+// padding4 returns n rounded up to the next multiple of 4.
+func padding4(n uint32) uint32 {
+	return ((n + 3) / 4) * 4
+}
+
+// accessorView returns the bufferView data starting at the first element of the accessor
+// and the number of bytes between the start of two consecutive elements.
+// It fails if the elements do not fit inside the bufferView or the buffer data is not loaded.
+func (d *Document) accessorView(acc *Accessor) ([]byte, uint32, error) {
+	if acc.BufferView == nil {
+		return nil, 0, errors.New("gltf: accessor without bufferView")
+	}
+	if int(*acc.BufferView) >= len(d.BufferViews) {
+		return nil, 0, fmt.Errorf("gltf: bufferView index %d out of range", *acc.BufferView)
+	}
+	view, err := d.bufferViewData(*acc.BufferView)
+	if err != nil {
+		return nil, 0, err
+	}
+	size := elementSize(acc.ComponentType, acc.Type)
+	stride := d.BufferViews[*acc.BufferView].ByteStride
+	if stride == 0 {
+		stride = size
+	}
+	if acc.Count > 0 && uint64(acc.ByteOffset)+uint64(stride)*uint64(acc.Count-1)+uint64(size) > uint64(len(view)) {
+		return nil, 0, errors.New("gltf: accessor elements out of bufferView bounds")
+	}
+	return view[acc.ByteOffset:], stride, nil
+}
+
+// bufferViewData returns the slice of the buffer data covered by the bufferView.
+func (d *Document) bufferViewData(index uint32) ([]byte, error) {
+	bv := d.BufferViews[index]
+	if int(bv.Buffer) >= len(d.Buffers) {
+		return nil, fmt.Errorf("gltf: buffer index %d out of range", bv.Buffer)
+	}
+	data := d.Buffers[bv.Buffer].Data
+	if uint64(bv.ByteOffset)+uint64(bv.ByteLength) > uint64(len(data)) {
+		return nil, errors.New("gltf: bufferView out of buffer bounds or buffer data not loaded")
+	}
+	return data[bv.ByteOffset : bv.ByteOffset+bv.ByteLength], nil
+}
+
+// ReadAccessor returns the components of all the accessor elements, flattened in order.
+// Normalized integers are converted to floating-point values as defined by the glTF spec,
+// the padding between matrix columns is skipped and sparse substitutions are applied.
+// An accessor without bufferView is initialized with zeros.
+func (d *Document) ReadAccessor(accessorIndex uint32) ([]float64, error) {
+	if int(accessorIndex) >= len(d.Accessors) {
+		return nil, fmt.Errorf("gltf: accessor index %d out of range", accessorIndex)
+	}
+	return d.readAccessor(&d.Accessors[accessorIndex])
+}
+
+// ReadAccessorFloat32 is like ReadAccessor but returns float32 values, which halves the memory
+// and can be uploaded to the GPU without further conversion.
+// FLOAT accessors that are not sparse are read directly from the buffer data without loss of precision.
+// Other accessors are read as float64 values and converted, so UNSIGNED_INT components above 2^24
+// are rounded to the nearest representable float32.
+func (d *Document) ReadAccessorFloat32(accessorIndex uint32) ([]float32, error) {
+	if int(accessorIndex) >= len(d.Accessors) {
+		return nil, fmt.Errorf("gltf: accessor index %d out of range", accessorIndex)
+	}
+	acc := &d.Accessors[accessorIndex]
+	if acc.ComponentType != Float || acc.Sparse != nil || acc.BufferView == nil {
+		values, err := d.readAccessor(acc)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]float32, len(values))
+		for i, v := range values {
+			out[i] = float32(v)
+		}
+		return out, nil
+	}
+	view, stride, err := d.accessorView(acc)
+	if err != nil {
+		return nil, err
+	}
+	n := acc.Type.Components()
+	values := make([]float32, uint64(acc.Count)*uint64(n))
+	r := byteReader{data: view, stride: stride}
+	offsets := componentOffsets(acc.ComponentType, acc.Type)
+	for i := uint32(0); i < acc.Count; i++ {
+		for j, offset := range offsets {
+			if values[i*n+uint32(j)], err = r.readFloat32(i, offset); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return values, nil
+}
+
+// ReadMatrices returns the elements of a MAT2, MAT3 or MAT4 accessor.
+// Each matrix is stored in column-major order without the column padding.
+func (d *Document) ReadMatrices(accessorIndex uint32) ([][]float64, error) {
+	if int(accessorIndex) >= len(d.Accessors) {
+		return nil, fmt.Errorf("gltf: accessor index %d out of range", accessorIndex)
+	}
+	acc := &d.Accessors[accessorIndex]
+	if acc.Type != Mat2 && acc.Type != Mat3 && acc.Type != Mat4 {
+		return nil, errors.New("gltf: accessor is not a matrix")
+	}
+	values, err := d.readAccessor(acc)
+	if err != nil {
+		return nil, err
+	}
+	n := int(acc.Type.Components())
+	matrices := make([][]float64, acc.Count)
+	for i := range matrices {
+		matrices[i] = values[i*n : (i+1)*n : (i+1)*n]
+	}
+	return matrices, nil
+}
+
+// AccessorBounds returns the minimum and maximum value of each component of the accessor elements.
+// The bounds declared in the accessor are returned when present, in which case declared is true
+// and the buffer data is not read. Otherwise they are computed from the data.
+// As the declared bounds, the computed bounds of normalized accessors are not normalized.
+func (d *Document) AccessorBounds(accessorIndex uint32) (min, max []float64, declared bool, err error) {
+	if int(accessorIndex) >= len(d.Accessors) {
+		return nil, nil, false, fmt.Errorf("gltf: accessor index %d out of range", accessorIndex)
+	}
+	acc := d.Accessors[accessorIndex]
+	n := int(acc.Type.Components())
+	if len(acc.Min) == n && len(acc.Max) == n {
+		return acc.Min, acc.Max, true, nil
+	}
+	if acc.Count == 0 {
+		return nil, nil, false, errors.New("gltf: bounds of an empty accessor")
+	}
+	acc.Normalized = false
+	values, err := d.readAccessor(&acc)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	min = append([]float64(nil), values[:n]...)
+	max = append([]float64(nil), values[:n]...)
+	for i := n; i < len(values); i++ {
+		c := i % n
+		if values[i] < min[c] {
+			min[c] = values[i]
+		} else if values[i] > max[c] {
+			max[c] = values[i]
+		}
+	}
+	return min, max, false, nil
+}
+
+// ReadIndices returns the values of an accessor of unsigned integer scalars, such as the primitive indices.
+func (d *Document) ReadIndices(accessorIndex uint32) ([]uint32, error) {
+	if int(accessorIndex) >= len(d.Accessors) {
+		return nil, fmt.Errorf("gltf: accessor index %d out of range", accessorIndex)
+	}
+	acc := &d.Accessors[accessorIndex]
+	if acc.Type != Scalar {
+		return nil, errors.New("gltf: indices accessor must be a scalar")
+	}
+	if acc.ComponentType != UnsignedByte && acc.ComponentType != UnsignedShort && acc.ComponentType != UnsignedInt {
+		return nil, errors.New("gltf: indices accessor must use an unsigned integer component type")
+	}
+	values, err := d.readAccessor(acc)
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]uint32, len(values))
+	for i, v := range values {
+		indices[i] = uint32(v)
+	}
+	return indices, nil
+}
+
+// AddIndices writes the indices in a new bufferView of the first buffer, which is created if the document does not have any,
+// and appends a scalar accessor that references them. The return value is the index of the new accessor.
+// The component type is the smallest of UNSIGNED_BYTE, UNSIGNED_SHORT and UNSIGNED_INT that can store all the indices.
+// The largest value of each type is not used, as it is reserved for primitive restart.
+func (d *Document) AddIndices(indices []uint32) (uint32, error) {
+	var max uint32
+	for _, index := range indices {
+		if index > max {
+			max = index
+		}
+	}
+	componentType := UnsignedInt
+	if max < math.MaxUint8 {
+		componentType = UnsignedByte
+	} else if max < math.MaxUint16 {
+		componentType = UnsignedShort
+	}
+	return d.AddIndicesAs(indices, componentType)
+}
+
+// AddIndicesAs is like AddIndices but stores the indices with the given component type,
+// which must be UNSIGNED_BYTE, UNSIGNED_SHORT or UNSIGNED_INT and able to store all of them.
+func (d *Document) AddIndicesAs(indices []uint32, componentType ComponentType) (uint32, error) {
+	if len(indices) == 0 {
+		return 0, errors.New("gltf: empty indices")
+	}
+	var limit uint32
+	switch componentType {
+	case UnsignedByte:
+		limit = math.MaxUint8
+	case UnsignedShort:
+		limit = math.MaxUint16
+	case UnsignedInt:
+		limit = math.MaxUint32
+	default:
+		return 0, errors.New("gltf: indices must use an unsigned integer component type")
+	}
+	size := componentType.ByteSize()
+	data := make([]byte, uint32(len(indices))*size)
+	for i, index := range indices {
+		if index >= limit {
+			return 0, fmt.Errorf("gltf: index %d does not fit the component type", index)
+		}
+		switch componentType {
+		case UnsignedByte:
+			data[i] = uint8(index)
+		case UnsignedShort:
+			binary.LittleEndian.PutUint16(data[i*2:], uint16(index))
+		default:
+			binary.LittleEndian.PutUint32(data[i*4:], index)
+		}
+	}
+	if len(d.Buffers) == 0 {
+		d.Buffers = append(d.Buffers, Buffer{})
+	}
+	view, err := d.appendBufferView(0, data, 0, ElementArrayBuffer)
+	if err != nil {
+		return 0, err
+	}
+	d.Accessors = append(d.Accessors, Accessor{
+		BufferView:    Index(view),
+		ComponentType: componentType,
+		Count:         uint32(len(indices)),
+		Type:          Scalar,
+	})
+	return uint32(len(d.Accessors) - 1), nil
+}
+
+// An InterleavedAttribute is a vertex attribute written by AddInterleaved.
+type InterleavedAttribute struct {
+	Semantic      string
+	Type          AccessorType
+	ComponentType ComponentType
+	Normalized    bool
+	Data          []float64 // The components of every vertex, one after the other.
+	NoBounds      bool      // Do not set the min and max bounds, which are set by default for POSITION.
+}
+
+// AddInterleaved writes the attributes in a single new bufferView of the first buffer,
+// which is created if the document does not have any, with the elements of each vertex stored contiguously
+// in the order of attrs, and appends an accessor for each of them.
+// Every element starts at a 4-byte boundary inside the vertex, as required for vertex attributes,
+// and the byteStride is the size of the padded elements.
+// The POSITION accessor gets its min and max bounds, as required by the specification, unless NoBounds is set.
+// They are computed from the stored values while writing them, so they match the data after the conversion,
+// and as required by the specification they are not normalized, even if the attribute is.
+// Values are converted to the component type of each attribute, normalized ones are clamped and scaled.
+// The return value maps each semantic to the index of its accessor and can be used as the attributes of a primitive.
+func (d *Document) AddInterleaved(attrs []InterleavedAttribute) (Attribute, error) {
+	if len(attrs) == 0 {
+		return nil, errors.New("gltf: no attributes to interleave")
+	}
+	var count, stride uint32
+	offsets := make([]uint32, len(attrs))
+	for i, attr := range attrs {
+		n := int(attr.Type.Components())
+		if len(attr.Data)%n != 0 {
+			return nil, fmt.Errorf("gltf: data of attribute %s is not a whole number of elements", attr.Semantic)
+		}
+		if i == 0 {
+			count = uint32(len(attr.Data) / n)
+		} else if uint32(len(attr.Data)/n) != count {
+			return nil, errors.New("gltf: all the interleaved attributes must have the same count")
+		}
+		for _, other := range attrs[:i] {
+			if other.Semantic == attr.Semantic {
+				return nil, fmt.Errorf("gltf: duplicated attribute %s", attr.Semantic)
+			}
+		}
+		offsets[i] = stride
+		stride += padding4(elementSize(attr.ComponentType, attr.Type))
+	}
+	if count == 0 {
+		return nil, errors.New("gltf: empty attributes")
+	}
+	if stride > 252 {
+		return nil, errors.New("gltf: interleaved vertex size exceeds the maximum byteStride")
+	}
+	data := make([]byte, stride*count)
+	r := byteReader{data: data, stride: stride}
+	mins, maxs := make([][]float64, len(attrs)), make([][]float64, len(attrs))
+	for i, attr := range attrs {
+		components := componentOffsets(attr.ComponentType, attr.Type)
+		n := uint32(len(components))
+		size := attr.ComponentType.ByteSize()
+		bounds := attr.Semantic == "POSITION" && !attr.NoBounds
+		for v := uint32(0); v < count; v++ {
+			for c, offset := range components {
+				start := v*stride + offsets[i] + offset
+				putComponent(data[start:start+size], attr.Data[v*n+uint32(c)], attr.ComponentType, attr.Normalized)
+				if !bounds {
+					continue
+				}
+				// The data is long enough, as it has just been written.
+				stored, _ := r.readComponent(v, offsets[i]+offset, attr.ComponentType, false)
+				if v == 0 {
+					mins[i], maxs[i] = append(mins[i], stored), append(maxs[i], stored)
+				} else {
+					mins[i][c], maxs[i][c] = math.Min(mins[i][c], stored), math.Max(maxs[i][c], stored)
+				}
+			}
+		}
+	}
+	if len(d.Buffers) == 0 {
+		d.Buffers = append(d.Buffers, Buffer{})
+	}
+	view, err := d.appendBufferView(0, data, stride, ArrayBuffer)
+	if err != nil {
+		return nil, err
+	}
+	out := make(Attribute, len(attrs))
+	for i, attr := range attrs {
+		d.Accessors = append(d.Accessors, Accessor{
+			BufferView:    Index(view),
+			ByteOffset:    offsets[i],
+			ComponentType: attr.ComponentType,
+			Normalized:    attr.Normalized,
+			Count:         count,
+			Type:          attr.Type,
+			Min:           mins[i],
+			Max:           maxs[i],
+		})
+		out[attr.Semantic] = uint32(len(d.Accessors) - 1)
+	}
+	return out, nil
+}
+
+// putComponent encodes v as a single component of the given type at the start of b,
+// the inverse of byteReader.readComponent.
+// Normalized values are clamped to [-1, 1] or [0, 1] and scaled, other integers are rounded.
+func putComponent(b []byte, v float64, componentType ComponentType, normalized bool) {
+	scale := func(max float64, signed bool) float64 {
+		if !normalized {
+			return math.Round(v)
+		}
+		min := 0.0
+		if signed {
+			min = -1
+		}
+		return math.Round(math.Max(min, math.Min(1, v)) * max)
+	}
+	switch componentType {
+	case Byte:
+		b[0] = uint8(int8(scale(math.MaxInt8, true)))
+	case UnsignedByte:
+		b[0] = uint8(scale(math.MaxUint8, false))
+	case Short:
+		binary.LittleEndian.PutUint16(b, uint16(int16(scale(math.MaxInt16, true))))
+	case UnsignedShort:
+		binary.LittleEndian.PutUint16(b, uint16(scale(math.MaxUint16, false)))
+	case UnsignedInt:
+		binary.LittleEndian.PutUint32(b, uint32(math.Round(v)))
+	default:
+		binary.LittleEndian.PutUint32(b, math.Float32bits(float32(v)))
+	}
+}
+
+func (d *Document) readAccessor(acc *Accessor) ([]float64, error) {
+	n := acc.Type.Components()
+	values := make([]float64, uint64(acc.Count)*uint64(n))
+	if acc.BufferView != nil {
+		view, stride, err := d.accessorView(acc)
+		if err != nil {
+			return nil, err
+		}
+		r := byteReader{data: view, stride: stride}
+		offsets := componentOffsets(acc.ComponentType, acc.Type)
+		for i := uint32(0); i < acc.Count; i++ {
+			for j, offset := range offsets {
+				if values[i*n+uint32(j)], err = r.readComponent(i, offset, acc.ComponentType, acc.Normalized); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if acc.Sparse != nil {
+		if err := d.applySparse(acc, values); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// applySparse overwrites the elements of values pointed by the sparse indices.
+// The indices are read one by one through a byteReader, so malformed data is reported instead of panicking.
+func (d *Document) applySparse(acc *Accessor, values []float64) error {
+	indices, substitutes := sparseAccessors(acc)
+	switch indices.ComponentType {
+	case UnsignedByte, UnsignedShort, UnsignedInt:
+	default:
+		return errors.New("gltf: sparse indices component type must be UNSIGNED_BYTE, UNSIGNED_SHORT or UNSIGNED_INT")
+	}
+	view, stride, err := d.accessorView(&indices)
+	if err != nil {
+		return err
+	}
+	r := byteReader{data: view, stride: stride}
+	if int(*substitutes.BufferView) >= len(d.BufferViews) {
+		return fmt.Errorf("gltf: bufferView index %d out of range", *substitutes.BufferView)
+	}
+	sv, err := d.readAccessor(&substitutes)
+	if err != nil {
+		return err
+	}
+	n := int(acc.Type.Components())
+	for i := uint32(0); i < indices.Count; i++ {
+		pos, err := r.readComponent(i, 0, indices.ComponentType, false)
+		if err != nil {
+			return err
+		}
+		if pos < 0 || pos >= float64(acc.Count) {
+			return errors.New("gltf: sparse index out of range")
+		}
+		copy(values[int(pos)*n:], sv[int(i)*n:(int(i)+1)*n])
+	}
+	return nil
+}
+
+// sparseAccessors returns dense accessors that describe the indices and the values of a sparse accessor.
+func sparseAccessors(acc *Accessor) (indices, values Accessor) {
+	sparse := acc.Sparse
+	indices = Accessor{BufferView: Index(sparse.Indices.BufferView), ByteOffset: sparse.Indices.ByteOffset, ComponentType: sparse.Indices.ComponentType, Count: sparse.Count, Type: Scalar}
+	values = Accessor{BufferView: Index(sparse.Values.BufferView), ByteOffset: sparse.Values.ByteOffset, ComponentType: acc.ComponentType, Normalized: acc.Normalized, Count: sparse.Count, Type: acc.Type}
+	return indices, values
+}
+
+// componentOffsets returns the byte offset of each component inside an element.
+func componentOffsets(componentType ComponentType, accessorType AccessorType) []uint32 {
+	size := componentType.ByteSize()
+	n := accessorType.Components()
+	offsets := make([]uint32, n)
+	var rows uint32
+	switch accessorType {
+	case Mat2:
+		rows = 2
+	case Mat3:
+		rows = 3
+	}
+	for i := range offsets {
+		c := uint32(i)
+		if rows == 0 {
+			offsets[i] = c * size
+		} else {
+			offsets[i] = (c/rows)*padding4(rows*size) + (c%rows)*size
+		}
+	}
+	return offsets
+}
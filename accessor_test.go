@@ -0,0 +1,390 @@
+package gltf
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDocument_ReadAccessor(t *testing.T) {
+	data := encodeData(
+		[]int8{1, 2, 3, 0, 4, 5, 6, 0, 7, 8, 9, 0}, // MAT3 of bytes, one element
+		[]uint8{255, 0, 51, 0},                     // normalized VEC2 of unsigned bytes, two elements
+		[]uint16{0, 2},                             // sparse indices
+		[]uint8{1, 2, 3, 4},                        // sparse values
+		[]int16{1, 2, 3, 4},                        // MAT2 of shorts, one element
+	)
+	doc := &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: Byte, Count: 1, Type: Mat3},
+			{BufferView: Index(1), ComponentType: UnsignedByte, Normalized: true, Count: 2, Type: Vec2},
+			{ComponentType: UnsignedByte, Count: 3, Type: Vec2, Sparse: &Sparse{Count: 2,
+				Indices: SparseIndices{BufferView: 2, ComponentType: UnsignedShort},
+				Values:  SparseValues{BufferView: 3},
+			}},
+			{BufferView: Index(4), ComponentType: Short, Count: 1, Type: Mat2},
+			{ComponentType: Float, Count: 2, Type: Scalar},
+			{BufferView: Index(1), ComponentType: Float, Count: 2, Type: Vec2},
+			{ComponentType: UnsignedByte, Count: 3, Type: Vec2, Sparse: &Sparse{Count: 2,
+				Indices: SparseIndices{BufferView: 0, ComponentType: Byte},
+				Values:  SparseValues{BufferView: 3},
+			}},
+			{ComponentType: UnsignedByte, Count: 3, Type: Scalar, Sparse: &Sparse{Count: 3,
+				Indices: SparseIndices{BufferView: 3, ComponentType: UnsignedShort},
+				Values:  SparseValues{BufferView: 3},
+			}},
+		},
+		BufferViews: []BufferView{
+			{ByteOffset: 0, ByteLength: 12},
+			{ByteOffset: 12, ByteLength: 4},
+			{ByteOffset: 16, ByteLength: 4},
+			{ByteOffset: 20, ByteLength: 4},
+			{ByteOffset: 24, ByteLength: 8},
+		},
+		Buffers: []Buffer{{ByteLength: uint32(len(data)), Data: data}},
+	}
+	tests := []struct {
+		name    string
+		index   uint32
+		want    []float64
+		wantErr bool
+	}{
+		{"mat3", 0, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}, false},
+		{"normalized", 1, []float64{1, 0, 0.2, 0}, false},
+		{"sparse", 2, []float64{1, 2, 0, 0, 3, 4}, false},
+		{"mat2", 3, []float64{1, 2, 3, 4}, false},
+		{"zeros", 4, []float64{0, 0}, false},
+		{"outOfBounds", 5, nil, true},
+		{"sparseSignedIndices", 6, nil, true},
+		{"sparseTruncatedIndices", 7, nil, true},
+		{"outOfRange", 8, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.ReadAccessor(tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.ReadAccessor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Document.ReadAccessor() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_ReadMatrices(t *testing.T) {
+	data := encodeData(
+		[]uint8{1, 2, 3, 0, 4, 5, 6, 0, 7, 8, 9, 0, 9, 8, 7, 0, 6, 5, 4, 0, 3, 2, 1, 0},
+		[]float32{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1, 2, 3, 1},
+	)
+	doc := &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: UnsignedByte, Count: 2, Type: Mat3},
+			{BufferView: Index(1), ComponentType: Float, Count: 1, Type: Mat4},
+			{BufferView: Index(1), ComponentType: Float, Count: 4, Type: Vec4},
+		},
+		BufferViews: []BufferView{
+			{ByteOffset: 0, ByteLength: 24},
+			{ByteOffset: 24, ByteLength: 64},
+		},
+		Buffers: []Buffer{{ByteLength: uint32(len(data)), Data: data}},
+	}
+	tests := []struct {
+		name    string
+		index   uint32
+		want    [][]float64
+		wantErr bool
+	}{
+		{"mat3", 0, [][]float64{{1, 2, 3, 4, 5, 6, 7, 8, 9}, {9, 8, 7, 6, 5, 4, 3, 2, 1}}, false},
+		{"mat4", 1, [][]float64{{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1, 2, 3, 1}}, false},
+		{"notMatrix", 2, nil, true},
+		{"outOfRange", 3, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.ReadMatrices(tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.ReadMatrices() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Document.ReadMatrices() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_AccessorBounds(t *testing.T) {
+	data := encodeData(
+		[]float32{1, -2, 3, -1, 5, 0, 0, 0, 2},
+		[]uint8{255, 0, 128, 64},
+	)
+	doc := &Document{
+		Accessors: []Accessor{
+			{ComponentType: Float, Count: 3, Type: Vec3, Min: []float64{-10, -10, -10}, Max: []float64{10, 10, 10}},
+			{BufferView: Index(0), ComponentType: Float, Count: 3, Type: Vec3},
+			{BufferView: Index(1), ComponentType: UnsignedByte, Normalized: true, Count: 2, Type: Vec2},
+			{BufferView: Index(0), ComponentType: Float, Count: 3, Type: Vec3, Min: []float64{-1}, Max: []float64{1}},
+			{ComponentType: Float, Count: 0, Type: Scalar},
+			{BufferView: Index(2), ComponentType: Float, Count: 1, Type: Scalar},
+		},
+		BufferViews: []BufferView{
+			{ByteOffset: 0, ByteLength: 36},
+			{ByteOffset: 36, ByteLength: 4},
+			{ByteOffset: 40, ByteLength: 4},
+		},
+		Buffers: []Buffer{{ByteLength: uint32(len(data)), Data: data}},
+	}
+	tests := []struct {
+		name         string
+		index        uint32
+		wantMin      []float64
+		wantMax      []float64
+		wantDeclared bool
+		wantErr      bool
+	}{
+		{"declared", 0, []float64{-10, -10, -10}, []float64{10, 10, 10}, true, false},
+		{"computed", 1, []float64{-1, -2, 0}, []float64{1, 5, 3}, false, false},
+		{"normalized", 2, []float64{128, 0}, []float64{255, 64}, false, false},
+		{"invalidDeclared", 3, []float64{-1, -2, 0}, []float64{1, 5, 3}, false, false},
+		{"empty", 4, nil, nil, false, true},
+		{"noData", 5, nil, nil, false, true},
+		{"outOfRange", 6, nil, nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMin, gotMax, gotDeclared, err := doc.AccessorBounds(tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.AccessorBounds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := deep.Equal(gotMin, tt.wantMin); diff != nil {
+				t.Errorf("Document.AccessorBounds() min = %v", diff)
+			}
+			if diff := deep.Equal(gotMax, tt.wantMax); diff != nil {
+				t.Errorf("Document.AccessorBounds() max = %v", diff)
+			}
+			if gotDeclared != tt.wantDeclared {
+				t.Errorf("Document.AccessorBounds() declared = %v, want %v", gotDeclared, tt.wantDeclared)
+			}
+		})
+	}
+}
+
+func TestDocument_AddIndices(t *testing.T) {
+	tests := []struct {
+		name          string
+		indices       []uint32
+		componentType ComponentType
+		force         bool
+		wantType      ComponentType
+		wantLength    uint32
+		wantErr       bool
+	}{
+		{"byte", []uint32{0, 1, 254}, 0, false, UnsignedByte, 3, false},
+		{"short", []uint32{0, 255, 2}, 0, false, UnsignedShort, 6, false},
+		{"shortLimit", []uint32{65534}, 0, false, UnsignedShort, 2, false},
+		{"int", []uint32{65535, 1}, 0, false, UnsignedInt, 8, false},
+		{"forced", []uint32{0, 1, 2}, UnsignedInt, true, UnsignedInt, 12, false},
+		{"forcedTooSmall", []uint32{0, 255}, UnsignedByte, true, 0, 0, true},
+		{"forcedFloat", []uint32{0, 1}, Float, true, 0, 0, true},
+		{"empty", nil, 0, false, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{Buffers: []Buffer{{ByteLength: 1, Data: []byte{7}}}}
+			var (
+				got uint32
+				err error
+			)
+			if tt.force {
+				got, err = doc.AddIndicesAs(tt.indices, tt.componentType)
+			} else {
+				got, err = doc.AddIndices(tt.indices)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.AddIndices() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			acc := doc.Accessors[got]
+			if acc.ComponentType != tt.wantType || doc.BufferViews[0].ByteLength != tt.wantLength {
+				t.Errorf("Document.AddIndices() = %v with length %d, want %v with length %d", acc.ComponentType, doc.BufferViews[0].ByteLength, tt.wantType, tt.wantLength)
+			}
+			if diff := deep.Equal(doc.BufferViews[0], BufferView{ByteOffset: 4, ByteLength: tt.wantLength, Target: ElementArrayBuffer}); diff != nil {
+				t.Errorf("Document.AddIndices() = %v", diff)
+			}
+			indices, err := doc.ReadIndices(got)
+			if err != nil {
+				t.Fatalf("Document.ReadIndices() error = %v", err)
+			}
+			if diff := deep.Equal(indices, tt.indices); diff != nil {
+				t.Errorf("Document.AddIndices() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_ReadAccessor_interleaved(t *testing.T) {
+	// Each vertex has a float VEC3, a normalized unsigned byte VEC4 and an unsigned short, padded to 20 bytes.
+	data := encodeData(
+		[]float32{1, 2, 3}, []uint8{255, 0, 51, 255}, []uint16{7, 0},
+		[]float32{-4, 5, 6}, []uint8{0, 102, 255, 0}, []uint16{65535, 0},
+	)
+	doc := &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: Float, Count: 2, Type: Vec3},
+			{BufferView: Index(0), ByteOffset: 12, ComponentType: UnsignedByte, Normalized: true, Count: 2, Type: Vec4},
+			{BufferView: Index(0), ByteOffset: 16, ComponentType: UnsignedShort, Count: 2, Type: Scalar},
+			{BufferView: Index(0), ByteOffset: 4, ComponentType: Float, Count: 2, Type: Scalar},
+		},
+		BufferViews: []BufferView{{ByteLength: uint32(len(data)), ByteStride: 20, Target: ArrayBuffer}},
+		Buffers:     []Buffer{{ByteLength: uint32(len(data)), Data: data}},
+	}
+	tests := []struct {
+		name  string
+		index uint32
+		want  []float64
+	}{
+		{"position", 0, []float64{1, 2, 3, -4, 5, 6}},
+		{"color", 1, []float64{1, 0, 0.2, 1, 0, 0.4, 1, 0}},
+		{"id", 2, []float64{7, 65535}},
+		{"component", 3, []float64{2, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.ReadAccessor(tt.index)
+			if err != nil {
+				t.Fatalf("Document.ReadAccessor() error = %v", err)
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Document.ReadAccessor() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_ReadAccessorFloat32(t *testing.T) {
+	data := encodeData(
+		[]float32{1.5, -2, 3, 0.1}, // float VEC2, two elements
+		[]uint32{16777217, 3},      // unsigned int scalar, two elements
+	)
+	doc := &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: Float, Count: 2, Type: Vec2},
+			{BufferView: Index(1), ComponentType: UnsignedInt, Count: 2, Type: Scalar},
+			{ComponentType: Float, Count: 2, Type: Scalar},
+			{BufferView: Index(0), ByteOffset: 4, ComponentType: Float, Count: 4, Type: Scalar},
+		},
+		BufferViews: []BufferView{
+			{ByteOffset: 0, ByteLength: 16},
+			{ByteOffset: 16, ByteLength: 8},
+		},
+		Buffers: []Buffer{{ByteLength: uint32(len(data)), Data: data}},
+	}
+	tests := []struct {
+		name    string
+		index   uint32
+		want    []float32
+		wantErr bool
+	}{
+		{"float", 0, []float32{1.5, -2, 3, 0.1}, false},
+		{"rounded", 1, []float32{16777216, 3}, false},
+		{"zeros", 2, []float32{0, 0}, false},
+		{"outOfBounds", 3, nil, true},
+		{"outOfRange", 4, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.ReadAccessorFloat32(tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.ReadAccessorFloat32() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Document.ReadAccessorFloat32() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_AddInterleaved(t *testing.T) {
+	doc := &Document{Buffers: []Buffer{{ByteLength: 2, Data: []byte{1, 2}}}}
+	attrs := []InterleavedAttribute{
+		{Semantic: "POSITION", Type: Vec3, ComponentType: Float, Data: []float64{1, 2, 3, -1, 5, 0}},
+		{Semantic: "COLOR_0", Type: Vec3, ComponentType: UnsignedByte, Normalized: true, Data: []float64{0, 0.5, 2, 1, 1, 1}},
+		{Semantic: "TEXCOORD_0", Type: Vec2, ComponentType: Short, Normalized: true, Data: []float64{-1, 1, 0, 0.5}},
+		{Semantic: "_ID", Type: Scalar, ComponentType: UnsignedShort, Data: []float64{7, 8}},
+	}
+	got, err := doc.AddInterleaved(attrs)
+	if err != nil {
+		t.Fatalf("Document.AddInterleaved() error = %v", err)
+	}
+	if diff := deep.Equal(got, Attribute{"POSITION": 0, "COLOR_0": 1, "TEXCOORD_0": 2, "_ID": 3}); diff != nil {
+		t.Errorf("Document.AddInterleaved() = %v", diff)
+	}
+	wantView := BufferView{ByteOffset: 4, ByteLength: 48, ByteStride: 24, Target: ArrayBuffer}
+	if diff := deep.Equal(doc.BufferViews, []BufferView{wantView}); diff != nil {
+		t.Errorf("Document.AddInterleaved() bufferViews = %v", diff)
+	}
+	wantAccessors := []Accessor{
+		{BufferView: Index(0), ComponentType: Float, Count: 2, Type: Vec3, Min: []float64{-1, 2, 0}, Max: []float64{1, 5, 3}},
+		{BufferView: Index(0), ByteOffset: 12, ComponentType: UnsignedByte, Normalized: true, Count: 2, Type: Vec3},
+		{BufferView: Index(0), ByteOffset: 16, ComponentType: Short, Normalized: true, Count: 2, Type: Vec2},
+		{BufferView: Index(0), ByteOffset: 20, ComponentType: UnsignedShort, Count: 2, Type: Scalar},
+	}
+	if diff := deep.Equal(doc.Accessors, wantAccessors); diff != nil {
+		t.Errorf("Document.AddInterleaved() accessors = %v", diff)
+	}
+	wantValues := [][]float64{{1, 2, 3, -1, 5, 0}, {0, 128.0 / 255, 1, 1, 1, 1}, {-1, 1, 0, 16384.0 / 32767}, {7, 8}}
+	for i, want := range wantValues {
+		values, err := doc.ReadAccessor(uint32(i))
+		if err != nil {
+			t.Fatalf("Document.ReadAccessor() error = %v", err)
+		}
+		if diff := deep.Equal(values, want); diff != nil {
+			t.Errorf("Document.AddInterleaved() values of %s = %v", attrs[i].Semantic, diff)
+		}
+	}
+
+	quantized := new(Document)
+	if _, err = quantized.AddInterleaved([]InterleavedAttribute{
+		{Semantic: "POSITION", Type: Vec2, ComponentType: Short, Normalized: true, Data: []float64{-2, 0.25, 0.5, 0.75}},
+	}); err != nil {
+		t.Fatalf("Document.AddInterleaved() error = %v", err)
+	}
+	acc := quantized.Accessors[0]
+	if diff := deep.Equal([][]float64{acc.Min, acc.Max}, [][]float64{{-32767, 8192}, {16384, 24575}}); diff != nil {
+		t.Errorf("Document.AddInterleaved() quantized bounds = %v", diff)
+	}
+	noBounds := new(Document)
+	if _, err = noBounds.AddInterleaved([]InterleavedAttribute{
+		{Semantic: "POSITION", Type: Scalar, ComponentType: Float, Data: []float64{1, 2}, NoBounds: true},
+	}); err != nil {
+		t.Fatalf("Document.AddInterleaved() error = %v", err)
+	}
+	if acc := noBounds.Accessors[0]; acc.Min != nil || acc.Max != nil {
+		t.Errorf("Document.AddInterleaved() set bounds with NoBounds, got %v %v", acc.Min, acc.Max)
+	}
+
+	errTests := []struct {
+		name  string
+		attrs []InterleavedAttribute
+	}{
+		{"empty", nil},
+		{"noVertices", []InterleavedAttribute{{Semantic: "POSITION", Type: Vec3}}},
+		{"partialElement", []InterleavedAttribute{{Semantic: "POSITION", Type: Vec3, Data: []float64{1, 2}}}},
+		{"count", []InterleavedAttribute{{Semantic: "A", Data: []float64{1, 2}}, {Semantic: "B", Data: []float64{1}}}},
+		{"duplicated", []InterleavedAttribute{{Semantic: "A", Data: []float64{1}}, {Semantic: "A", Data: []float64{1}}}},
+		{"stride", []InterleavedAttribute{{Semantic: "A", Type: Mat4, Data: make([]float64, 16)}, {Semantic: "B", Type: Mat4, Data: make([]float64, 16)},
+			{Semantic: "C", Type: Mat4, Data: make([]float64, 16)}, {Semantic: "D", Type: Mat4, Data: make([]float64, 16)}}},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := new(Document).AddInterleaved(tt.attrs); err == nil {
+				t.Error("Document.AddInterleaved() expected error")
+			}
+		})
+	}
+}
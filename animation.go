@@ -0,0 +1,282 @@
+package gltf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// An AnimationTarget identifies the node property animated by a channel.
+type AnimationTarget struct {
+	Node uint32
+	Path TRSProperty
+}
+
+// ChannelsByNode groups the indices of the animation channels by their target node,
+// keeping the order in which they are defined, so all the channels that affect a node can be applied in one pass.
+// Channels without target node, such as the ones targeting a JSON pointer, are not included.
+func (a *Animation) ChannelsByNode() map[uint32][]uint32 {
+	nodes := make(map[uint32][]uint32)
+	for i, channel := range a.Channels {
+		if channel.Target.Node != nil && channel.Target.Path != Pointer {
+			nodes[*channel.Target.Node] = append(nodes[*channel.Target.Node], uint32(i))
+		}
+	}
+	return nodes
+}
+
+// ChannelsByTarget indexes the animation channels by their target node and path.
+// When several channels have the same target, which is not allowed by the spec, the first one is indexed.
+// Channels without target node, such as the ones targeting a JSON pointer, are not included.
+func (a *Animation) ChannelsByTarget() map[AnimationTarget]uint32 {
+	targets := make(map[AnimationTarget]uint32)
+	for i, channel := range a.Channels {
+		if channel.Target.Node == nil || channel.Target.Path == Pointer {
+			continue
+		}
+		target := AnimationTarget{Node: *channel.Target.Node, Path: channel.Target.Path}
+		if _, ok := targets[target]; !ok {
+			targets[target] = uint32(i)
+		}
+	}
+	return targets
+}
+
+// ResampleSampler replaces the keyframes of a sampler of the animation at animationIndex
+// with keyframes evaluated at a fixed rate, in samples per second, and stored with the given interpolation.
+// If rate is 0 the original keyframe times are kept, which converts the sampler to the new interpolation.
+// Rotations are interpolated with spherical linear interpolation and normalized.
+// When the new interpolation is CUBICSPLINE the tangents are estimated from the neighbour keyframes.
+// The new input and output accessors are stored as floats in the first buffer, which is created if needed,
+// and the previous ones are left untouched.
+func (d *Document) ResampleSampler(animationIndex, samplerIndex uint32, rate float64, interpolation Interpolation) error {
+	if int(animationIndex) >= len(d.Animations) {
+		return fmt.Errorf("gltf: animation index %d out of range", animationIndex)
+	}
+	anim := &d.Animations[animationIndex]
+	if int(samplerIndex) >= len(anim.Samplers) {
+		return fmt.Errorf("gltf: animation sampler index %d out of range", samplerIndex)
+	}
+	sampler := &anim.Samplers[samplerIndex]
+	if sampler.Input == nil || sampler.Output == nil {
+		return errors.New("gltf: animation sampler without input or output")
+	}
+	if rate < 0 {
+		return errors.New("gltf: negative sampling rate")
+	}
+	times, err := d.ReadAccessor(*sampler.Input)
+	if err != nil {
+		return err
+	}
+	values, err := d.ReadAccessor(*sampler.Output)
+	if err != nil {
+		return err
+	}
+	elements := len(times)
+	if sampler.Interpolation == CubicSpline {
+		elements *= 3
+	}
+	if len(times) == 0 || len(values)%elements != 0 {
+		return errors.New("gltf: animation sampler output does not match its input")
+	}
+	track := keyframes{
+		times:         times,
+		values:        values,
+		n:             len(values) / elements,
+		interpolation: sampler.Interpolation,
+	}
+	for _, channel := range anim.Channels {
+		if channel.Sampler != nil && *channel.Sampler == samplerIndex && channel.Target.Path == Rotation {
+			track.rotation = true
+		}
+	}
+	newTimes := times
+	if rate > 0 {
+		start, end := times[0], times[len(times)-1]
+		count := int(math.Floor((end-start)*rate+1e-9)) + 1
+		newTimes = make([]float64, count)
+		for i := range newTimes {
+			newTimes[i] = start + float64(i)/rate
+		}
+		// Always finish at the last keyframe.
+		if end-newTimes[count-1] > 1e-9 {
+			newTimes = append(newTimes, end)
+		} else {
+			newTimes[count-1] = end
+		}
+	}
+	newValues := make([]float64, 0, len(newTimes)*track.n)
+	for _, t := range newTimes {
+		newValues = append(newValues, track.sample(t)...)
+	}
+	if interpolation == CubicSpline {
+		newValues = cubicSplineOutput(newTimes, newValues, track.n)
+	}
+	accessorType := d.Accessors[*sampler.Output].Type
+	input, err := d.addFloatAccessor(newTimes, Scalar, true)
+	if err != nil {
+		return err
+	}
+	output, err := d.addFloatAccessor(newValues, accessorType, false)
+	if err != nil {
+		return err
+	}
+	sampler.Input, sampler.Output, sampler.Interpolation = Index(input), Index(output), interpolation
+	return nil
+}
+
+// keyframes evaluates an animation sampler.
+type keyframes struct {
+	times         []float64
+	values        []float64
+	n             int // Number of components of each keyframe value.
+	interpolation Interpolation
+	rotation      bool
+}
+
+// value returns the value of the keyframe k,
+// or one of its tangents when element is 0 or 2 and the interpolation is CUBICSPLINE.
+func (kf *keyframes) value(k, element int) []float64 {
+	i := k
+	if kf.interpolation == CubicSpline {
+		i = 3*k + element
+	}
+	return kf.values[i*kf.n : (i+1)*kf.n]
+}
+
+// sample returns the value of the track at time t, clamped to the range of the keyframes.
+func (kf *keyframes) sample(t float64) []float64 {
+	last := len(kf.times) - 1
+	k := sort.SearchFloat64s(kf.times, t)
+	if k <= last && kf.times[k] == t || k == 0 {
+		return append([]float64(nil), kf.value(k, 1)...)
+	}
+	if k > last {
+		return append([]float64(nil), kf.value(last, 1)...)
+	}
+	k--
+	dt := kf.times[k+1] - kf.times[k]
+	s := (t - kf.times[k]) / dt
+	out := make([]float64, kf.n)
+	switch kf.interpolation {
+	case Step:
+		copy(out, kf.value(k, 1))
+	case CubicSpline:
+		v0, b0, v1, a1 := kf.value(k, 1), kf.value(k, 2), kf.value(k+1, 1), kf.value(k+1, 0)
+		s2, s3 := s*s, s*s*s
+		for i := range out {
+			out[i] = (2*s3-3*s2+1)*v0[i] + (s3-2*s2+s)*dt*b0[i] + (-2*s3+3*s2)*v1[i] + (s3-s2)*dt*a1[i]
+		}
+		if kf.rotation && kf.n == 4 {
+			normalize(out)
+		}
+	default:
+		v0, v1 := kf.value(k, 1), kf.value(k+1, 1)
+		if kf.rotation && kf.n == 4 {
+			return slerp(v0, v1, s)
+		}
+		for i := range out {
+			out[i] = v0[i] + (v1[i]-v0[i])*s
+		}
+	}
+	return out
+}
+
+// cubicSplineOutput returns the output of a CUBICSPLINE sampler that goes through the given values,
+// with the tangents estimated with finite differences.
+func cubicSplineOutput(times, values []float64, n int) []float64 {
+	out := make([]float64, 0, 3*len(values))
+	last := len(times) - 1
+	for k := range times {
+		prev, next := k-1, k+1
+		if prev < 0 {
+			prev = k
+		}
+		if next > last {
+			next = k
+		}
+		tangent := make([]float64, n)
+		if dt := times[next] - times[prev]; dt > 0 {
+			for i := range tangent {
+				tangent[i] = (values[next*n+i] - values[prev*n+i]) / dt
+			}
+		}
+		out = append(out, tangent...)
+		out = append(out, values[k*n:(k+1)*n]...)
+		out = append(out, tangent...)
+	}
+	return out
+}
+
+// slerp returns the spherical linear interpolation between the unit quaternions a and b, following the shortest path.
+func slerp(a, b []float64, s float64) []float64 {
+	dot := a[0]*b[0] + a[1]*b[1] + a[2]*b[2] + a[3]*b[3]
+	sign := 1.0
+	if dot < 0 {
+		dot, sign = -dot, -1
+	}
+	wa, wb := 1-s, s*sign
+	// Fall back to linear interpolation when the quaternions are too close.
+	if dot < 0.9995 {
+		theta := math.Acos(dot)
+		sin := math.Sin(theta)
+		wa, wb = math.Sin((1-s)*theta)/sin, math.Sin(s*theta)/sin*sign
+	}
+	out := make([]float64, 4)
+	for i := range out {
+		out[i] = wa*a[i] + wb*b[i]
+	}
+	normalize(out)
+	return out
+}
+
+// normalize scales v to unit length. A zero vector is not modified.
+func normalize(v []float64) {
+	var sum float64
+	for _, c := range v {
+		sum += c * c
+	}
+	if sum == 0 {
+		return
+	}
+	l := math.Sqrt(sum)
+	for i := range v {
+		v[i] /= l
+	}
+}
+
+// addFloatAccessor writes the values as floats in a new bufferView of the first buffer,
+// which is created if the document does not have any, and appends an accessor of the given type that references them.
+// The accessor declares the bounds of the values when bounds is true.
+func (d *Document) addFloatAccessor(values []float64, accessorType AccessorType, bounds bool) (uint32, error) {
+	n := int(accessorType.Components())
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(float32(v)))
+	}
+	if len(d.Buffers) == 0 {
+		d.Buffers = append(d.Buffers, Buffer{})
+	}
+	view, err := d.appendBufferView(0, data, 0, None)
+	if err != nil {
+		return 0, err
+	}
+	acc := Accessor{BufferView: Index(view), ComponentType: Float, Count: uint32(len(values) / n), Type: accessorType}
+	if bounds && len(values) > 0 {
+		acc.Min = make([]float64, n)
+		acc.Max = make([]float64, n)
+		for i, v := range values {
+			c := i % n
+			if v32 := float64(float32(v)); i < n || v32 < acc.Min[c] {
+				acc.Min[c] = v32
+			}
+			if v32 := float64(float32(v)); i < n || v32 > acc.Max[c] {
+				acc.Max[c] = v32
+			}
+		}
+	}
+	d.Accessors = append(d.Accessors, acc)
+	return uint32(len(d.Accessors) - 1), nil
+}
@@ -0,0 +1,105 @@
+package gltf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func newSamplerDoc(interpolation Interpolation, path TRSProperty, times, values []float64, accessorType AccessorType) *Document {
+	doc := new(Document)
+	input, _ := doc.addFloatAccessor(times, Scalar, true)
+	output, _ := doc.addFloatAccessor(values, accessorType, false)
+	doc.Animations = []Animation{{
+		Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: path}}},
+		Samplers: []AnimationSampler{{Input: Index(input), Output: Index(output), Interpolation: interpolation}},
+	}}
+	return doc
+}
+
+func TestAnimation_Channels(t *testing.T) {
+	anim := &Animation{Channels: []Channel{
+		{Target: ChannelTarget{Node: Index(1), Path: Rotation}},
+		{Target: ChannelTarget{Node: Index(0), Path: Translation}},
+		{Target: ChannelTarget{Path: Pointer}},
+		{Target: ChannelTarget{Node: Index(1), Path: Translation}},
+		{Target: ChannelTarget{Node: Index(1), Path: Rotation}},
+	}}
+	wantNodes := map[uint32][]uint32{0: {1}, 1: {0, 3, 4}}
+	if diff := deep.Equal(anim.ChannelsByNode(), wantNodes); diff != nil {
+		t.Errorf("Animation.ChannelsByNode() = %v", diff)
+	}
+	wantTargets := map[AnimationTarget]uint32{
+		{Node: 0, Path: Translation}: 1,
+		{Node: 1, Path: Rotation}:    0,
+		{Node: 1, Path: Translation}: 3,
+	}
+	if diff := deep.Equal(anim.ChannelsByTarget(), wantTargets); diff != nil {
+		t.Errorf("Animation.ChannelsByTarget() = %v", diff)
+	}
+}
+
+func TestDocument_ResampleSampler(t *testing.T) {
+	near := func(a, b []float64) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if math.Abs(a[i]-b[i]) > 1e-6 {
+				return false
+			}
+		}
+		return true
+	}
+	s45, c45 := math.Sin(math.Pi/8), math.Cos(math.Pi/8)
+	s90 := math.Sqrt2 / 2
+	tests := []struct {
+		name          string
+		doc           *Document
+		rate          float64
+		interpolation Interpolation
+		wantTimes     []float64
+		wantValues    []float64
+		wantErr       bool
+	}{
+		{"slerp", newSamplerDoc(Linear, Rotation, []float64{0, 1}, []float64{0, 0, 0, 1, 0, 0, s90, s90}, Vec4), 2, Linear,
+			[]float64{0, 0.5, 1}, []float64{0, 0, 0, 1, 0, 0, s45, c45, 0, 0, s90, s90}, false},
+		{"lerp", newSamplerDoc(Linear, Translation, []float64{0, 1}, []float64{0, 0, 0, 2, 4, 6}, Vec3), 4, Step,
+			[]float64{0, 0.25, 0.5, 0.75, 1}, []float64{0, 0, 0, 0.5, 1, 1.5, 1, 2, 3, 1.5, 3, 4.5, 2, 4, 6}, false},
+		{"lastKeyframe", newSamplerDoc(Step, Scale, []float64{0, 0.5}, []float64{1, 1, 1, 2, 2, 2}, Vec3), 3, Linear,
+			[]float64{0, 1.0 / 3, 0.5}, []float64{1, 1, 1, 1, 1, 1, 2, 2, 2}, false},
+		{"fromCubicSpline", newSamplerDoc(CubicSpline, Translation, []float64{0, 1}, []float64{9, 0, 9, 9, 2, 9}, Scalar), 0, Linear,
+			[]float64{0, 1}, []float64{0, 2}, false},
+		{"toCubicSpline", newSamplerDoc(Linear, Weights, []float64{0, 1, 2}, []float64{0, 1, 1, 0, 2, 1}, Scalar), 0, CubicSpline,
+			[]float64{0, 1, 2}, []float64{1, -1, 0, 1, 1, -1, 1, 0, 1, 0, 1, 0, 1, 1, 2, 1, 1, 1}, false},
+		{"mismatch", newSamplerDoc(CubicSpline, Translation, []float64{0, 1}, []float64{0, 1}, Scalar), 0, Linear, nil, nil, true},
+		{"negativeRate", newSamplerDoc(Linear, Translation, []float64{0, 1}, []float64{0, 1}, Scalar), -1, Linear, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.ResampleSampler(0, 0, tt.rate, tt.interpolation)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.ResampleSampler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			sampler := tt.doc.Animations[0].Samplers[0]
+			if sampler.Interpolation != tt.interpolation {
+				t.Errorf("Document.ResampleSampler() interpolation = %v, want %v", sampler.Interpolation, tt.interpolation)
+			}
+			times, _ := tt.doc.ReadAccessor(*sampler.Input)
+			values, _ := tt.doc.ReadAccessor(*sampler.Output)
+			if !near(times, tt.wantTimes) {
+				t.Errorf("Document.ResampleSampler() times = %v, want %v", times, tt.wantTimes)
+			}
+			if !near(values, tt.wantValues) {
+				t.Errorf("Document.ResampleSampler() values = %v, want %v", values, tt.wantValues)
+			}
+		})
+	}
+	if err := new(Document).ResampleSampler(0, 0, 1, Linear); err == nil {
+		t.Error("Document.ResampleSampler() expected error")
+	}
+}
@@ -0,0 +1,97 @@
+package animpointer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/qmuntal/gltf"
+)
+
+const (
+	// ExtAnimationPointer defines the AnimationPointer unique key.
+	ExtAnimationPointer = "KHR_animation_pointer"
+)
+
+// New returns a new animpointer.AnimationPointer.
+func New() json.Unmarshaler {
+	return new(AnimationPointer)
+}
+
+func init() {
+	gltf.RegisterExtension(ExtAnimationPointer, New)
+}
+
+// AnimationPointer defines the property animated by a channel whose target path is gltf.Pointer.
+type AnimationPointer struct {
+	Pointer string `json:"pointer" validate:"required"`
+}
+
+// UnmarshalJSON unmarshal the animation pointer.
+func (p *AnimationPointer) UnmarshalJSON(data []byte) error {
+	type alias AnimationPointer
+	return json.Unmarshal(data, (*alias)(p))
+}
+
+// Resolve returns the value of the document property referenced by the pointer.
+func (p *AnimationPointer) Resolve(doc *gltf.Document) (reflect.Value, error) {
+	return Resolve(doc, p.Pointer)
+}
+
+// Resolve returns the document property referenced by a JSON pointer, i.e. /materials/0/pbrMetallicRoughness/metallicFactor.
+// The returned value can be modified using reflection, unless the property is a map value.
+// Struct fields are matched by their JSON name, or by their position if they don't have one,
+// as colors are encoded as arrays.
+func Resolve(doc *gltf.Document, pointer string) (reflect.Value, error) {
+	v := reflect.ValueOf(doc).Elem()
+	if pointer == "" {
+		return v, nil
+	}
+	if pointer[0] != '/' {
+		return reflect.Value{}, fmt.Errorf("gltf: invalid JSON pointer %q", pointer)
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, fmt.Errorf("gltf: JSON pointer %q references an undefined property", pointer)
+			}
+			v = v.Elem()
+		}
+		var err error
+		if v, err = child(v, token); err != nil {
+			return reflect.Value{}, fmt.Errorf("gltf: JSON pointer %q cannot be resolved: %v", pointer, err)
+		}
+	}
+	return v, nil
+}
+
+func child(v reflect.Value, token string) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.SplitN(t.Field(i).Tag.Get("json"), ",", 2)[0]
+			if name == token || (name == "" && strconv.Itoa(i) == token) {
+				return v.Field(i), nil
+			}
+		}
+		return reflect.Value{}, fmt.Errorf("property %q not found", token)
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= v.Len() {
+			return reflect.Value{}, fmt.Errorf("index %q out of range", token)
+		}
+		return v.Index(i), nil
+	case reflect.Map:
+		e := v.MapIndex(reflect.ValueOf(token))
+		if !e.IsValid() {
+			return reflect.Value{}, fmt.Errorf("key %q not found", token)
+		}
+		return e, nil
+	}
+	return reflect.Value{}, errors.New("a basic type has no properties")
+}
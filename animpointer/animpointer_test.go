@@ -0,0 +1,90 @@
+package animpointer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/qmuntal/gltf"
+)
+
+func TestAnimationPointer_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    *AnimationPointer
+		wantErr bool
+	}{
+		{"default", []byte("{}"), new(AnimationPointer), false},
+		{"pointer", []byte(`{"pointer": "/nodes/0/rotation"}`), &AnimationPointer{Pointer: "/nodes/0/rotation"}, false},
+		{"invalid", []byte(`{"pointer": 1}`), new(AnimationPointer), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(AnimationPointer)
+			if err := got.UnmarshalJSON(tt.data); (err != nil) != tt.wantErr {
+				t.Errorf("AnimationPointer.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AnimationPointer.UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	doc := &gltf.Document{
+		Nodes: []gltf.Node{{Rotation: [4]float64{0, 0, 0, 1}}},
+		Materials: []gltf.Material{{
+			PBRMetallicRoughness: &gltf.PBRMetallicRoughness{BaseColorFactor: &gltf.RGBA{R: 1, G: 0.5, B: 0.25, A: 1}, MetallicFactor: gltf.Float64(0.5)},
+			Extensions:           gltf.Extensions{"a/b": &AnimationPointer{Pointer: "/"}},
+		}},
+	}
+	tests := []struct {
+		name    string
+		pointer string
+		want    interface{}
+		wantErr bool
+	}{
+		{"rotation", "/nodes/0/rotation", [4]float64{0, 0, 0, 1}, false},
+		{"rotationW", "/nodes/0/rotation/3", float64(1), false},
+		{"metallic", "/materials/0/pbrMetallicRoughness/metallicFactor", float64(0.5), false},
+		{"color", "/materials/0/pbrMetallicRoughness/baseColorFactor/2", float64(0.25), false},
+		{"extension", "/materials/0/extensions/a~1b/pointer", "/", false},
+		{"relative", "nodes/0", nil, true},
+		{"outOfRange", "/nodes/1/rotation", nil, true},
+		{"unknown", "/nodes/0/other", nil, true},
+		{"undefined", "/materials/0/normalTexture/index", nil, true},
+		{"basic", "/nodes/0/rotation/0/x", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(doc, tt.pointer)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Kind() == reflect.Ptr {
+				got = got.Elem()
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.want) {
+				t.Errorf("Resolve() = %v, want %v", got.Interface(), tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve_Set(t *testing.T) {
+	doc := &gltf.Document{Nodes: []gltf.Node{{}}}
+	v, err := (&AnimationPointer{Pointer: "/nodes/0/translation/1"}).Resolve(doc)
+	if err != nil {
+		t.Fatalf("AnimationPointer.Resolve() error = %v", err)
+	}
+	v.SetFloat(2)
+	if doc.Nodes[0].Translation[1] != 2 {
+		t.Errorf("AnimationPointer.Resolve() = %v, want 2", doc.Nodes[0].Translation)
+	}
+}
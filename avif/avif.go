@@ -0,0 +1,40 @@
+package avif
+
+import (
+	"encoding/json"
+
+	"github.com/qmuntal/gltf"
+)
+
+const (
+	// ExtTextureAVIF defines the TextureAVIF unique key.
+	ExtTextureAVIF = "EXT_texture_avif"
+	// MimeType is the mime type of the AVIF images.
+	MimeType = "image/avif"
+)
+
+// New returns a new avif.TextureAVIF.
+func New() json.Unmarshaler {
+	return new(TextureAVIF)
+}
+
+func init() {
+	gltf.RegisterExtension(ExtTextureAVIF, New)
+}
+
+// TextureAVIF defines a texture whose image is encoded in the AVIF format.
+// The core texture source can be used as a fallback for clients that do not support it.
+type TextureAVIF struct {
+	Source uint32 `json:"source"`
+}
+
+// ImageIndex returns the index of the AVIF image.
+func (t *TextureAVIF) ImageIndex() uint32 {
+	return t.Source
+}
+
+// UnmarshalJSON unmarshal the texture extension.
+func (t *TextureAVIF) UnmarshalJSON(data []byte) error {
+	type alias TextureAVIF
+	return json.Unmarshal(data, (*alias)(t))
+}
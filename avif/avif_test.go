@@ -0,0 +1,54 @@
+package avif
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/qmuntal/gltf"
+)
+
+func TestTextureAVIF_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    *TextureAVIF
+		wantErr bool
+	}{
+		{"default", []byte("{}"), new(TextureAVIF), false},
+		{"source", []byte(`{"source": 2}`), &TextureAVIF{Source: 2}, false},
+		{"invalid", []byte(`{"source": "a"}`), new(TextureAVIF), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(TextureAVIF)
+			if err := got.UnmarshalJSON(tt.data); (err != nil) != tt.wantErr {
+				t.Errorf("TextureAVIF.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TextureAVIF.UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextureAVIF_Decode(t *testing.T) {
+	data := []byte(`{"asset": {"version": "2.0"},
+	"images": [{"uri": "a.png"}, {"uri": "a.avif", "mimeType": "image/avif"}],
+	"textures": [{"source": 0, "extensions": {"EXT_texture_avif": {"source": 1}}}]}`)
+	doc := new(gltf.Document)
+	if err := gltf.NewDecoder(bytes.NewReader(data), nil).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	tex := &doc.Textures[0]
+	if want := (&TextureAVIF{Source: 1}); !reflect.DeepEqual(tex.Extensions[ExtTextureAVIF], want) {
+		t.Errorf("Decoder.Decode() = %v, want %v", tex.Extensions[ExtTextureAVIF], want)
+	}
+	if got := tex.ResolveSource(ExtTextureAVIF); *got != 1 {
+		t.Errorf("Texture.ResolveSource() = %v, want 1", *got)
+	}
+	if got := tex.ResolveSource(); *got != 0 {
+		t.Errorf("Texture.ResolveSource() = %v, want 0", *got)
+	}
+}
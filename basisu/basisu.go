@@ -0,0 +1,41 @@
+package basisu
+
+import (
+	"encoding/json"
+
+	"github.com/qmuntal/gltf"
+)
+
+const (
+	// ExtTextureBasisU defines the TextureBasisU unique key.
+	ExtTextureBasisU = "KHR_texture_basisu"
+	// MimeType is the mime type of the KTX2 images.
+	MimeType = "image/ktx2"
+)
+
+// New returns a new basisu.TextureBasisU.
+func New() json.Unmarshaler {
+	return new(TextureBasisU)
+}
+
+func init() {
+	gltf.RegisterExtension(ExtTextureBasisU, New)
+}
+
+// TextureBasisU defines a texture whose image is a KTX2 container with Basis Universal supercompression,
+// which can be transcoded to the GPU compressed formats supported by each client.
+// The core texture source is optional and can be used as a fallback for clients that do not support it.
+type TextureBasisU struct {
+	Source uint32 `json:"source"`
+}
+
+// ImageIndex returns the index of the KTX2 image.
+func (t *TextureBasisU) ImageIndex() uint32 {
+	return t.Source
+}
+
+// UnmarshalJSON unmarshal the texture extension.
+func (t *TextureBasisU) UnmarshalJSON(data []byte) error {
+	type alias TextureBasisU
+	return json.Unmarshal(data, (*alias)(t))
+}
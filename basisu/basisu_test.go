@@ -0,0 +1,57 @@
+package basisu
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/qmuntal/gltf"
+)
+
+func TestTextureBasisU_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    *TextureBasisU
+		wantErr bool
+	}{
+		{"default", []byte("{}"), new(TextureBasisU), false},
+		{"source", []byte(`{"source": 2}`), &TextureBasisU{Source: 2}, false},
+		{"invalid", []byte(`{"source": "a"}`), new(TextureBasisU), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(TextureBasisU)
+			if err := got.UnmarshalJSON(tt.data); (err != nil) != tt.wantErr {
+				t.Errorf("TextureBasisU.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TextureBasisU.UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextureBasisU_Decode(t *testing.T) {
+	data := []byte(`{"asset": {"version": "2.0"},
+	"images": [{"uri": "a.png"}, {"uri": "a.ktx2", "mimeType": "image/ktx2"}],
+	"textures": [{"source": 0, "extensions": {"KHR_texture_basisu": {"source": 1}}}, {"extensions": {"KHR_texture_basisu": {"source": 1}}}]}`)
+	doc := new(gltf.Document)
+	if err := gltf.NewDecoder(bytes.NewReader(data), nil).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	tex := &doc.Textures[0]
+	if want := (&TextureBasisU{Source: 1}); !reflect.DeepEqual(tex.Extensions[ExtTextureBasisU], want) {
+		t.Errorf("Decoder.Decode() = %v, want %v", tex.Extensions[ExtTextureBasisU], want)
+	}
+	if got := tex.ResolveSource(ExtTextureBasisU); *got != 1 {
+		t.Errorf("Texture.ResolveSource() = %v, want 1", *got)
+	}
+	if got := tex.ResolveSource(); *got != 0 {
+		t.Errorf("Texture.ResolveSource() = %v, want 0", *got)
+	}
+	if got := doc.Textures[1].ResolveSource(); got != nil {
+		t.Errorf("Texture.ResolveSource() = %v, want nil", *got)
+	}
+}
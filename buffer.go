@@ -0,0 +1,290 @@
+package gltf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// appendBufferView appends data at the end of the buffer, aligned to 4 bytes,
+// and adds a new bufferView pointing to it. The return value is the index of the new bufferView.
+func (d *Document) appendBufferView(bufferIndex uint32, data []byte, byteStride uint32, target Target) (uint32, error) {
+	if int(bufferIndex) >= len(d.Buffers) {
+		return 0, errors.New("gltf: buffer index out of range")
+	}
+	buffer := &d.Buffers[bufferIndex]
+	if uint32(len(buffer.Data)) != buffer.ByteLength {
+		return 0, errors.New("gltf: buffer data not loaded")
+	}
+	if uint64(buffer.ByteLength)+3+uint64(len(data)) > math.MaxUint32 {
+		return 0, errors.New("gltf: buffer length overflow")
+	}
+	offset := padding4(buffer.ByteLength)
+	buffer.Data = append(buffer.Data, make([]byte, offset-buffer.ByteLength)...)
+	buffer.Data = append(buffer.Data, data...)
+	buffer.ByteLength = uint32(len(buffer.Data))
+	if buffer.IsEmbeddedResource() {
+		buffer.EmbeddedResource()
+	}
+	d.BufferViews = append(d.BufferViews, BufferView{
+		Buffer:     bufferIndex,
+		ByteOffset: offset,
+		ByteLength: uint32(len(data)),
+		ByteStride: byteStride,
+		Target:     target,
+	})
+	return uint32(len(d.BufferViews) - 1), nil
+}
+
+// CheckBufferLengths checks that the data of every loaded buffer has exactly ByteLength bytes,
+// so accessor reads cannot go past a truncated buffer. Buffers without data are not checked.
+// The returned error lists all the mismatches.
+func (d *Document) CheckBufferLengths() error {
+	var mismatches []string
+	for i, buffer := range d.Buffers {
+		if buffer.Data != nil && uint32(len(buffer.Data)) != buffer.ByteLength {
+			mismatches = append(mismatches, fmt.Sprintf("buffers[%d] has %d bytes, want %d", i, len(buffer.Data), buffer.ByteLength))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("gltf: buffer data length mismatch: %s", strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+// AlignBufferViews inserts zero padding in the buffers so every bufferView starts at a 4-byte boundary,
+// which satisfies the alignment required by any component type, and updates the bufferView offsets accordingly.
+// Padding is also inserted inside the bufferViews so every accessor, sparse indices and sparse values
+// start at a multiple of their component size, updating their byteOffsets and the bufferView lengths.
+// Buffers whose bufferViews and accessors are already aligned are not modified.
+// It fails if a buffer that has to be padded has not been loaded,
+// if a misaligned bufferView overlaps a preceding one, or if a misaligned accessor overlaps a preceding accessor
+// or lies in a bufferView that overlaps another one, as the padding would break the overlapped data.
+func (d *Document) AlignBufferViews() error {
+	for i := range d.Buffers {
+		for j := range d.BufferViews {
+			if int(d.BufferViews[j].Buffer) == i {
+				if err := d.alignAccessors(j); err != nil {
+					return err
+				}
+			}
+		}
+		var views []int
+		aligned := true
+		for j, bv := range d.BufferViews {
+			if int(bv.Buffer) == i {
+				views = append(views, j)
+				aligned = aligned && bv.ByteOffset%4 == 0
+			}
+		}
+		if aligned {
+			continue
+		}
+		buffer := &d.Buffers[i]
+		if uint32(len(buffer.Data)) != buffer.ByteLength {
+			return errors.New("gltf: buffer data not loaded")
+		}
+		sort.SliceStable(views, func(a, b int) bool {
+			return d.BufferViews[views[a]].ByteOffset < d.BufferViews[views[b]].ByteOffset
+		})
+		var (
+			data             []byte
+			copied, end, pad uint32
+		)
+		for _, j := range views {
+			bv := &d.BufferViews[j]
+			if bv.ByteOffset > buffer.ByteLength {
+				return errors.New("gltf: bufferView out of buffer bounds")
+			}
+			if (bv.ByteOffset+pad)%4 != 0 {
+				if bv.ByteOffset < end {
+					return errors.New("gltf: misaligned bufferView overlaps another bufferView")
+				}
+				data = append(data, buffer.Data[copied:bv.ByteOffset]...)
+				copied = bv.ByteOffset
+				n := 4 - (bv.ByteOffset+pad)%4
+				data = append(data, make([]byte, n)...)
+				pad += n
+			}
+			if bv.ByteOffset+bv.ByteLength > end {
+				end = bv.ByteOffset + bv.ByteLength
+			}
+			bv.ByteOffset += pad
+		}
+		buffer.Data = append(data, buffer.Data[copied:]...)
+		buffer.ByteLength = uint32(len(buffer.Data))
+		if buffer.IsEmbeddedResource() {
+			buffer.EmbeddedResource()
+		}
+	}
+	return nil
+}
+
+// viewRegion is a range of a bufferView read by an accessor, which has to start at a multiple of align.
+type viewRegion struct {
+	offset        *uint32
+	length, align uint32
+}
+
+// viewRegions returns the ranges of the given bufferView read by the accessors and their sparse storage.
+func (d *Document) viewRegions(view int) []viewRegion {
+	var regions []viewRegion
+	for i := range d.Accessors {
+		acc := &d.Accessors[i]
+		size := acc.ComponentType.ByteSize()
+		elem := elementSize(acc.ComponentType, acc.Type)
+		if acc.BufferView != nil && int(*acc.BufferView) == view {
+			length := acc.Count * elem
+			if stride := d.BufferViews[view].ByteStride; stride > 0 && acc.Count > 0 {
+				length = (acc.Count-1)*stride + elem
+			}
+			regions = append(regions, viewRegion{&acc.ByteOffset, length, size})
+		}
+		if sp := acc.Sparse; sp != nil {
+			if int(sp.Indices.BufferView) == view {
+				indexSize := sp.Indices.ComponentType.ByteSize()
+				regions = append(regions, viewRegion{&sp.Indices.ByteOffset, sp.Count * indexSize, indexSize})
+			}
+			if int(sp.Values.BufferView) == view {
+				regions = append(regions, viewRegion{&sp.Values.ByteOffset, sp.Count * elem, size})
+			}
+		}
+	}
+	return regions
+}
+
+// alignAccessors inserts zero padding inside the given bufferView so every region read by an accessor
+// starts at a multiple of its component size, and shifts the bufferViews that follow it in the buffer.
+func (d *Document) alignAccessors(view int) error {
+	regions := d.viewRegions(view)
+	aligned := true
+	for _, r := range regions {
+		aligned = aligned && *r.offset%r.align == 0
+	}
+	if aligned {
+		return nil
+	}
+	bv := &d.BufferViews[view]
+	buffer := &d.Buffers[bv.Buffer]
+	if uint32(len(buffer.Data)) != buffer.ByteLength {
+		return errors.New("gltf: buffer data not loaded")
+	}
+	viewEnd, ok := addUint32(bv.ByteOffset, bv.ByteLength)
+	if !ok || viewEnd > buffer.ByteLength {
+		return errors.New("gltf: bufferView out of buffer bounds")
+	}
+	for j, other := range d.BufferViews {
+		if j != view && other.Buffer == bv.Buffer && other.ByteOffset < viewEnd && other.ByteOffset+other.ByteLength > bv.ByteOffset {
+			return errors.New("gltf: misaligned accessor in a bufferView that overlaps another bufferView")
+		}
+	}
+	sort.SliceStable(regions, func(a, b int) bool {
+		return *regions[a].offset < *regions[b].offset
+	})
+	src := buffer.Data[bv.ByteOffset:viewEnd]
+	var (
+		data             []byte
+		copied, end, pad uint32
+	)
+	for _, r := range regions {
+		if *r.offset > bv.ByteLength {
+			return errors.New("gltf: accessor out of bufferView bounds")
+		}
+		if (*r.offset+pad)%r.align != 0 {
+			if *r.offset < end {
+				return errors.New("gltf: misaligned accessor overlaps another accessor")
+			}
+			data = append(data, src[copied:*r.offset]...)
+			copied = *r.offset
+			n := r.align - (*r.offset+pad)%r.align
+			data = append(data, make([]byte, n)...)
+			pad += n
+		}
+		if *r.offset+r.length > end {
+			end = *r.offset + r.length
+		}
+		*r.offset += pad
+	}
+	data = append(data, src[copied:]...)
+	buffer.Data = append(append(append([]byte(nil), buffer.Data[:bv.ByteOffset]...), data...), buffer.Data[viewEnd:]...)
+	buffer.ByteLength = uint32(len(buffer.Data))
+	for j := range d.BufferViews {
+		if other := &d.BufferViews[j]; j != view && other.Buffer == bv.Buffer && other.ByteOffset >= viewEnd {
+			other.ByteOffset += pad
+		}
+	}
+	bv.ByteLength += pad
+	if buffer.IsEmbeddedResource() {
+		buffer.EmbeddedResource()
+	}
+	return nil
+}
+
+// RemoveEmptyBufferViews removes the bufferViews with a byteLength of 0 that are not used by any accessor or image,
+// and updates the indices of the remaining ones. It returns the number of bufferViews removed.
+// Validate reports every empty bufferView, as the schema requires a byteLength of at least 1,
+// so this is the way to repair documents written by tools that leave unreferenced empty views.
+// Indices stored inside extensions are not tracked, so nothing is removed when any primitive has extensions,
+// and bufferViews with their own extensions are always kept.
+func (d *Document) RemoveEmptyBufferViews() int {
+	for _, mesh := range d.Meshes {
+		for _, prim := range mesh.Primitives {
+			if len(prim.Extensions) > 0 {
+				return 0
+			}
+		}
+	}
+	used := d.usedBufferViews()
+	keep := make([]bool, len(d.BufferViews))
+	var removed int
+	for i, bv := range d.BufferViews {
+		keep[i] = used[i] || bv.ByteLength > 0 || len(bv.Extensions) > 0
+		if !keep[i] {
+			removed++
+		}
+	}
+	if removed > 0 {
+		d.compact(kindBufferView, keep)
+	}
+	return removed
+}
+
+// usedBufferViews reports which bufferViews are referenced by an accessor, including its sparse storage, or an image.
+func (d *Document) usedBufferViews() []bool {
+	used := make([]bool, len(d.BufferViews))
+	d.walkReferences(func(kind elementKind, index *uint32) {
+		if kind == kindBufferView && int(*index) < len(used) {
+			used[*index] = true
+		}
+	})
+	return used
+}
+
+// AccessorsForBufferView returns the indices of the accessors that use the bufferView,
+// either as their own storage or as sparse indices or values, in ascending order.
+func (d *Document) AccessorsForBufferView(index uint32) []uint32 {
+	var accessors []uint32
+	for i, acc := range d.Accessors {
+		used := acc.BufferView != nil && *acc.BufferView == index
+		if sp := acc.Sparse; sp != nil {
+			used = used || sp.Indices.BufferView == index || sp.Values.BufferView == index
+		}
+		if used {
+			accessors = append(accessors, uint32(i))
+		}
+	}
+	return accessors
+}
+
+// BufferViewsForBuffer returns the indices of the bufferViews stored in the buffer, in ascending order.
+func (d *Document) BufferViewsForBuffer(index uint32) []uint32 {
+	var views []uint32
+	for i, bv := range d.BufferViews {
+		if bv.Buffer == index {
+			views = append(views, uint32(i))
+		}
+	}
+	return views
+}
@@ -0,0 +1,232 @@
+package gltf
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDocument_AlignBufferViews(t *testing.T) {
+	data := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	tests := []struct {
+		name      string
+		doc       *Document
+		wantViews []BufferView
+		wantData  []byte
+		wantErr   bool
+	}{
+		{"aligned", &Document{
+			BufferViews: []BufferView{{ByteOffset: 0, ByteLength: 4}, {ByteOffset: 4, ByteLength: 6}},
+			Buffers:     []Buffer{{ByteLength: 10}},
+		}, []BufferView{{ByteOffset: 0, ByteLength: 4}, {ByteOffset: 4, ByteLength: 6}}, nil, false},
+		{"padded", &Document{
+			BufferViews: []BufferView{{ByteOffset: 8, ByteLength: 2}, {ByteOffset: 0, ByteLength: 3}, {ByteOffset: 3, ByteLength: 4}},
+			Buffers:     []Buffer{{ByteLength: 10, Data: append([]byte(nil), data...)}},
+		}, []BufferView{{ByteOffset: 12, ByteLength: 2}, {ByteOffset: 0, ByteLength: 3}, {ByteOffset: 4, ByteLength: 4}},
+			[]byte{0, 1, 2, 0, 3, 4, 5, 6, 7, 0, 0, 0, 8, 9}, false},
+		{"notLoaded", &Document{
+			BufferViews: []BufferView{{ByteOffset: 3, ByteLength: 4}},
+			Buffers:     []Buffer{{ByteLength: 10}},
+		}, nil, nil, true},
+		{"overlap", &Document{
+			BufferViews: []BufferView{{ByteOffset: 0, ByteLength: 8}, {ByteOffset: 2, ByteLength: 2}},
+			Buffers:     []Buffer{{ByteLength: 10, Data: append([]byte(nil), data...)}},
+		}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.AlignBufferViews()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.AlignBufferViews() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := deep.Equal(tt.doc.BufferViews, tt.wantViews); diff != nil {
+				t.Errorf("Document.AlignBufferViews() = %v", diff)
+			}
+			if tt.wantData != nil {
+				if diff := deep.Equal(tt.doc.Buffers[0].Data, tt.wantData); diff != nil {
+					t.Errorf("Document.AlignBufferViews() = %v", diff)
+				}
+				if tt.doc.Buffers[0].ByteLength != uint32(len(tt.wantData)) {
+					t.Errorf("Document.AlignBufferViews() byteLength = %d, want %d", tt.doc.Buffers[0].ByteLength, len(tt.wantData))
+				}
+			}
+		})
+	}
+}
+
+func TestDocument_AlignBufferViews_Accessors(t *testing.T) {
+	tests := []struct {
+		name          string
+		doc           *Document
+		wantViews     []BufferView
+		wantAccessors []Accessor
+		wantData      []byte
+		wantErr       bool
+	}{
+		{"padded", &Document{
+			Accessors: []Accessor{
+				{BufferView: Index(0), ComponentType: UnsignedByte, Count: 3, Type: Scalar},
+				{BufferView: Index(0), ByteOffset: 3, ComponentType: UnsignedShort, Count: 2, Type: Scalar},
+			},
+			BufferViews: []BufferView{{ByteLength: 7}, {ByteOffset: 7, ByteLength: 3}},
+			Buffers:     []Buffer{{ByteLength: 10, Data: []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}}},
+		}, []BufferView{{ByteLength: 8}, {ByteOffset: 8, ByteLength: 3}}, []Accessor{
+			{BufferView: Index(0), ComponentType: UnsignedByte, Count: 3, Type: Scalar},
+			{BufferView: Index(0), ByteOffset: 4, ComponentType: UnsignedShort, Count: 2, Type: Scalar},
+		}, []byte{0, 1, 2, 0, 3, 4, 5, 6, 7, 8, 9}, false},
+		{"sparse", &Document{
+			Accessors: []Accessor{{ComponentType: Float, Count: 1, Type: Scalar, Sparse: &Sparse{
+				Count:   1,
+				Indices: SparseIndices{ByteOffset: 1, ComponentType: UnsignedShort},
+				Values:  SparseValues{ByteOffset: 3},
+			}}},
+			BufferViews: []BufferView{{ByteLength: 7}},
+			Buffers:     []Buffer{{ByteLength: 7, Data: []byte{0, 1, 2, 3, 4, 5, 6}}},
+		}, []BufferView{{ByteLength: 8}}, []Accessor{{ComponentType: Float, Count: 1, Type: Scalar, Sparse: &Sparse{
+			Count:   1,
+			Indices: SparseIndices{ByteOffset: 2, ComponentType: UnsignedShort},
+			Values:  SparseValues{ByteOffset: 4},
+		}}}, []byte{0, 0, 1, 2, 3, 4, 5, 6}, false},
+		{"overlapAccessor", &Document{
+			Accessors: []Accessor{
+				{BufferView: Index(0), ComponentType: UnsignedByte, Count: 4, Type: Scalar},
+				{BufferView: Index(0), ByteOffset: 1, ComponentType: UnsignedShort, Count: 1, Type: Scalar},
+			},
+			BufferViews: []BufferView{{ByteLength: 4}},
+			Buffers:     []Buffer{{ByteLength: 4, Data: make([]byte, 4)}},
+		}, nil, nil, nil, true},
+		{"overlapView", &Document{
+			Accessors:   []Accessor{{BufferView: Index(0), ByteOffset: 1, ComponentType: UnsignedShort, Count: 1, Type: Scalar}},
+			BufferViews: []BufferView{{ByteLength: 4}, {ByteOffset: 2, ByteLength: 2}},
+			Buffers:     []Buffer{{ByteLength: 4, Data: make([]byte, 4)}},
+		}, nil, nil, nil, true},
+		{"notLoaded", &Document{
+			Accessors:   []Accessor{{BufferView: Index(0), ByteOffset: 1, ComponentType: UnsignedShort, Count: 1, Type: Scalar}},
+			BufferViews: []BufferView{{ByteLength: 4}},
+			Buffers:     []Buffer{{ByteLength: 4}},
+		}, nil, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.AlignBufferViews()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.AlignBufferViews() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := deep.Equal(tt.doc.BufferViews, tt.wantViews); diff != nil {
+				t.Errorf("Document.AlignBufferViews() views = %v", diff)
+			}
+			if diff := deep.Equal(tt.doc.Accessors, tt.wantAccessors); diff != nil {
+				t.Errorf("Document.AlignBufferViews() accessors = %v", diff)
+			}
+			if diff := deep.Equal(tt.doc.Buffers[0].Data, tt.wantData); diff != nil {
+				t.Errorf("Document.AlignBufferViews() data = %v", diff)
+			}
+			if tt.doc.Buffers[0].ByteLength != uint32(len(tt.wantData)) {
+				t.Errorf("Document.AlignBufferViews() byteLength = %d, want %d", tt.doc.Buffers[0].ByteLength, len(tt.wantData))
+			}
+		})
+	}
+}
+
+func TestDocument_CheckBufferLengths(t *testing.T) {
+	tests := []struct {
+		name    string
+		buffers []Buffer
+		wantErr bool
+	}{
+		{"empty", nil, false},
+		{"notLoaded", []Buffer{{ByteLength: 4}}, false},
+		{"loaded", []Buffer{{ByteLength: 2, Data: []byte{1, 2}}}, false},
+		{"short", []Buffer{{ByteLength: 2, Data: []byte{1, 2}}, {ByteLength: 4, Data: []byte{1}}}, true},
+		{"long", []Buffer{{ByteLength: 1, Data: []byte{1, 2}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{Buffers: tt.buffers}
+			if err := doc.CheckBufferLengths(); (err != nil) != tt.wantErr {
+				t.Errorf("Document.CheckBufferLengths() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDocument_RemoveEmptyBufferViews(t *testing.T) {
+	doc := &Document{
+		Accessors: []Accessor{{BufferView: Index(3), ComponentType: Float, Count: 1, Type: Scalar}},
+		Images:    []Image{{BufferView: 1}},
+		BufferViews: []BufferView{
+			{},
+			{ByteLength: 4},
+			{Extensions: Extensions{"EXT_foo": nil}},
+			{ByteLength: 4},
+			{},
+		},
+	}
+	if got := doc.RemoveEmptyBufferViews(); got != 2 {
+		t.Errorf("Document.RemoveEmptyBufferViews() = %d, want 2", got)
+	}
+	if len(doc.BufferViews) != 3 {
+		t.Fatalf("Document.RemoveEmptyBufferViews() left %d bufferViews, want 3", len(doc.BufferViews))
+	}
+	if doc.Images[0].BufferView != 0 || *doc.Accessors[0].BufferView != 2 {
+		t.Errorf("Document.RemoveEmptyBufferViews() indices = %d, %d, want 0, 2", doc.Images[0].BufferView, *doc.Accessors[0].BufferView)
+	}
+
+	doc = &Document{
+		Meshes:      []Mesh{{Primitives: []Primitive{{Extensions: Extensions{"EXT_foo": nil}}}}},
+		BufferViews: []BufferView{{}},
+	}
+	if got := doc.RemoveEmptyBufferViews(); got != 0 || len(doc.BufferViews) != 1 {
+		t.Errorf("Document.RemoveEmptyBufferViews() = %d, want 0 with primitive extensions", got)
+	}
+}
+
+func TestDocument_AccessorsForBufferView(t *testing.T) {
+	doc := &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0)},
+			{BufferView: Index(1)},
+			{Sparse: &Sparse{Indices: SparseIndices{BufferView: 2}, Values: SparseValues{BufferView: 0}}},
+			{},
+		},
+	}
+	tests := []struct {
+		index uint32
+		want  []uint32
+	}{
+		{0, []uint32{0, 2}},
+		{1, []uint32{1}},
+		{2, []uint32{2}},
+		{3, nil},
+	}
+	for _, tt := range tests {
+		if got := doc.AccessorsForBufferView(tt.index); deep.Equal(got, tt.want) != nil {
+			t.Errorf("Document.AccessorsForBufferView(%d) = %v, want %v", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestDocument_BufferViewsForBuffer(t *testing.T) {
+	doc := &Document{BufferViews: []BufferView{{Buffer: 1}, {Buffer: 0}, {Buffer: 1}}}
+	tests := []struct {
+		index uint32
+		want  []uint32
+	}{
+		{0, []uint32{1}},
+		{1, []uint32{0, 2}},
+		{2, nil},
+	}
+	for _, tt := range tests {
+		if got := doc.BufferViewsForBuffer(tt.index); deep.Equal(got, tt.want) != nil {
+			t.Errorf("Document.BufferViewsForBuffer(%d) = %v, want %v", tt.index, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package gltf
+
+import "sort"
+
+// Canonicalize normalizes the document so the same logical asset is always represented by the same values,
+// and therefore re-encoding it always produces byte-identical output:
+//   - extensionsUsed and extensionsRequired are sorted and deduplicated.
+//   - properties with a default value are set to it explicitly, as the decoder does.
+//   - embedded buffers are encoded again from their data.
+//
+// Attributes and extensions are maps, which are always marshalled with their keys sorted.
+func (d *Document) Canonicalize() {
+	d.ExtensionsUsed = sortedUnique(d.ExtensionsUsed)
+	d.ExtensionsRequired = sortedUnique(d.ExtensionsRequired)
+	for i := range d.Nodes {
+		node := &d.Nodes[i]
+		node.Matrix = node.MatrixOrDefault()
+		node.Rotation = node.RotationOrDefault()
+		node.Scale = node.ScaleOrDefault()
+	}
+	for i := range d.Materials {
+		mat := &d.Materials[i]
+		if mat.AlphaCutoff == nil {
+			mat.AlphaCutoff = Float64(0.5)
+		}
+		if pbr := mat.PBRMetallicRoughness; pbr != nil {
+			if pbr.BaseColorFactor == nil {
+				pbr.BaseColorFactor = NewRGBA()
+			}
+			if pbr.MetallicFactor == nil {
+				pbr.MetallicFactor = Float64(1)
+			}
+			if pbr.RoughnessFactor == nil {
+				pbr.RoughnessFactor = Float64(1)
+			}
+		}
+		if mat.NormalTexture != nil && mat.NormalTexture.Scale == nil {
+			mat.NormalTexture.Scale = Float64(1)
+		}
+		if mat.OcclusionTexture != nil && mat.OcclusionTexture.Strength == nil {
+			mat.OcclusionTexture.Strength = Float64(1)
+		}
+	}
+	for i := range d.Buffers {
+		buffer := &d.Buffers[i]
+		if buffer.IsEmbeddedResource() && uint32(len(buffer.Data)) == buffer.ByteLength {
+			buffer.EmbeddedResource()
+		}
+	}
+}
+
+// sortedUnique returns the values sorted in increasing order without duplicates.
+func sortedUnique(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	out := append([]string(nil), values...)
+	sort.Strings(out)
+	n := 1
+	for i := 1; i < len(out); i++ {
+		if out[i] != out[n-1] {
+			out[n] = out[i]
+			n++
+		}
+	}
+	return out[:n]
+}
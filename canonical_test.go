@@ -0,0 +1,83 @@
+package gltf
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDocument_Canonicalize(t *testing.T) {
+	doc := &Document{
+		Asset:              Asset{Version: "2.0"},
+		ExtensionsUsed:     []string{"b", "a", "b"},
+		ExtensionsRequired: []string{"b"},
+		Nodes:              []Node{{Name: "n"}, {Rotation: [4]float64{0, 1, 0, 0}}},
+		Materials: []Material{{
+			PBRMetallicRoughness: &PBRMetallicRoughness{RoughnessFactor: Float64(0.5)},
+			NormalTexture:        &NormalTexture{Index: Index(0)},
+			OcclusionTexture:     &OcclusionTexture{Index: Index(0), Strength: Float64(0.5)},
+		}},
+		Buffers: []Buffer{{ByteLength: 3, Data: []byte{1, 2, 3}, URI: "data:application/octet-stream;base64,AAAA"}},
+	}
+	want := &Document{
+		Asset:              Asset{Version: "2.0"},
+		ExtensionsUsed:     []string{"a", "b"},
+		ExtensionsRequired: []string{"b"},
+		Nodes: []Node{
+			{Name: "n", Matrix: DefaultMatrix, Rotation: DefaultRotation, Scale: DefaultScale},
+			{Matrix: DefaultMatrix, Rotation: [4]float64{0, 1, 0, 0}, Scale: DefaultScale},
+		},
+		Materials: []Material{{
+			AlphaCutoff:          Float64(0.5),
+			PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorFactor: NewRGBA(), MetallicFactor: Float64(1), RoughnessFactor: Float64(0.5)},
+			NormalTexture:        &NormalTexture{Index: Index(0), Scale: Float64(1)},
+			OcclusionTexture:     &OcclusionTexture{Index: Index(0), Strength: Float64(0.5)},
+		}},
+		Buffers: []Buffer{{ByteLength: 3, Data: []byte{1, 2, 3}, URI: "data:application/octet-stream;base64,AQID"}},
+	}
+	doc.Canonicalize()
+	if diff := deep.Equal(doc, want); diff != nil {
+		t.Errorf("Document.Canonicalize() = %v", diff)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := NewEncoder(buf, nil, false).Encode(doc); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	decoded := new(Document)
+	if err := NewDecoder(bytes.NewReader(buf.Bytes()), nil).Decode(decoded); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	decoded.Canonicalize()
+	if diff := deep.Equal(decoded, doc); diff != nil {
+		t.Errorf("Document.Canonicalize() roundtrip = %v", diff)
+	}
+	buf2 := new(bytes.Buffer)
+	if err := NewEncoder(buf2, nil, false).Encode(decoded); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), buf2.Bytes()) {
+		t.Errorf("Document.Canonicalize() output = %s, want %s", buf2, buf)
+	}
+}
+
+func Test_sortedUnique(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   []string
+	}{
+		{"nil", nil, nil},
+		{"empty", []string{}, nil},
+		{"one", []string{"a"}, []string{"a"}},
+		{"sorted", []string{"c", "a", "c", "b", "a"}, []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := deep.Equal(sortedUnique(tt.values), tt.want); diff != nil {
+				t.Errorf("sortedUnique() = %v", diff)
+			}
+		})
+	}
+}
@@ -67,6 +67,17 @@ func (c *ComponentType) MarshalJSON() ([]byte, error) {
 	}[*c])
 }
 
+// ByteSize returns the size of a component in bytes.
+func (c ComponentType) ByteSize() uint32 {
+	switch c {
+	case Byte, UnsignedByte:
+		return 1
+	case Short, UnsignedShort:
+		return 2
+	}
+	return 4
+}
+
 // AccessorType specifies if the attribute is a scalar, vector, or matrix.
 type AccessorType uint8
 
@@ -118,6 +129,23 @@ func (a *AccessorType) MarshalJSON() ([]byte, error) {
 	}[*a])
 }
 
+// Components returns the number of components of an element of this type.
+func (a AccessorType) Components() uint32 {
+	switch a {
+	case Vec2:
+		return 2
+	case Vec3:
+		return 3
+	case Vec4, Mat2:
+		return 4
+	case Mat3:
+		return 9
+	case Mat4:
+		return 16
+	}
+	return 1
+}
+
 // The Target that the GPU buffer should be bound to.
 type Target uint16
 
@@ -380,6 +408,8 @@ const (
 	Scale
 	// Weights corresponds to a weights transform.
 	Weights
+	// Pointer corresponds to a property targeted by the KHR_animation_pointer extension.
+	Pointer
 )
 
 // UnmarshalJSON unmarshal the TRSProperty with the correct default values.
@@ -392,6 +422,7 @@ func (t *TRSProperty) UnmarshalJSON(data []byte) error {
 			"rotation":    Rotation,
 			"scale":       Scale,
 			"weights":     Weights,
+			"pointer":     Pointer,
 		}[tmp]
 	}
 	return err
@@ -404,9 +435,21 @@ func (t *TRSProperty) MarshalJSON() ([]byte, error) {
 		Rotation:    "rotation",
 		Scale:       "scale",
 		Weights:     "weights",
+		Pointer:     "pointer",
 	}[*t])
 }
 
+// SupportedVersion is the latest glTF version that this package can decode.
+const SupportedVersion = "2.0"
+
+const (
+	supportedMajorVersion = 2
+	supportedMinorVersion = 0
+)
+
+// extMeshoptCompression is the key of the EXT_meshopt_compression extension, whose fallback buffers may have no data.
+const extMeshoptCompression = "EXT_meshopt_compression"
+
 const (
 	glbHeaderMagic = 0x46546c67
 	glbChunkJSON   = 0x4e4f534a
@@ -429,4 +472,6 @@ const (
 	mimetypeApplicationOctet = "data:application/octet-stream;base64"
 	mimetypeImagePNG         = "data:image/png;base64"
 	mimetypeImageJPG         = "data:image/jpeg;base64"
+	mimetypeImageAVIF        = "data:image/avif;base64"
+	mimetypeImageKTX2        = "data:image/ktx2;base64"
 )
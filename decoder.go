@@ -8,16 +8,58 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"unsafe"
 )
 
 // ReadQuotas defines maximum allocation sizes to prevent DOS's from malicious files.
+// MaxBufferCount and MaxMemoryAllocation are always enforced, so a zero value does not allow any buffer.
+// The counts of accessors, bufferViews, nodes and meshes are checked while their arrays are read,
+// before the elements are decoded, and a zero value does not limit them.
 type ReadQuotas struct {
 	MaxBufferCount      int
 	MaxMemoryAllocation int
+	MaxAccessorCount    int
+	MaxBufferViewCount  int
+	MaxNodeCount        int
+	MaxMeshCount        int
+}
+
+// exceedsMemory reports whether allocating n bytes exceeds MaxMemoryAllocation.
+func (q *ReadQuotas) exceedsMemory(n int64) bool {
+	return n > int64(q.MaxMemoryAllocation)
+}
+
+// countQuota returns the name and the value of the quota that limits the number of elements
+// of the top-level property, such as "nodes", and whether the property is limited at all.
+func (q *ReadQuotas) countQuota(property string) (string, int, bool) {
+	switch property {
+	case "buffers":
+		return "MaxBufferCount", q.MaxBufferCount, true
+	case "accessors":
+		return "MaxAccessorCount", q.MaxAccessorCount, q.MaxAccessorCount > 0
+	case "bufferViews":
+		return "MaxBufferViewCount", q.MaxBufferViewCount, q.MaxBufferViewCount > 0
+	case "nodes":
+		return "MaxNodeCount", q.MaxNodeCount, q.MaxNodeCount > 0
+	case "meshes":
+		return "MaxMeshCount", q.MaxMeshCount, q.MaxMeshCount > 0
+	}
+	return "", 0, false
+}
+
+// checkCount returns an error if n elements of the top-level property exceed its quota.
+func (q *ReadQuotas) checkCount(property string, n int) error {
+	if name, max, ok := q.countQuota(property); ok && n > max {
+		return fmt.Errorf("gltf: Quota exceeded, number of %s > %s", property, name)
+	}
+	return nil
 }
 
 // ReadResourceCallback defines a callback that will be called when an external resource should be loaded.
@@ -31,20 +73,105 @@ func Open(name string) (*Document, error) {
 	if err != nil {
 		return nil, err
 	}
-	cb := func(uri string) (io.ReadCloser, error) {
-		return os.Open(filepath.Join(filepath.Dir(name), uri))
-	}
+	cb := openResource(filepath.Dir(name))
 	doc := new(Document)
 	err = NewDecoder(f, cb).Decode(doc)
 	f.Close()
 	return doc, err
 }
 
+// Unmarshal decodes the glTF or GLB content of data, detected by its magic number, and returns the Document.
+// The external resources are loaded with cb.
+func Unmarshal(data []byte, cb ReadResourceCallback) (*Document, error) {
+	doc := new(Document)
+	if err := NewDecoder(bytes.NewReader(data), cb).Decode(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// CheckGLBLength verifies that the total length declared in the GLB header of data equals the size of the file,
+// that is the 12-byte header plus, for each chunk, its 8-byte header and its data padded to 4 bytes.
+// It also fails if data is shorter than the declared length, as in truncated files,
+// or has bytes after it, as in concatenated files.
+func CheckGLBLength(data []byte) error {
+	var header glbHeader
+	headerSize := uint32(unsafe.Sizeof(header))
+	if len(data) < int(headerSize) {
+		return errors.New("gltf: data too short for a GLB header")
+	}
+	binary.Read(bytes.NewReader(data), binary.LittleEndian, &header)
+	if header.Magic != glbHeaderMagic {
+		return errors.New("gltf: data is not a GLB file")
+	}
+	if uint64(len(data)) < uint64(header.Length) {
+		return fmt.Errorf("gltf: GLB truncated, header length is %d but there are %d bytes", header.Length, len(data))
+	}
+	if uint64(len(data)) > uint64(header.Length) {
+		return fmt.Errorf("gltf: GLB has %d bytes after the length declared in the header", uint64(len(data))-uint64(header.Length))
+	}
+	chunkHeaderSize := uint64(unsafe.Sizeof(chunkHeader{}))
+	total := uint64(headerSize) - chunkHeaderSize
+	for total < uint64(len(data)) {
+		if total+chunkHeaderSize > uint64(len(data)) {
+			return fmt.Errorf("gltf: GLB chunk header at byte %d is truncated", total)
+		}
+		length := uint64(binary.LittleEndian.Uint32(data[total:]))
+		total += chunkHeaderSize + (length+3)/4*4
+	}
+	if total != uint64(header.Length) {
+		return fmt.Errorf("gltf: GLB header length is %d but its chunks take %d bytes", header.Length, total)
+	}
+	return nil
+}
+
+// openResource returns a callback that opens the resources from the file system.
+// Relative URIs are resolved against dir. file:// URIs are rejected, as they could read any local file;
+// trusted assets that use them can be decoded registering OpenFileURI as the callback of the file scheme.
+func openResource(dir string) ReadResourceCallback {
+	return func(uri string) (io.ReadCloser, error) {
+		if uriScheme(uri) == "file" {
+			return nil, fmt.Errorf("gltf: file URIs are not allowed '%s'", uri)
+		}
+		return os.Open(filepath.Join(dir, uri))
+	}
+}
+
+// OpenFileURI is a ReadResourceCallback that opens a file:// URI as an absolute path of the local file system,
+// decoding its percent-encoding. Only URIs with an empty or localhost host are accepted.
+// It is not used by default, as any local file could be read from an untrusted asset,
+// so it has to be enabled with Decoder.SetSchemeCallback("file", OpenFileURI).
+func OpenFileURI(uri string) (io.ReadCloser, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(u.Scheme, "file") {
+		return nil, fmt.Errorf("gltf: not a file URI '%s'", uri)
+	}
+	if u.Host != "" && !strings.EqualFold(u.Host, "localhost") {
+		return nil, fmt.Errorf("gltf: file URI with a remote host '%s'", uri)
+	}
+	p := u.Path
+	// Windows paths have the form /C:/path.
+	if len(p) > 2 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+	return os.Open(filepath.FromSlash(p))
+}
+
 // A Decoder reads and decodes glTF and GLB values from an input stream.
 type Decoder struct {
-	r      *bufio.Reader
-	cb     ReadResourceCallback
-	quotas ReadQuotas
+	r            *bufio.Reader
+	cb           ReadResourceCallback
+	quotas       ReadQuotas
+	rawExtras    bool
+	useNumber    bool
+	strictColors bool
+	bufferFilter func(int, *Buffer) bool
+	binLength    uint32 // Bytes declared in the GLB header after the JSON chunk not read yet.
+	chunks       map[uint32][]byte
+	schemes      map[string]ReadResourceCallback
 }
 
 // NewDecoder returns a new decoder that reads from r.
@@ -55,6 +182,10 @@ func NewDecoder(r io.Reader, cb ReadResourceCallback) *Decoder {
 		quotas: ReadQuotas{
 			MaxBufferCount:      8,
 			MaxMemoryAllocation: 32 * 1024 * 1024,
+			MaxAccessorCount:    1 << 20,
+			MaxBufferViewCount:  1 << 20,
+			MaxNodeCount:        1 << 20,
+			MaxMeshCount:        1 << 20,
 		}}
 }
 
@@ -64,55 +195,366 @@ func (d *Decoder) SetQuotas(quotas ReadQuotas) *Decoder {
 	return d
 }
 
+// SetSchemeCallback registers cb to load the external resources whose URI has the given scheme,
+// such as "s3" for s3://bucket/a.bin. Schemes are case insensitive.
+// Resources with other schemes and relative URIs are loaded with the callback passed to NewDecoder.
+// A nil cb removes the scheme registration. The return value is the same decoder.
+func (d *Decoder) SetSchemeCallback(scheme string, cb ReadResourceCallback) *Decoder {
+	scheme = strings.ToLower(scheme)
+	if cb == nil {
+		delete(d.schemes, scheme)
+		return d
+	}
+	if d.schemes == nil {
+		d.schemes = make(map[string]ReadResourceCallback)
+	}
+	d.schemes[scheme] = cb
+	return d
+}
+
+// resourceCallback returns the callback used to load the resource with the given URI.
+func (d *Decoder) resourceCallback(uri string) ReadResourceCallback {
+	if cb, ok := d.schemes[uriScheme(uri)]; ok {
+		return cb
+	}
+	return d.cb
+}
+
+// uriScheme returns the lower case scheme of an absolute URI, or an empty string if uri is relative.
+// Single letter schemes are not considered, as they are Windows drive letters.
+func uriScheme(uri string) string {
+	for i, c := range uri {
+		switch {
+		case c == ':':
+			if i < 2 {
+				return ""
+			}
+			return strings.ToLower(uri[:i])
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case i > 0 && ('0' <= c && c <= '9' || c == '+' || c == '-' || c == '.'):
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// SetRawExtras instructs the decoder to store the extras of all the document properties
+// as json.RawMessage instead of decoding them into maps, slices and basic types,
+// so they can be decoded on demand into custom types and encoded again with the exact same formatting.
+// The return value is the same decoder.
+func (d *Decoder) SetRawExtras(raw bool) *Decoder {
+	d.rawExtras = raw
+	return d
+}
+
+// SetUseNumber instructs the decoder to store the numbers inside extras as json.Number instead of float64,
+// so integers keep their precision and numbers are encoded again exactly as they were written.
+// It has no effect when the raw extras mode is set.
+// The return value is the same decoder.
+func (d *Decoder) SetUseNumber(useNumber bool) *Decoder {
+	d.useNumber = useNumber
+	return d
+}
+
+// SetStrictColors instructs the decoder to fail when a material color factor has a component out of the [0, 1] range,
+// returning a ValidationErrors that points to every offending component.
+// Colors stored inside extensions are not checked.
+// The return value is the same decoder.
+func (d *Decoder) SetStrictColors(strict bool) *Decoder {
+	d.strictColors = strict
+	return d
+}
+
+// SetBufferFilter sets a function that decides, for each buffer, whether its data is loaded.
+// Buffers for which fn returns false keep a nil Data, as when the resource callback returns a nil reader,
+// and the BIN chunk of a GLB is skipped without being read into memory.
+// A nil fn, the default, loads all the buffers. The return value is the same decoder.
+func (d *Decoder) SetBufferFilter(fn func(index int, buffer *Buffer) bool) *Decoder {
+	d.bufferFilter = fn
+	return d
+}
+
+// loadBuffer reports whether the data of the buffer at index has to be loaded.
+func (d *Decoder) loadBuffer(index int, buffer *Buffer) bool {
+	return d.bufferFilter == nil || d.bufferFilter(index, buffer)
+}
+
 // Decode reads the next JSON-encoded value from its
 // input and stores it in the value pointed to by doc.
+// To reuse a document between calls, clear it first with Document.Reset.
 func (d *Decoder) Decode(doc *Document) error {
 	isBinary, err := d.decodeDocument(doc)
 	if err != nil {
 		return err
 	}
-	if len(doc.Buffers) > d.quotas.MaxBufferCount {
-		return errors.New("gltf: Quota exceeded, number of buffer > MaxBufferCount")
+	return d.decodeBuffers(doc, isBinary)
+}
+
+// ExtraChunks returns the GLB chunks found after the BIN chunk by the last call to Decode, indexed by chunk type.
+// The data of each chunk includes the padding added to align it to 4 bytes.
+// It is nil if the input was not a GLB or it had no extra chunks.
+func (d *Decoder) ExtraChunks() map[uint32][]byte {
+	return d.chunks
+}
+
+// decodeBuffers checks the decoded document and loads the data of its buffers.
+func (d *Decoder) decodeBuffers(doc *Document, isBinary bool) error {
+	if err := validateVersion(doc.Asset); err != nil {
+		return err
 	}
 
 	var externalBufferIndex = 0
-	if isBinary && len(doc.Buffers) > 0 {
+	// The first buffer of a GLB may use a URI instead of the BIN chunk.
+	if isBinary && len(doc.Buffers) > 0 && doc.Buffers[0].URI == "" {
 		externalBufferIndex = 1
-		if err := d.decodeBinaryBuffer(&doc.Buffers[0]); err != nil {
+		if err := d.decodeBinaryBuffer(&doc.Buffers[0], d.loadBuffer(0, &doc.Buffers[0])); err != nil {
 			return err
 		}
 	}
 	for i := externalBufferIndex; i < len(doc.Buffers); i++ {
+		if !d.loadBuffer(i, &doc.Buffers[i]) {
+			continue
+		}
 		if err := d.decodeBuffer(&doc.Buffers[i]); err != nil {
 			return err
 		}
 	}
+	if err := doc.CheckBufferLengths(); err != nil {
+		return err
+	}
+	if isBinary {
+		return d.decodeExtraChunks()
+	}
+	return nil
+}
+
+// decodeExtraChunks reads the chunks that follow the BIN chunk until the end of the GLB.
+// JSON and BIN chunks found there are discarded.
+func (d *Decoder) decodeExtraChunks() error {
+	headerSize := uint32(unsafe.Sizeof(chunkHeader{}))
+	for d.binLength >= headerSize {
+		header, err := d.chunkHeader()
+		if err != nil {
+			return err
+		}
+		d.binLength -= headerSize
+		if header.Length > d.binLength {
+			return errors.New("gltf: Invalid GLB chunk length")
+		}
+		if d.quotas.exceedsMemory(int64(header.Length)) {
+			return errors.New("gltf: Quota exceeded, bytes of chunk > MaxMemoryAllocation")
+		}
+		data, err := readData(d.r, header.Length)
+		if err != nil {
+			return err
+		}
+		d.binLength -= header.Length
+		if header.Type == glbChunkJSON || header.Type == glbChunkBIN {
+			continue
+		}
+		if d.chunks == nil {
+			d.chunks = make(map[uint32][]byte)
+		}
+		d.chunks[header.Type] = data
+	}
 	return nil
 }
 
 func (d *Decoder) decodeDocument(doc *Document) (bool, error) {
-	glbHeader, err := d.readGLBHeader()
+	jd, lr, err := d.jsonDecoder()
 	if err != nil {
 		return false, err
 	}
-	var (
-		jd       *json.Decoder
-		isBinary bool
-	)
-	if glbHeader != nil {
-		jd = json.NewDecoder(&io.LimitedReader{R: d.r, N: int64(glbHeader.JSONHeader.Length)})
-		isBinary = true
-	} else {
-		jd = json.NewDecoder(d.r)
-		isBinary = false
+	err = d.decodeProperties(jd, doc, nil)
+	if err == nil {
+		err = d.checkColors(doc)
+	}
+	if err == nil {
+		err = skipJSONChunk(lr)
 	}
+	return lr != nil, err
+}
 
-	err = jd.Decode(doc)
-	if err == nil && len(doc.Buffers) > d.quotas.MaxBufferCount {
-		err = errors.New("gltf: Quota exceeded, number of buffer > MaxBufferCount")
+// checkColors returns the material colors of doc out of the [0, 1] range if the strict colors mode is set.
+func (d *Decoder) checkColors(doc *Document) error {
+	if !d.strictColors {
+		return nil
 	}
+	var errs ValidationErrors
+	doc.validateColors(&errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
 
-	return isBinary, err
+// decodeProperties decodes the JSON object read by jd into doc property by property,
+// so the quotas on the number of elements are checked while the arrays are read, before the elements are allocated.
+// The elements of the arrays with a handler are passed to it instead of being stored in doc.
+// Unknown properties are skipped and the rest are matched to the document fields as encoding/json does.
+func (d *Decoder) decodeProperties(jd *json.Decoder, doc *Document, handlers map[string]streamHandler) error {
+	if err := expectDelim(jd, '{'); err != nil {
+		return err
+	}
+	v := reflect.ValueOf(doc).Elem()
+	extras := d.extrasFunc()
+	for jd.More() {
+		tok, err := jd.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		field, ok := documentField(v, key)
+		switch handler, stream := handlers[key]; {
+		case stream:
+			err = d.streamArray(jd, key, handler)
+		case !ok:
+			var raw json.RawMessage
+			err = jd.Decode(&raw)
+		case field.Kind() == reflect.Slice:
+			err = d.decodeArray(jd, key, field)
+		case field.Type() == emptyInterfaceType && extras != nil:
+			var raw json.RawMessage
+			if err = jd.Decode(&raw); err == nil {
+				doc.Extras, err = extras(raw)
+			}
+		default:
+			err = d.decodeValue(jd, field.Addr().Interface())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return expectDelim(jd, '}')
+}
+
+// documentField returns the field of doc that stores the top-level property key,
+// preferring an exact match of the JSON name over a case-insensitive one as encoding/json does.
+func documentField(doc reflect.Value, key string) (reflect.Value, bool) {
+	t := doc.Type()
+	match := -1
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == key {
+			return doc.Field(i), true
+		}
+		if match < 0 && strings.EqualFold(name, key) {
+			match = i
+		}
+	}
+	if match < 0 {
+		return reflect.Value{}, false
+	}
+	return doc.Field(match), true
+}
+
+// decodeArray decodes the JSON array of the top-level property key into the slice field element by element,
+// checking the quota of the property before each element is allocated.
+// As encoding/json does, the capacity of the slice is reused and null leaves a nil slice.
+func (d *Decoder) decodeArray(jd *json.Decoder, key string, field reflect.Value) error {
+	tok, err := jd.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("gltf: property %s is not an array", key)
+	}
+	if field.IsNil() {
+		field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+	}
+	field.SetLen(0)
+	for n := 1; jd.More(); n++ {
+		if err = d.quotas.checkCount(key, n); err != nil {
+			return err
+		}
+		if n <= field.Cap() {
+			field.SetLen(n)
+		} else {
+			field.Set(reflect.Append(field, reflect.Zero(field.Type().Elem())))
+		}
+		elem := field.Index(n - 1)
+		elem.Set(reflect.Zero(elem.Type()))
+		if err = d.decodeValue(jd, elem.Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	return expectDelim(jd, ']')
+}
+
+// streamArray passes each element of the JSON array of the top-level property key to handler,
+// checking the quota of the property before each element is decoded.
+func (d *Decoder) streamArray(jd *json.Decoder, key string, handler streamHandler) error {
+	if err := expectDelim(jd, '['); err != nil {
+		return fmt.Errorf("gltf: property %s is not an array", key)
+	}
+	for i := uint32(0); jd.More(); i++ {
+		if err := d.quotas.checkCount(key, int(i)+1); err != nil {
+			return err
+		}
+		if err := handler(jd, i); err != nil {
+			return err
+		}
+	}
+	return expectDelim(jd, ']')
+}
+
+// extrasFunc returns the function that converts the raw extras as configured by SetRawExtras and SetUseNumber,
+// or nil if the extras are decoded as any other value.
+func (d *Decoder) extrasFunc() func(json.RawMessage) (interface{}, error) {
+	if d.rawExtras {
+		return func(raw json.RawMessage) (interface{}, error) {
+			return append(json.RawMessage(nil), raw...), nil
+		}
+	}
+	if d.useNumber {
+		return func(raw json.RawMessage) (interface{}, error) {
+			var v interface{}
+			jd := json.NewDecoder(bytes.NewReader(raw))
+			jd.UseNumber()
+			err := jd.Decode(&v)
+			return v, err
+		}
+	}
+	return nil
+}
+
+// decodeValue decodes the next JSON value of jd into v,
+// storing the extras as configured by SetRawExtras and SetUseNumber.
+func (d *Decoder) decodeValue(jd *json.Decoder, v interface{}) error {
+	if fn := d.extrasFunc(); fn != nil {
+		return decodeWithExtras(jd, v, fn)
+	}
+	return jd.Decode(v)
+}
+
+// jsonDecoder returns a decoder for the JSON content.
+// If the input is a GLB the decoder is limited to the JSON chunk, which is also returned.
+func (d *Decoder) jsonDecoder() (*json.Decoder, *io.LimitedReader, error) {
+	d.chunks = nil
+	glbHeader, err := d.readGLBHeader()
+	if err != nil {
+		return nil, nil, err
+	}
+	if glbHeader == nil {
+		return json.NewDecoder(d.r), nil, nil
+	}
+	lr := &io.LimitedReader{R: d.r, N: int64(glbHeader.JSONHeader.Length)}
+	d.binLength = glbHeader.Length - glbHeader.JSONHeader.Length - uint32(unsafe.Sizeof(*glbHeader))
+	return json.NewDecoder(lr), lr, nil
+}
+
+// skipJSONChunk discards the JSON chunk padding so the next read starts at the BIN chunk.
+func skipJSONChunk(lr *io.LimitedReader) error {
+	if lr == nil {
+		return nil
+	}
+	_, err := io.Copy(ioutil.Discard, lr)
+	return err
 }
 
 func (d *Decoder) readGLBHeader() (*glbHeader, error) {
@@ -126,20 +568,31 @@ func (d *Decoder) readGLBHeader() (*glbHeader, error) {
 	if header.Magic != glbHeaderMagic {
 		return nil, nil
 	}
-	d.r.Read(chunk)
+	// The header is already buffered, so discarding it never reads from the underlying stream.
+	if _, err = d.r.Discard(len(chunk)); err != nil {
+		return nil, err
+	}
 	return &header, d.validateGLBHeader(&header)
 }
 
 func (d *Decoder) validateGLBHeader(header *glbHeader) error {
-	if int(header.Length) > d.quotas.MaxMemoryAllocation {
+	if d.quotas.exceedsMemory(int64(header.Length)) {
 		return errors.New("gltf: Quota exceeded, bytes of glb buffer > MaxMemoryAllocation")
 	}
-	if header.JSONHeader.Type != glbChunkJSON || (header.JSONHeader.Length+uint32(unsafe.Sizeof(header))) > header.Length {
+	jsonEnd, ok := addUint32(header.JSONHeader.Length, uint32(unsafe.Sizeof(*header)))
+	if header.JSONHeader.Type != glbChunkJSON || !ok || jsonEnd > header.Length {
 		return errors.New("gltf: Invalid GLB JSON header")
 	}
 	return nil
 }
 
+// addUint32 returns a+b and whether the sum fits in an uint32,
+// so lengths read from the input can not wrap around and bypass the checks.
+func addUint32(a, b uint32) (uint32, bool) {
+	sum := a + b
+	return sum, sum >= a
+}
+
 func (d *Decoder) chunkHeader() (*chunkHeader, error) {
 	var header chunkHeader
 	if err := binary.Read(d.r, binary.LittleEndian, &header); err != nil {
@@ -149,6 +602,11 @@ func (d *Decoder) chunkHeader() (*chunkHeader, error) {
 }
 
 func (d *Decoder) decodeBuffer(buffer *Buffer) error {
+	// The fallback buffers of EXT_meshopt_compression may not have any data,
+	// it is filled by the decoder of the extension.
+	if buffer.URI == "" && isMeshoptFallback(buffer) {
+		return nil
+	}
 	if err := d.validateBuffer(buffer); err != nil {
 		return err
 	}
@@ -159,18 +617,49 @@ func (d *Decoder) decodeBuffer(buffer *Buffer) error {
 	var r io.ReadCloser
 	if buffer.IsEmbeddedResource() {
 		buffer.Data, err = buffer.marshalData()
+		// Empty data URIs decode to nil data, which CheckBufferLengths would not check.
+		if err == nil && uint32(len(buffer.Data)) != buffer.ByteLength {
+			err = fmt.Errorf("gltf: embedded buffer has %d bytes, want byteLength %d", len(buffer.Data), buffer.ByteLength)
+		}
 	} else if err = validateBufferURI(buffer.URI); err == nil {
-		r, err = d.cb(buffer.URI)
+		r, err = d.resourceCallback(buffer.URI)(buffer.URI)
 		if r != nil && err == nil {
+			// A short read is not an error here, the data length is checked once all the buffers are loaded.
+			var n int
 			buffer.Data = make([]uint8, buffer.ByteLength)
-			_, err = r.Read(buffer.Data)
+			n, err = io.ReadFull(r, buffer.Data)
+			buffer.Data = buffer.Data[:n]
+			if err == io.ErrUnexpectedEOF || err == io.EOF {
+				err = nil
+			}
 			r.Close()
 		}
 	}
 	return err
 }
 
-func (d *Decoder) decodeBinaryBuffer(buffer *Buffer) error {
+// isMeshoptFallback reports whether the buffer is flagged as fallback by the EXT_meshopt_compression extension,
+// which is only used by clients that do not support it.
+func isMeshoptFallback(buffer *Buffer) bool {
+	ext, ok := buffer.Extensions[extMeshoptCompression]
+	if !ok {
+		return false
+	}
+	data, ok := ext.(json.RawMessage)
+	if !ok {
+		// The extension has been registered with a custom type.
+		var err error
+		if data, err = json.Marshal(ext); err != nil {
+			return false
+		}
+	}
+	var meshopt struct {
+		Fallback bool `json:"fallback"`
+	}
+	return json.Unmarshal(data, &meshopt) == nil && meshopt.Fallback
+}
+
+func (d *Decoder) decodeBinaryBuffer(buffer *Buffer, load bool) error {
 	if err := d.validateBuffer(buffer); err != nil {
 		return err
 	}
@@ -181,9 +670,39 @@ func (d *Decoder) decodeBinaryBuffer(buffer *Buffer) error {
 	if header.Type != glbChunkBIN || header.Length < buffer.ByteLength {
 		return errors.New("gltf: Invalid GLB BIN header")
 	}
-	buffer.Data = make([]uint8, buffer.ByteLength)
-	_, err = d.r.Read(buffer.Data)
-	return err
+	chunkEnd, ok := addUint32(header.Length, uint32(unsafe.Sizeof(*header)))
+	if !ok || chunkEnd > d.binLength {
+		return errors.New("gltf: Invalid GLB BIN chunk length")
+	}
+	skip := header.Length
+	if load {
+		if buffer.Data, err = readData(d.r, buffer.ByteLength); err != nil {
+			return err
+		}
+		skip -= buffer.ByteLength
+	}
+	// Skip the chunk padding, or the whole chunk if it is not loaded, so the next read starts at the following chunk.
+	if _, err = io.CopyN(ioutil.Discard, d.r, int64(skip)); err != nil {
+		return err
+	}
+	d.binLength -= chunkEnd
+	return nil
+}
+
+// readData reads n bytes from r. The destination grows as the data arrives
+// so a declared length larger than the available data does not force a large allocation.
+func readData(r io.Reader, n uint32) ([]byte, error) {
+	const chunkSize = 512 * 1024
+	capacity := n
+	if capacity > chunkSize {
+		capacity = chunkSize
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, capacity))
+	_, err := io.CopyN(buf, r, int64(n))
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return buf.Bytes(), err
 }
 
 func (d *Decoder) validateBuffer(buffer *Buffer) error {
@@ -191,12 +710,56 @@ func (d *Decoder) validateBuffer(buffer *Buffer) error {
 		return errors.New("gltf: Invalid buffer.byteLength value = 0")
 	}
 
-	if int(buffer.ByteLength) > d.quotas.MaxMemoryAllocation {
+	if d.quotas.exceedsMemory(int64(buffer.ByteLength)) {
 		return errors.New("gltf: Quota exceeded, bytes of buffer > MaxMemoryAllocation")
 	}
 	return nil
 }
 
+// validateVersion checks that the asset targets a glTF version that this package can decode.
+func validateVersion(asset Asset) error {
+	if asset.Version == "" {
+		return errors.New("gltf: missing asset version")
+	}
+	major, _, ok := parseVersion(asset.Version)
+	if !ok {
+		return fmt.Errorf("gltf: malformed glTF version %s", asset.Version)
+	}
+	if major != supportedMajorVersion {
+		return fmt.Errorf("gltf: unsupported glTF version %s", asset.Version)
+	}
+	if asset.MinVersion != "" {
+		major, minor, ok := parseVersion(asset.MinVersion)
+		if !ok || major > supportedMajorVersion || (major == supportedMajorVersion && minor > supportedMinorVersion) {
+			return fmt.Errorf("gltf: unsupported glTF minimum version %s", asset.MinVersion)
+		}
+	}
+	return nil
+}
+
+// parseVersion splits a version string with the pattern <major>.<minor>, where both parts are sequences of digits.
+func parseVersion(version string) (major, minor int, ok bool) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	for i, part := range parts {
+		if part == "" || strings.TrimLeft(part, "0123456789") != "" {
+			return 0, 0, false
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, 0, false
+		}
+		if i == 0 {
+			major = n
+		} else {
+			minor = n
+		}
+	}
+	return major, minor, true
+}
+
 func validateBufferURI(uri string) error {
 	if uri == "" || strings.Contains(uri, "..") || strings.HasPrefix(uri, "/") || strings.HasPrefix(uri, "\\") {
 		return fmt.Errorf("gltf: Invalid buffer.uri value '%s'", uri)
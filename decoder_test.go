@@ -2,10 +2,17 @@ package gltf
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"io"
 	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/go-test/deep"
 )
@@ -139,6 +146,108 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+func Test_openResource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gltf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = ioutil.WriteFile(filepath.Join(dir, "my data.bin"), []byte{1, 2, 3}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	abs := filepath.ToSlash(filepath.Join(dir, "my data.bin"))
+	if !strings.HasPrefix(abs, "/") {
+		abs = "/" + abs
+	}
+	tests := []struct {
+		name    string
+		uri     string
+		want    []byte
+		wantErr bool
+	}{
+		{"relative", "my data.bin", []byte{1, 2, 3}, false},
+		{"file", "file://" + strings.Replace(abs, " ", "%20", -1), nil, true},
+		{"fileUpper", "FILE://" + strings.Replace(abs, " ", "%20", -1), nil, true},
+	}
+	cb := openResource(dir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := cb(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("openResource() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			defer r.Close()
+			got, _ := ioutil.ReadAll(r)
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("openResource() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestOpenFileURI(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gltf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = ioutil.WriteFile(filepath.Join(dir, "my data.bin"), []byte{1, 2, 3}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	abs := filepath.ToSlash(filepath.Join(dir, "my data.bin"))
+	if !strings.HasPrefix(abs, "/") {
+		abs = "/" + abs
+	}
+	abs = strings.Replace(abs, " ", "%20", -1)
+	tests := []struct {
+		name    string
+		uri     string
+		want    []byte
+		wantErr bool
+	}{
+		{"file", "file://" + abs, []byte{1, 2, 3}, false},
+		{"fileUpper", "FILE://" + abs, []byte{1, 2, 3}, false},
+		{"localhost", "file://localhost" + abs, []byte{1, 2, 3}, false},
+		{"remoteHost", "file://host" + abs, nil, true},
+		{"relative", "my%20data.bin", nil, true},
+		{"fileNotFound", "file:///not/exists.bin", nil, true},
+		{"invalidEscape", "file:///a%zz", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := OpenFileURI(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("OpenFileURI() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			defer r.Close()
+			got, _ := ioutil.ReadAll(r)
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("OpenFileURI() = %v", diff)
+			}
+		})
+	}
+
+	gltf := `{"asset": {"version": "2.0"}, "buffers": [{"byteLength": 3, "uri": "file://` + abs + `"}]}`
+	if err := NewDecoder(strings.NewReader(gltf), openResource(dir)).Decode(new(Document)); err == nil {
+		t.Error("Decoder.Decode() expected error without the file scheme callback")
+	}
+	doc := new(Document)
+	if err := NewDecoder(strings.NewReader(gltf), openResource(dir)).SetSchemeCallback("file", OpenFileURI).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	if diff := deep.Equal(doc.Buffers[0].Data, []byte{1, 2, 3}); diff != nil {
+		t.Errorf("Decoder.Decode() = %v", diff)
+	}
+}
+
 func readCallback(name string) (io.ReadCloser, error) {
 	return ioutil.NopCloser(bytes.NewBufferString("a")), nil
 }
@@ -169,6 +278,12 @@ func TestDecoder_decodeBuffer(t *testing.T) {
 	}
 }
 
+func binDecoder(binLength uint32, data []byte) *Decoder {
+	d := NewDecoder(bytes.NewBuffer(data), nil)
+	d.binLength = binLength
+	return d
+}
+
 func TestDecoder_decodeBinaryBuffer(t *testing.T) {
 	type args struct {
 		buffer *Buffer
@@ -182,11 +297,19 @@ func TestDecoder_decodeBinaryBuffer(t *testing.T) {
 		{"invalidBuffer", new(Decoder), args{&Buffer{ByteLength: 0, URI: "a.bin"}}, true},
 		{"readErr", NewDecoder(bytes.NewBufferString(""), nil), args{&Buffer{ByteLength: 1, URI: "a.bin"}}, true},
 		{"invalidHeader", NewDecoder(bytes.NewBufferString("aaaaaaaa"), nil), args{&Buffer{ByteLength: 1, URI: "a.bin"}}, true},
+		{"chunkTooLong", binDecoder(8, []byte{4, 0, 0, 0, 'B', 'I', 'N', 0, 1, 2, 3, 4}), args{&Buffer{ByteLength: 4}}, true},
+		{"chunkLengthOverflow", binDecoder(8, []byte{0xfc, 0xff, 0xff, 0xff, 'B', 'I', 'N', 0, 1, 2, 3, 4}), args{&Buffer{ByteLength: 4}}, true},
+		{"truncated", binDecoder(100, []byte{4, 0, 0, 0, 'B', 'I', 'N', 0, 1, 2}), args{&Buffer{ByteLength: 4}}, true},
+		{"base", binDecoder(12, []byte{4, 0, 0, 0, 'B', 'I', 'N', 0, 1, 2, 3, 4}), args{&Buffer{ByteLength: 4}}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.d.decodeBinaryBuffer(tt.args.buffer); (err != nil) != tt.wantErr {
+			if err := tt.d.decodeBinaryBuffer(tt.args.buffer, true); (err != nil) != tt.wantErr {
 				t.Errorf("Decoder.decodeBinaryBuffer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !bytes.Equal(tt.args.buffer.Data, []byte{1, 2, 3, 4}) {
+				t.Errorf("Decoder.decodeBinaryBuffer() data = %v", tt.args.buffer.Data)
 			}
 		})
 	}
@@ -202,14 +325,28 @@ func TestDecoder_Decode(t *testing.T) {
 		args    args
 		wantErr bool
 	}{
-		{"baseJSON", NewDecoder(bytes.NewBufferString("{\"buffers\": [{\"byteLength\": 1, \"URI\": \"a.bin\"}]}"), readCallback), args{new(Document)}, false},
+		{"baseJSON", NewDecoder(bytes.NewBufferString("{\"asset\": {\"version\": \"2.0\"}, \"buffers\": [{\"byteLength\": 1, \"URI\": \"a.bin\"}]}"), readCallback), args{new(Document)}, false},
 		{"onlyGLBHeader", NewDecoder(bytes.NewBuffer([]byte{0x67, 0x6c, 0x54, 0x46, 0x02, 0x00, 0x00, 0x00, 0x40, 0x0b, 0x00, 0x00, 0x5c, 0x06, 0x00, 0x00, 0x4a, 0x53, 0x4f, 0x4e}), readCallback), args{new(Document)}, true},
 		{"glbMaxMemory", NewDecoder(bytes.NewBuffer([]byte{0x67, 0x6c, 0x54, 0x46, 0x02, 0x00, 0x00, 0x00, 0x40, 0x0b, 0x00, 0x00, 0x5c, 0x06, 0x00, 0x00, 0x4a, 0x53, 0x4f, 0x4e}), readCallback).SetQuotas(ReadQuotas{MaxMemoryAllocation: 0}), args{new(Document)}, true},
 		{"glbNoJSONChunk", NewDecoder(bytes.NewBuffer([]byte{0x67, 0x6c, 0x54, 0x46, 0x02, 0x00, 0x00, 0x00, 0x40, 0x0b, 0x00, 0x00, 0x5c, 0x06, 0x00, 0x00, 0x4a, 0x52, 0x4f, 0x4e}), readCallback), args{new(Document)}, true},
+		{"glbJSONLengthOverflow", NewDecoder(bytes.NewBuffer([]byte{0x67, 0x6c, 0x54, 0x46, 0x02, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00, 0x00, 0xf8, 0xff, 0xff, 0xff, 0x4a, 0x53, 0x4f, 0x4e}), readCallback), args{new(Document)}, true},
 		{"empty", NewDecoder(bytes.NewBufferString(""), nil), args{new(Document)}, true},
 		{"invalidJSON", NewDecoder(bytes.NewBufferString("{asset: {}}"), nil), args{new(Document)}, true},
 		{"invalidBuffer", NewDecoder(bytes.NewBufferString("{\"buffers\": [{\"byteLength\": 0}]}"), nil), args{new(Document)}, true},
 		{"maxBuffers", NewDecoder(bytes.NewBufferString("{\"buffers\": [{\"byteLength\": 0}]}"), nil).SetQuotas(ReadQuotas{MaxBufferCount: 0}), args{new(Document)}, true},
+		{"maxAccessors", NewDecoder(bytes.NewBufferString("{\"asset\": {\"version\": \"2.0\"}, \"accessors\": [{}, {}]}"), nil).SetQuotas(ReadQuotas{MaxAccessorCount: 1}), args{new(Document)}, true},
+		{"maxNodes", NewDecoder(bytes.NewBufferString("{\"asset\": {\"version\": \"2.0\"}, \"nodes\": [{}, {}]}"), nil).SetQuotas(ReadQuotas{MaxNodeCount: 1}), args{new(Document)}, true},
+		{"unlimitedNodes", NewDecoder(bytes.NewBufferString("{\"asset\": {\"version\": \"2.0\"}, \"nodes\": [{}, {}]}"), nil).SetQuotas(ReadQuotas{}), args{new(Document)}, false},
+		{"version", NewDecoder(bytes.NewBufferString("{\"asset\": {\"version\": \"2.1\", \"minVersion\": \"2.0\"}}"), nil), args{new(Document)}, false},
+		{"caseInsensitive", NewDecoder(bytes.NewBufferString("{\"Asset\": {\"version\": \"2.0\"}, \"unknown\": [1]}"), nil), args{new(Document)}, false},
+		{"notArray", NewDecoder(bytes.NewBufferString("{\"asset\": {\"version\": \"2.0\"}, \"nodes\": {}}"), nil), args{new(Document)}, true},
+		{"unsupportedVersion", NewDecoder(bytes.NewBufferString("{\"asset\": {\"version\": \"1.0\"}}"), nil), args{new(Document)}, true},
+		{"invalidVersion", NewDecoder(bytes.NewBufferString("{\"asset\": {\"version\": \"two\"}}"), nil), args{new(Document)}, true},
+		{"majorOnlyVersion", NewDecoder(bytes.NewBufferString("{\"asset\": {\"version\": \"2\"}}"), nil), args{new(Document)}, true},
+		{"trailingVersion", NewDecoder(bytes.NewBufferString("{\"asset\": {\"version\": \"2.0-beta\"}}"), nil), args{new(Document)}, true},
+		{"signedVersion", NewDecoder(bytes.NewBufferString("{\"asset\": {\"version\": \"+2.0\"}}"), nil), args{new(Document)}, true},
+		{"missingVersion", NewDecoder(bytes.NewBufferString("{\"asset\": {}}"), nil), args{new(Document)}, true},
+		{"unsupportedMinVersion", NewDecoder(bytes.NewBufferString("{\"asset\": {\"version\": \"2.0\", \"minVersion\": \"2.1\"}}"), nil), args{new(Document)}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -219,3 +356,409 @@ func TestDecoder_Decode(t *testing.T) {
 		})
 	}
 }
+
+func TestDecoder_quotas(t *testing.T) {
+	data := `{"asset": {"version": "2.0"}, "accessors": [{}, {}], "bufferViews": [{"byteLength": 1}, {"byteLength": 1}],
+	"nodes": [{}, {}], "meshes": [{}, {}], "buffers": [{"byteLength": 1, "uri": "a.bin"}, {"byteLength": 1, "uri": "b.bin"}]}`
+	tests := []struct {
+		name   string
+		quotas ReadQuotas
+		want   string
+	}{
+		{"buffers", ReadQuotas{MaxBufferCount: 1, MaxMemoryAllocation: 1}, "MaxBufferCount"},
+		{"noBuffers", ReadQuotas{MaxMemoryAllocation: 1}, "MaxBufferCount"},
+		{"memory", ReadQuotas{MaxBufferCount: 2}, "MaxMemoryAllocation"},
+		{"accessors", ReadQuotas{MaxBufferCount: 2, MaxMemoryAllocation: 1, MaxAccessorCount: 1}, "MaxAccessorCount"},
+		{"bufferViews", ReadQuotas{MaxBufferCount: 2, MaxMemoryAllocation: 1, MaxBufferViewCount: 1}, "MaxBufferViewCount"},
+		{"nodes", ReadQuotas{MaxBufferCount: 2, MaxMemoryAllocation: 1, MaxNodeCount: 1}, "MaxNodeCount"},
+		{"meshes", ReadQuotas{MaxBufferCount: 2, MaxMemoryAllocation: 1, MaxMeshCount: 1}, "MaxMeshCount"},
+		{"unlimitedCounts", ReadQuotas{MaxBufferCount: 2, MaxMemoryAllocation: 1}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := func(string) (io.ReadCloser, error) { return nil, nil }
+			decoders := map[string]func(*Decoder) error{
+				"Decode": func(d *Decoder) error { return d.Decode(new(Document)) },
+				"DecodeStream": func(d *Decoder) error {
+					return d.DecodeStream(new(Document), &StreamCallbacks{OnNode: func(uint32, *Node) error { return nil }})
+				},
+			}
+			for name, decode := range decoders {
+				err := decode(NewDecoder(bytes.NewBufferString(data), cb).SetQuotas(tt.quotas))
+				if tt.want == "" {
+					if err != nil {
+						t.Errorf("Decoder.%s() error = %v", name, err)
+					}
+				} else if err == nil || !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("Decoder.%s() error = %v, want %s quota", name, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecoder_SetRawExtras(t *testing.T) {
+	data := `{"asset": {"version": "2.0", "extras": {"id": 12345678901234567890}},
+	"nodes": [{"name": "a"}, {"extras": [1, 2.50]}],
+	"materials": [{"pbrMetallicRoughness": {"extras": "text"}}],
+	"extras": 1.0}`
+	doc := new(Document)
+	if err := NewDecoder(bytes.NewBufferString(data), nil).SetRawExtras(true).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"document", doc.Extras, json.RawMessage(`1.0`)},
+		{"asset", doc.Asset.Extras, json.RawMessage(`{"id": 12345678901234567890}`)},
+		{"nodeWithout", doc.Nodes[0].Extras, nil},
+		{"node", doc.Nodes[1].Extras, json.RawMessage(`[1, 2.50]`)},
+		{"nested", doc.Materials[0].PBRMetallicRoughness.Extras, json.RawMessage(`"text"`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := deep.Equal(tt.got, tt.want); diff != nil {
+				t.Errorf("Decoder.Decode() = %v", diff)
+			}
+		})
+	}
+}
+
+func Test_addUint32(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   uint32
+		want   uint32
+		wantOk bool
+	}{
+		{"zero", 0, 0, 0, true},
+		{"base", 1, 2, 3, true},
+		{"max", math.MaxUint32 - 1, 1, math.MaxUint32, true},
+		{"overflow", math.MaxUint32, 1, 0, false},
+		{"overflowLarge", 0xfffffff8, 20, 12, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := addUint32(tt.a, tt.b)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("addUint32() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestDecoder_SetStrictColors(t *testing.T) {
+	data := `{"asset": {"version": "2.0"}, "materials": [
+	{"pbrMetallicRoughness": {"baseColorFactor": [0.5, 1.2, 0.5, 1]}},
+	{"emissiveFactor": [0.5, 0.5, 0.5]},
+	{"emissiveFactor": [-0.1, 0.5, 2]}]}`
+	doc := new(Document)
+	if err := NewDecoder(bytes.NewBufferString(data), nil).Decode(doc); err != nil {
+		t.Errorf("Decoder.Decode() error = %v", err)
+	}
+	err := NewDecoder(bytes.NewBufferString(data), nil).SetStrictColors(true).Decode(doc)
+	want := ValidationErrors{
+		{"/materials/0/pbrMetallicRoughness/baseColorFactor/1", ErrColorOutOfRange},
+		{"/materials/2/emissiveFactor/0", ErrColorOutOfRange},
+		{"/materials/2/emissiveFactor/2", ErrColorOutOfRange},
+	}
+	if diff := diffValidationErrors(err, want); diff != nil {
+		t.Errorf("Decoder.Decode() = %v", diff)
+	}
+	if err := NewDecoder(bytes.NewBufferString(data[:strings.Index(data, ",")]+"}"), nil).SetStrictColors(true).Decode(new(Document)); err != nil {
+		t.Errorf("Decoder.Decode() error = %v", err)
+	}
+}
+
+func TestDecoder_SetUseNumber(t *testing.T) {
+	data := `{"asset":{"version":"2.0","extras":{"id":12345678901234567890}},"nodes":[{"extras":[1,2.50,"a"]}],"extras":1.0}`
+	doc := new(Document)
+	if err := NewDecoder(bytes.NewBufferString(data), nil).SetUseNumber(true).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"document", doc.Extras, json.Number("1.0")},
+		{"asset", doc.Asset.Extras, map[string]interface{}{"id": json.Number("12345678901234567890")}},
+		{"node", doc.Nodes[0].Extras, []interface{}{json.Number("1"), json.Number("2.50"), "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := deep.Equal(tt.got, tt.want); diff != nil {
+				t.Errorf("Decoder.Decode() = %v", diff)
+			}
+		})
+	}
+	out, err := json.Marshal(doc.Asset)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"extras":{"id":12345678901234567890},"version":"2.0"}`; string(out) != want {
+		t.Errorf("json.Marshal() = %s, want %s", out, want)
+	}
+}
+
+func TestDecoder_Decode_truncatedBuffer(t *testing.T) {
+	data := `{"asset": {"version": "2.0"}, "buffers": [{"byteLength": 3, "uri": "a.bin"}]}`
+	err := NewDecoder(bytes.NewBufferString(data), readCallback).Decode(new(Document))
+	if err == nil {
+		t.Error("Decoder.Decode() expected error with a truncated buffer")
+	}
+}
+
+func TestDecoder_SetSchemeCallback(t *testing.T) {
+	var loaded []string
+	callback := func(prefix string) ReadResourceCallback {
+		return func(uri string) (io.ReadCloser, error) {
+			loaded = append(loaded, prefix+uri)
+			return ioutil.NopCloser(bytes.NewBufferString("abc")), nil
+		}
+	}
+	data := `{"asset": {"version": "2.0"}, "buffers": [{"byteLength": 3, "uri": "a.bin"}, {"byteLength": 3, "uri": "S3://bucket/b.bin"},
+	{"byteLength": 3, "uri": "asset://c.bin"}, {"byteLength": 3, "uri": "http://d.bin"}]}`
+	d := NewDecoder(bytes.NewBufferString(data), callback("default:")).
+		SetSchemeCallback("s3", callback("s3:")).
+		SetSchemeCallback("asset", callback("asset:")).
+		SetSchemeCallback("http", callback("http:")).
+		SetSchemeCallback("http", nil)
+	if err := d.Decode(new(Document)); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	want := []string{"default:a.bin", "s3:S3://bucket/b.bin", "asset:asset://c.bin", "default:http://d.bin"}
+	if diff := deep.Equal(loaded, want); diff != nil {
+		t.Errorf("Decoder.SetSchemeCallback() = %v", diff)
+	}
+}
+
+func Test_uriScheme(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"a.bin", ""},
+		{"dir/a:b.bin", ""},
+		{"C:/a.bin", ""},
+		{"HTTPS://a.bin", "https"},
+		{"s3://bucket/a.bin", "s3"},
+		{"svn+ssh://a", "svn+ssh"},
+		{"1a://a", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			if got := uriScheme(tt.uri); got != tt.want {
+				t.Errorf("uriScheme() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_stream(t *testing.T) {
+	src := &Document{
+		Asset:   Asset{Version: "2.0"},
+		Buffers: []Buffer{{ByteLength: 5, Data: []byte{1, 2, 3, 4, 5}}},
+		Nodes:   []Node{{Name: "n"}},
+	}
+	buff := new(bytes.Buffer)
+	chunks := map[uint32][]byte{0x123: {6, 7, 8, 9}}
+	if err := NewEncoder(buff, nil, true).SetExtraChunks(chunks).Encode(src); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	data := buff.Bytes()
+	tests := []struct {
+		name string
+		r    io.Reader
+	}{
+		{"oneByte", iotest.OneByteReader(bytes.NewReader(data))},
+		{"half", iotest.HalfReader(bytes.NewReader(data))},
+		{"dataErr", iotest.DataErrReader(bytes.NewReader(data))},
+		{"pipe", func() io.Reader {
+			pr, pw := io.Pipe()
+			go func() {
+				for i := 0; i < len(data); i += 3 {
+					end := i + 3
+					if end > len(data) {
+						end = len(data)
+					}
+					pw.Write(data[i:end])
+				}
+				pw.Close()
+			}()
+			return pr
+		}()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := new(Document)
+			d := NewDecoder(tt.r, nil)
+			if err := d.Decode(doc); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			if diff := deep.Equal(doc.Buffers[0].Data, src.Buffers[0].Data); diff != nil {
+				t.Errorf("Decoder.Decode() BIN = %v", diff)
+			}
+			if doc.Nodes[0].Name != "n" {
+				t.Errorf("Decoder.Decode() nodes = %v", doc.Nodes)
+			}
+			if diff := deep.Equal(d.ExtraChunks(), chunks); diff != nil {
+				t.Errorf("Decoder.ExtraChunks() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_meshoptFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		buffer  string
+		wantErr bool
+	}{
+		{"fallback", `{"byteLength": 100000000, "extensions": {"EXT_meshopt_compression": {"fallback": true}}}`, false},
+		{"notFallback", `{"byteLength": 4, "extensions": {"EXT_meshopt_compression": {"fallback": false}}}`, true},
+		{"noExtension", `{"byteLength": 4}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := `{"asset": {"version": "2.0"}, "extensionsUsed": ["EXT_meshopt_compression"], "buffers": [` + tt.buffer + `]}`
+			doc := new(Document)
+			err := NewDecoder(bytes.NewBufferString(data), nil).Decode(doc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decoder.Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && doc.Buffers[0].Data != nil {
+				t.Errorf("Decoder.Decode() loaded data of a fallback buffer")
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_glbBufferURI(t *testing.T) {
+	tests := []struct {
+		name string
+		bin  []byte
+	}{
+		{"noBIN", nil},
+		{"unusedBIN", []byte{9, 9, 9, 9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonText := []byte(`{"asset": {"version": "2.0"}, "buffers": [{"byteLength": 3, "uri": "data:application/octet-stream;base64,AQID"}, {"byteLength": 1, "uri": "a.bin"}]}`)
+			var bin io.Reader
+			if tt.bin != nil {
+				bin = bytes.NewReader(tt.bin)
+			}
+			var glb bytes.Buffer
+			if err := WriteGLB(&glb, jsonText, bin, uint32(len(tt.bin))); err != nil {
+				t.Fatalf("WriteGLB() error = %v", err)
+			}
+			doc := new(Document)
+			if err := NewDecoder(&glb, readCallback).Decode(doc); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			if want := []byte{1, 2, 3}; !bytes.Equal(doc.Buffers[0].Data, want) {
+				t.Errorf("Decoder.Decode() buffers[0] = %v, want %v", doc.Buffers[0].Data, want)
+			}
+			if want := []byte("a"); !bytes.Equal(doc.Buffers[1].Data, want) {
+				t.Errorf("Decoder.Decode() buffers[1] = %v, want %v", doc.Buffers[1].Data, want)
+			}
+		})
+	}
+}
+
+func TestCheckGLBLength(t *testing.T) {
+	var glb bytes.Buffer
+	if err := WriteGLB(&glb, []byte(`{"asset":{"version":"2.0"}}`), bytes.NewReader([]byte{1, 2, 3, 4, 5}), 5); err != nil {
+		t.Fatalf("WriteGLB() error = %v", err)
+	}
+	valid := glb.Bytes()
+	withLength := func(data []byte, length uint32) []byte {
+		out := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint32(out[8:], length)
+		return out
+	}
+	// A chunk header that declares 8 bytes of data that are missing.
+	extraChunk := append(append([]byte(nil), valid...), 8, 0, 0, 0, 0, 0, 0, 0)
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"valid", valid, false},
+		{"short", valid[:10], true},
+		{"notGLB", []byte(`{"asset":{"version":"2.0"}}`), true},
+		{"truncated", valid[:len(valid)-4], true},
+		{"concatenated", append(append([]byte(nil), valid...), valid...), true},
+		{"chunksMismatch", withLength(extraChunk, uint32(len(extraChunk))), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckGLBLength(tt.data); (err != nil) != tt.wantErr {
+				t.Errorf("CheckGLBLength() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_embeddedBufferLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		buffer  string
+		wantErr bool
+	}{
+		{"valid", `{"byteLength": 3, "uri": "data:application/octet-stream;base64,AQID"}`, false},
+		{"short", `{"byteLength": 4, "uri": "data:application/octet-stream;base64,AQID"}`, true},
+		{"long", `{"byteLength": 2, "uri": "data:application/octet-stream;base64,AQID"}`, true},
+		{"empty", `{"byteLength": 4, "uri": "data:application/octet-stream;base64,"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonText := `{"asset": {"version": "2.0"}, "buffers": [` + tt.buffer + `]}`
+			err := NewDecoder(strings.NewReader(jsonText), nil).Decode(new(Document))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decoder.Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecoder_SetBufferFilter(t *testing.T) {
+	jsonText := []byte(`{"asset": {"version": "2.0"}, "buffers": [{"byteLength": 4}, {"byteLength": 3, "uri": "data:application/octet-stream;base64,AQID"}, {"byteLength": 1, "uri": "a.bin"}]}`)
+	tests := []struct {
+		name   string
+		filter func(int, *Buffer) bool
+		want   [][]byte
+	}{
+		{"all", nil, [][]byte{{9, 8, 7, 6}, {1, 2, 3}, []byte("a")}},
+		{"skipBIN", func(i int, _ *Buffer) bool { return i != 0 }, [][]byte{nil, {1, 2, 3}, []byte("a")}},
+		{"embeddedOnly", func(_ int, b *Buffer) bool { return b.IsEmbeddedResource() }, [][]byte{nil, {1, 2, 3}, nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var glb bytes.Buffer
+			if err := WriteGLB(&glb, jsonText, bytes.NewReader([]byte{9, 8, 7, 6}), 4); err != nil {
+				t.Fatalf("WriteGLB() error = %v", err)
+			}
+			// An extra chunk after the BIN chunk checks that a skipped BIN chunk is fully consumed.
+			glb.Write([]byte{4, 0, 0, 0, 'E', 'X', 'T', 0, 1, 2, 3, 4})
+			data := glb.Bytes()
+			binary.LittleEndian.PutUint32(data[8:], uint32(len(data)))
+			d := NewDecoder(bytes.NewReader(data), readCallback).SetBufferFilter(tt.filter)
+			doc := new(Document)
+			if err := d.Decode(doc); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			for i, want := range tt.want {
+				if !bytes.Equal(doc.Buffers[i].Data, want) || (want == nil) != (doc.Buffers[i].Data == nil) {
+					t.Errorf("Decoder.Decode() buffers[%d] = %v, want %v", i, doc.Buffers[i].Data, want)
+				}
+			}
+			if len(d.ExtraChunks()) != 1 {
+				t.Errorf("Decoder.ExtraChunks() = %v, want one chunk", d.ExtraChunks())
+			}
+		})
+	}
+}
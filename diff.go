@@ -0,0 +1,394 @@
+package gltf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// DifferenceKind defines how an element differs between two documents.
+type DifferenceKind uint8
+
+const (
+	// DifferenceAdded is an element that only exists in the second document.
+	DifferenceAdded DifferenceKind = iota
+	// DifferenceRemoved is an element that only exists in the first document.
+	DifferenceRemoved
+	// DifferenceChanged is an element with the same name in both documents but a different content.
+	DifferenceChanged
+	// DifferenceRenamed is an element with the same content in both documents but a different name.
+	DifferenceRenamed
+)
+
+func (k DifferenceKind) String() string {
+	switch k {
+	case DifferenceAdded:
+		return "added"
+	case DifferenceRemoved:
+		return "removed"
+	case DifferenceChanged:
+		return "changed"
+	case DifferenceRenamed:
+		return "renamed"
+	}
+	return fmt.Sprintf("DifferenceKind(%d)", uint8(k))
+}
+
+// A Difference describes an element of a top-level property that differs between two documents.
+type Difference struct {
+	Kind     DifferenceKind
+	Property string  // Top-level property of the element, such as "meshes".
+	A, B     *uint32 // Index of the element in each document, nil when it does not exist there.
+	Name     string  // Name of the element in the second document, or in the first one when it has been removed.
+}
+
+func (d Difference) String() string {
+	var index uint32
+	if d.B != nil {
+		index = *d.B
+	} else if d.A != nil {
+		index = *d.A
+	}
+	if d.Name == "" {
+		return fmt.Sprintf("%s/%d: %s", d.Property, index, d.Kind)
+	}
+	return fmt.Sprintf("%s/%d %q: %s", d.Property, index, d.Name, d.Kind)
+}
+
+// Diff compares two documents semantically and returns the elements that differ between them,
+// grouped by property in the order accessors, images, textures, materials, cameras, meshes, nodes, skins, animations and scenes.
+// Elements are compared by content regardless of their position in the arrays,
+// so reordering elements or changing the layout of the binary data does not produce differences:
+// references are compared by the content of the referenced element, accessors by the values of their elements,
+// images by their data, or their URI when it is not loaded, and properties with a default value
+// are equal to those that omit it. Names are not part of the content.
+// Elements with the same content are reported as renamed if their names are different,
+// and the remaining elements with the same name as changed.
+// Indices stored inside extensions are compared as they are.
+func Diff(a, b *Document) []Difference {
+	ka, kb := newContentKeys(a), newContentKeys(b)
+	var diffs []Difference
+	for i, property := range contentProperties {
+		diffs = append(diffs, matchElements(property, ka.elements[i], kb.elements[i])...)
+	}
+	return diffs
+}
+
+var contentProperties = []string{"accessors", "images", "textures", "materials", "cameras", "meshes", "nodes", "skins", "animations", "scenes"}
+
+// contentElement is the key and the name of an element, as compared by Diff.
+type contentElement struct {
+	key, name string
+}
+
+// matchElements pairs the elements of a and b with the same key and name, then the ones with the same key,
+// and finally the ones with the same name, and reports the rest as removed or added.
+func matchElements(property string, a, b []contentElement) []Difference {
+	matchedA, matchedB := make([]bool, len(a)), make([]bool, len(b))
+	var diffs []Difference
+	pair := func(same func(x, y contentElement) bool, kind DifferenceKind, report bool) {
+		for i := range a {
+			if matchedA[i] {
+				continue
+			}
+			for j := range b {
+				if !matchedB[j] && same(a[i], b[j]) {
+					matchedA[i], matchedB[j] = true, true
+					if report {
+						diffs = append(diffs, Difference{Kind: kind, Property: property, A: Index(uint32(i)), B: Index(uint32(j)), Name: b[j].name})
+					}
+					break
+				}
+			}
+		}
+	}
+	pair(func(x, y contentElement) bool { return x == y }, 0, false)
+	pair(func(x, y contentElement) bool { return x.key == y.key }, DifferenceRenamed, true)
+	pair(func(x, y contentElement) bool { return x.name != "" && x.name == y.name }, DifferenceChanged, true)
+	for i := range a {
+		if !matchedA[i] {
+			diffs = append(diffs, Difference{Kind: DifferenceRemoved, Property: property, A: Index(uint32(i)), Name: a[i].name})
+		}
+	}
+	for j := range b {
+		if !matchedB[j] {
+			diffs = append(diffs, Difference{Kind: DifferenceAdded, Property: property, B: Index(uint32(j)), Name: b[j].name})
+		}
+	}
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffOrder(diffs[i]) < diffOrder(diffs[j])
+	})
+	return diffs
+}
+
+// diffOrder sorts the differences by their index in the first document, followed by the added ones.
+func diffOrder(d Difference) int64 {
+	if d.A != nil {
+		return int64(*d.A)
+	}
+	return 1<<32 + int64(*d.B)
+}
+
+// contentKeys computes the keys of the elements of a document compared by Diff.
+type contentKeys struct {
+	d        *Document
+	elements [][]contentElement // Indexed as contentProperties.
+	visiting []bool             // Nodes whose key is being computed, to break cycles.
+}
+
+func newContentKeys(d *Document) *contentKeys {
+	k := &contentKeys{d: d, elements: make([][]contentElement, len(contentProperties)), visiting: make([]bool, len(d.Nodes))}
+	for i := range d.Accessors {
+		k.add(0, d.Accessors[i].Name, k.accessor(&d.Accessors[i], uint32(i)))
+	}
+	for i := range d.Images {
+		k.add(1, d.Images[i].Name, k.image(&d.Images[i]))
+	}
+	for i := range d.Textures {
+		k.add(2, d.Textures[i].Name, k.texture(&d.Textures[i]))
+	}
+	for i := range d.Materials {
+		k.add(3, d.Materials[i].Name, k.material(&d.Materials[i]))
+	}
+	for i := range d.Cameras {
+		cam := d.Cameras[i]
+		cam.Name = ""
+		k.add(4, d.Cameras[i].Name, digest(&cam))
+	}
+	for i := range d.Meshes {
+		k.add(5, d.Meshes[i].Name, k.mesh(&d.Meshes[i]))
+	}
+	k.elements[6] = make([]contentElement, len(d.Nodes))
+	for i := range d.Nodes {
+		k.elements[6][i].name = d.Nodes[i].Name
+		k.node(uint32(i))
+	}
+	for i := range d.Skins {
+		k.add(7, d.Skins[i].Name, k.skin(&d.Skins[i]))
+	}
+	for i := range d.Animations {
+		k.add(8, d.Animations[i].Name, k.animation(&d.Animations[i]))
+	}
+	for i := range d.Scenes {
+		scene := &d.Scenes[i]
+		k.add(9, scene.Name, digest(struct {
+			Nodes      []string
+			Extensions Extensions
+			Extras     interface{}
+		}{k.nodeList(scene.Nodes), scene.Extensions, scene.Extras}))
+	}
+	return k
+}
+
+func (k *contentKeys) add(property int, name, key string) {
+	k.elements[property] = append(k.elements[property], contentElement{key, name})
+}
+
+// ref returns the key of the referenced element of the given property, which must be already computed.
+func (k *contentKeys) ref(property int, index *uint32) string {
+	if index == nil {
+		return ""
+	}
+	if int(*index) >= len(k.elements[property]) {
+		return fmt.Sprintf("missing %d", *index)
+	}
+	return k.elements[property][*index].key
+}
+
+func (k *contentKeys) accessor(acc *Accessor, index uint32) string {
+	values, err := k.d.ReadAccessor(index)
+	unreadable := ""
+	if err != nil {
+		unreadable = err.Error()
+	}
+	return digest(struct {
+		Type          AccessorType
+		ComponentType ComponentType
+		Normalized    bool
+		Count         uint32
+		Min, Max      []float64
+		Values        []float64
+		Unreadable    string
+		Extensions    Extensions
+		Extras        interface{}
+	}{acc.Type, acc.ComponentType, acc.Normalized, acc.Count, acc.Min, acc.Max, values, unreadable, acc.Extensions, acc.Extras})
+}
+
+func (k *contentKeys) image(img *Image) string {
+	uri := img.URI
+	data, err := k.d.imageData(img)
+	if err == nil {
+		uri = ""
+	}
+	return digest(struct {
+		MimeType   string
+		URI        string
+		Data       []byte
+		Extensions Extensions
+		Extras     interface{}
+	}{img.MimeType, uri, data, img.Extensions, img.Extras})
+}
+
+func (k *contentKeys) texture(tex *Texture) string {
+	var sampler *Sampler
+	if tex.Sampler != nil && int(*tex.Sampler) < len(k.d.Samplers) {
+		s := k.d.Samplers[*tex.Sampler]
+		s.Name = ""
+		sampler = &s
+	}
+	return digest(struct {
+		Sampler    *Sampler
+		Source     string
+		Extensions Extensions
+		Extras     interface{}
+	}{sampler, k.ref(1, tex.Source), tex.Extensions, tex.Extras})
+}
+
+func (k *contentKeys) material(mat *Material) string {
+	// Encoding and decoding the material sets the omitted properties to their default value.
+	var m Material
+	if data, err := json.Marshal(mat); err == nil {
+		json.Unmarshal(data, &m)
+	}
+	m.Name = ""
+	var textures []string
+	m.WalkTextures(func(index, _ *uint32) {
+		textures = append(textures, k.ref(2, index))
+		*index = 0
+	})
+	return digest(struct {
+		Material *Material
+		Textures []string
+	}{&m, textures})
+}
+
+func (k *contentKeys) mesh(mesh *Mesh) string {
+	attributes := func(attrs Attribute) map[string]string {
+		out := make(map[string]string, len(attrs))
+		for name, index := range attrs {
+			out[name] = k.ref(0, Index(index))
+		}
+		return out
+	}
+	type primitiveKey struct {
+		Mode       PrimitiveMode
+		Attributes map[string]string
+		Targets    []map[string]string
+		Indices    string
+		Material   string
+		Extensions Extensions
+		Extras     interface{}
+	}
+	prims := make([]primitiveKey, len(mesh.Primitives))
+	for i, prim := range mesh.Primitives {
+		prims[i] = primitiveKey{prim.Mode, attributes(prim.Attributes), nil, k.ref(0, prim.Indices), k.ref(3, prim.Material), prim.Extensions, prim.Extras}
+		for _, target := range prim.Targets {
+			prims[i].Targets = append(prims[i].Targets, attributes(target))
+		}
+	}
+	return digest(struct {
+		Primitives []primitiveKey
+		Weights    []float64
+		Extensions Extensions
+		Extras     interface{}
+	}{prims, mesh.Weights, mesh.Extensions, mesh.Extras})
+}
+
+// node returns the key of a node, which includes the keys of its descendants.
+// The skin is not part of the key, as its joints are nodes, only whether the node is skinned.
+// Each key is computed once and stored in the node elements, so shared subtrees are not digested again.
+func (k *contentKeys) node(index uint32) string {
+	if int(index) >= len(k.d.Nodes) {
+		return fmt.Sprintf("missing %d", index)
+	}
+	if key := k.elements[6][index].key; key != "" {
+		return key
+	}
+	if k.visiting[index] {
+		return "cycle"
+	}
+	k.visiting[index] = true
+	defer func() { k.visiting[index] = false }()
+	node := &k.d.Nodes[index]
+	key := digest(struct {
+		Matrix     [16]float64
+		Mesh       string
+		Camera     string
+		Skinned    bool
+		Weights    []float64
+		Children   []string
+		Extensions Extensions
+		Extras     interface{}
+	}{localMatrix(node), k.ref(5, node.Mesh), k.ref(4, node.Camera), node.Skin != nil, node.Weights, k.nodeList(node.Children), node.Extensions, node.Extras})
+	k.elements[6][index].key = key
+	return key
+}
+
+// nodeList returns the sorted keys of the nodes, as the order of children and scene roots is not meaningful.
+func (k *contentKeys) nodeList(indices []uint32) []string {
+	keys := make([]string, len(indices))
+	for i, index := range indices {
+		keys[i] = k.node(index)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (k *contentKeys) nodeRef(index *uint32) string {
+	if index == nil {
+		return ""
+	}
+	return k.node(*index)
+}
+
+func (k *contentKeys) skin(skin *Skin) string {
+	joints := make([]string, len(skin.Joints))
+	for i, joint := range skin.Joints {
+		joints[i] = k.node(joint)
+	}
+	return digest(struct {
+		InverseBindMatrices string
+		Skeleton            string
+		Joints              []string
+		Extensions          Extensions
+		Extras              interface{}
+	}{k.ref(0, skin.InverseBindMatrices), k.nodeRef(skin.Skeleton), joints, skin.Extensions, skin.Extras})
+}
+
+func (k *contentKeys) animation(anim *Animation) string {
+	channels := make([]string, len(anim.Channels))
+	for i, channel := range anim.Channels {
+		var sampler *AnimationSampler
+		if channel.Sampler != nil && int(*channel.Sampler) < len(anim.Samplers) {
+			sampler = &anim.Samplers[*channel.Sampler]
+		} else {
+			sampler = new(AnimationSampler)
+		}
+		channels[i] = digest(struct {
+			Input, Output     string
+			Interpolation     Interpolation
+			Node              string
+			Path              TRSProperty
+			Extensions        Extensions
+			SamplerExtensions Extensions
+		}{k.ref(0, sampler.Input), k.ref(0, sampler.Output), sampler.Interpolation, k.nodeRef(channel.Target.Node), channel.Target.Path, channel.Extensions, sampler.Extensions})
+	}
+	sort.Strings(channels)
+	return digest(struct {
+		Channels   []string
+		Extensions Extensions
+		Extras     interface{}
+	}{channels, anim.Extensions, anim.Extras})
+}
+
+// digest returns a hash of the JSON encoding of v.
+func digest(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%#v", v))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
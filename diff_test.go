@@ -0,0 +1,104 @@
+package gltf
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(doc *Document)
+		want   []Difference
+	}{
+		{"equal", func(doc *Document) {}, nil},
+		{"layout", func(doc *Document) { doc.MergeBuffers() }, nil},
+		{"reordered", func(doc *Document) {
+			doc.Materials[0], doc.Materials[1] = doc.Materials[1], doc.Materials[0]
+			doc.Meshes[0].Primitives[0].Material = Index(0)
+		}, nil},
+		{"defaults", func(doc *Document) {
+			doc.Materials[0].AlphaCutoff = Float64(0.5)
+			doc.Nodes[0].Scale = [3]float64{1, 1, 1}
+		}, nil},
+		{"renamed", func(doc *Document) { doc.Materials[0].Name = "other" }, []Difference{
+			{Kind: DifferenceRenamed, Property: "materials", A: Index(0), B: Index(0), Name: "other"},
+		}},
+		{"changed", func(doc *Document) { doc.Materials[1].EmissiveFactor = [3]float64{1, 0, 0} }, []Difference{
+			{Kind: DifferenceChanged, Property: "materials", A: Index(1), B: Index(1), Name: "used"},
+			{Kind: DifferenceRemoved, Property: "meshes", A: Index(0)},
+			{Kind: DifferenceAdded, Property: "meshes", B: Index(0)},
+			{Kind: DifferenceRemoved, Property: "nodes", A: Index(0)},
+			{Kind: DifferenceAdded, Property: "nodes", B: Index(0)},
+		}},
+		{"added", func(doc *Document) {
+			doc.Cameras = append(doc.Cameras, Camera{Name: "cam", Perspective: &Perspective{Yfov: 1, Znear: 0.1}})
+		}, []Difference{
+			{Kind: DifferenceAdded, Property: "cameras", B: Index(0), Name: "cam"},
+		}},
+		{"removed", func(doc *Document) {
+			doc.Accessors = doc.Accessors[:2]
+			doc.Meshes = doc.Meshes[:2]
+		}, []Difference{
+			{Kind: DifferenceRemoved, Property: "accessors", A: Index(2), Name: "unused"},
+			{Kind: DifferenceRemoved, Property: "meshes", A: Index(2)},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newOptimizeDoc()
+			tt.modify(b)
+			got := Diff(newOptimizeDoc(), b)
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Diff() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDiff_hierarchy(t *testing.T) {
+	chain := func(depth int, leafScale float64) *Document {
+		doc := &Document{Nodes: make([]Node, depth), Scenes: []Scene{{Nodes: []uint32{0}}}}
+		for i := 0; i < depth-1; i++ {
+			doc.Nodes[i].Children = []uint32{uint32(i + 1)}
+		}
+		doc.Nodes[depth-1].Scale = [3]float64{leafScale, leafScale, leafScale}
+		return doc
+	}
+	if got := Diff(chain(5000, 2), chain(5000, 2)); len(got) != 0 {
+		t.Errorf("Diff() = %v, want no differences", got)
+	}
+	got := Diff(chain(3, 2), chain(3, 3))
+	want := []Difference{
+		{Kind: DifferenceRemoved, Property: "nodes", A: Index(0)},
+		{Kind: DifferenceRemoved, Property: "nodes", A: Index(1)},
+		{Kind: DifferenceRemoved, Property: "nodes", A: Index(2)},
+		{Kind: DifferenceAdded, Property: "nodes", B: Index(0)},
+		{Kind: DifferenceAdded, Property: "nodes", B: Index(1)},
+		{Kind: DifferenceAdded, Property: "nodes", B: Index(2)},
+		{Kind: DifferenceRemoved, Property: "scenes", A: Index(0)},
+		{Kind: DifferenceAdded, Property: "scenes", B: Index(0)},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("Diff() = %v", diff)
+	}
+}
+
+func TestDifference_String(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Difference
+		want string
+	}{
+		{"added", Difference{Kind: DifferenceAdded, Property: "meshes", B: Index(2), Name: "box"}, `meshes/2 "box": added`},
+		{"removed", Difference{Kind: DifferenceRemoved, Property: "nodes", A: Index(1)}, "nodes/1: removed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.String(); got != tt.want {
+				t.Errorf("Difference.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,17 @@
 package gltf
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"unsafe"
 )
 
@@ -29,14 +35,37 @@ func Save(doc *Document, name string, asBinary bool) error {
 	return f.Close()
 }
 
+// Marshal returns the encoding of doc as glTF or as GLB.
+// The data of the external buffers is not written, use an Encoder to save them.
+func Marshal(doc *Document, asBinary bool) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	cb := func(string, int) (io.WriteCloser, error) { return nopWriteCloser{ioutil.Discard}, nil }
+	if err := NewEncoder(buf, cb, asBinary).Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// nopWriteCloser wraps an io.Writer with a no-op Close method.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
 // An Encoder writes a GLTF to an output stream.
 type Encoder struct {
 	w        io.Writer
 	cb       WriteResourceCallback
 	asBinary bool
+	prefix   string
+	indent   string
+	chunks   map[uint32][]byte
 }
 
 // NewEncoder returns a new encoder that writes to w as a normal glTF file.
+// When asBinary is true it writes a GLB instead, which stores the first buffer in its BIN chunk
+// unless the buffer has a URI, in which case it is written as the rest of buffers.
 func NewEncoder(w io.Writer, cb WriteResourceCallback, asBinary bool) *Encoder {
 	return &Encoder{
 		w:        w,
@@ -45,18 +74,52 @@ func NewEncoder(w io.Writer, cb WriteResourceCallback, asBinary bool) *Encoder {
 	}
 }
 
+// SetIndent instructs the encoder to format the glTF JSON as if indented by json.MarshalIndent.
+// It has no effect when encoding as GLB, whose JSON chunk is always compact.
+// The return value is the same encoder.
+func (e *Encoder) SetIndent(prefix, indent string) *Encoder {
+	e.prefix = prefix
+	e.indent = indent
+	return e
+}
+
+// SetExtraChunks instructs the encoder to write the given chunks, indexed by chunk type, after the BIN chunk
+// when encoding as GLB, sorted by type. The data of each chunk is padded with zeros to a multiple of 4 bytes.
+// The chunk types must not be the JSON or BIN ones. It has no effect when encoding as glTF.
+// The return value is the same encoder.
+func (e *Encoder) SetExtraChunks(chunks map[uint32][]byte) *Encoder {
+	e.chunks = chunks
+	return e
+}
+
 // Encode writes the encoding of doc to the stream.
+// The URI of each buffer decides how its data is stored: a GLB stores the first buffer in its BIN chunk if it has no URI,
+// buffers with a data URI are embedded in the JSON as they are, and the data of the rest is written
+// to their URI with the WriteResourceCallback. It fails without writing anything
+// if the data of any other buffer without URI would be lost.
 func (e *Encoder) Encode(doc *Document) error {
+	for i := range doc.Buffers {
+		if e.asBinary && i == 0 {
+			continue
+		}
+		if buffer := &doc.Buffers[i]; buffer.URI == "" && len(buffer.Data) > 0 {
+			return fmt.Errorf("gltf: buffer %d has data but no URI", i)
+		}
+	}
 	if doc.Asset.Version == "" {
-		doc.Asset.Version = "2.0"
+		doc.Asset.Version = SupportedVersion
 	}
 	var err error
 	var externalBufferIndex = 0
 	if e.asBinary {
 		err = e.encodeBinary(doc)
-		externalBufferIndex = 1
+		if glbBuffer(doc) != nil {
+			externalBufferIndex = 1
+		}
 	} else {
-		err = json.NewEncoder(e.w).Encode(doc)
+		enc := json.NewEncoder(e.w)
+		enc.SetIndent(e.prefix, e.indent)
+		err = enc.Encode(doc)
 	}
 	if err != nil {
 		return err
@@ -96,6 +159,35 @@ func (e *Encoder) encodeBuffer(buffer *Buffer) error {
 	return r.Close()
 }
 
+// GLBSize returns the number of bytes that the Encoder would write to encode doc as GLB,
+// without the external buffers. Only the JSON is marshalled, the buffer data is not serialized,
+// so it is cheap enough for pre-flight checks.
+func GLBSize(doc *Document) (int64, error) {
+	if doc.Asset.Version == "" {
+		tmp := *doc
+		tmp.Asset.Version = SupportedVersion
+		doc = &tmp
+	}
+	jsonText, err := json.Marshal(doc)
+	if err != nil {
+		return 0, err
+	}
+	var binBufferLength int64
+	if buffer := glbBuffer(doc); buffer != nil {
+		binBufferLength = int64(buffer.ByteLength)
+	}
+	headerSize := int64(unsafe.Sizeof(glbHeader{}) + unsafe.Sizeof(chunkHeader{}))
+	return headerSize + int64(padding4(uint32(len(jsonText)))) + ((binBufferLength+3)/4)*4, nil
+}
+
+// glbBuffer returns the buffer stored in the BIN chunk of a GLB, which is the first one unless it has a URI.
+func glbBuffer(doc *Document) *Buffer {
+	if len(doc.Buffers) == 0 || doc.Buffers[0].URI != "" {
+		return nil
+	}
+	return &doc.Buffers[0]
+}
+
 func (e *Encoder) encodeBinary(doc *Document) error {
 	jsonText, err := json.Marshal(doc)
 	if err != nil {
@@ -104,17 +196,27 @@ func (e *Encoder) encodeBinary(doc *Document) error {
 	header := glbHeader{Magic: glbHeaderMagic, Version: 2, Length: 0, JSONHeader: chunkHeader{Length: 0, Type: glbChunkJSON}}
 	binHeader := chunkHeader{Length: 0, Type: glbChunkBIN}
 	var binBufferLength uint32
-	var binBuffer *Buffer
-	if len(doc.Buffers) > 0 {
-		binBuffer = &doc.Buffers[0]
+	binBuffer := glbBuffer(doc)
+	if binBuffer != nil {
 		binBufferLength = binBuffer.ByteLength
 	}
 	binPaddedLength := ((binBufferLength + 3) / 4) * 4
 	binPadding := make([]byte, binPaddedLength-binBufferLength)
 	binHeader.Length = binPaddedLength
 
+	types := make([]uint32, 0, len(e.chunks))
+	var chunksLength uint32
+	for chunkType, data := range e.chunks {
+		if chunkType == glbChunkJSON || chunkType == glbChunkBIN {
+			return errors.New("gltf: extra chunk type reserved for JSON or BIN")
+		}
+		types = append(types, chunkType)
+		chunksLength += uint32(unsafe.Sizeof(binHeader)) + padding4(uint32(len(data)))
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
 	header.JSONHeader.Length = uint32(((len(jsonText) + 3) / 4) * 4)
-	header.Length = uint32(unsafe.Sizeof(header)+unsafe.Sizeof(binHeader)) + header.JSONHeader.Length + binHeader.Length
+	header.Length = uint32(unsafe.Sizeof(header)+unsafe.Sizeof(binHeader)) + header.JSONHeader.Length + binHeader.Length + chunksLength
 	headerPadding := make([]byte, header.JSONHeader.Length-uint32(len(jsonText)))
 	for i := range headerPadding {
 		headerPadding[i] = ' '
@@ -132,5 +234,75 @@ func (e *Encoder) encodeBinary(doc *Document) error {
 	if binBuffer != nil {
 		binary.Write(e.w, binary.LittleEndian, binBuffer.Data)
 	}
-	return binary.Write(e.w, binary.LittleEndian, binPadding)
+	err = binary.Write(e.w, binary.LittleEndian, binPadding)
+	for _, chunkType := range types {
+		if err != nil {
+			break
+		}
+		data := e.chunks[chunkType]
+		err = writeChunk(e.w, chunkType, bytes.NewReader(data), uint32(len(data)))
+	}
+	return err
+}
+
+// WriteGLB writes a GLB container with the given JSON chunk and binLen bytes read from bin as BIN chunk,
+// adding the headers and the padding required by the specification.
+// It does not parse nor validate the JSON, so it can be used when the JSON and the binary data
+// are produced independently and there is no Document at all.
+// The BIN chunk is omitted if bin is nil.
+// It fails without writing anything if the length of the GLB does not fit in an uint32.
+func WriteGLB(w io.Writer, jsonText []byte, bin io.Reader, binLen uint32) error {
+	jsonLength, ok := paddedLength(uint64(len(jsonText)))
+	header := glbHeader{Magic: glbHeaderMagic, Version: 2, JSONHeader: chunkHeader{Length: jsonLength, Type: glbChunkJSON}}
+	if ok {
+		header.Length, ok = addUint32(uint32(unsafe.Sizeof(header)), jsonLength)
+	}
+	if ok && bin != nil {
+		var binLength uint32
+		if binLength, ok = paddedLength(uint64(binLen)); ok {
+			header.Length, ok = addUint32(header.Length, uint32(unsafe.Sizeof(chunkHeader{})))
+		}
+		if ok {
+			header.Length, ok = addUint32(header.Length, binLength)
+		}
+	}
+	if !ok {
+		return errors.New("gltf: GLB length overflows uint32")
+	}
+	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	if _, err := w.Write(jsonText); err != nil {
+		return err
+	}
+	if _, err := w.Write(bytes.Repeat([]byte{' '}, int(jsonLength)-len(jsonText))); err != nil {
+		return err
+	}
+	if bin == nil {
+		return nil
+	}
+	return writeChunk(w, glbChunkBIN, bin, binLen)
+}
+
+// paddedLength returns n padded to 4 bytes and whether it fits in an uint32.
+func paddedLength(n uint64) (uint32, bool) {
+	n = (n + 3) / 4 * 4
+	return uint32(n), n <= math.MaxUint32
+}
+
+// writeChunk writes a GLB chunk with length bytes read from r, padded with zeros to 4 bytes.
+func writeChunk(w io.Writer, chunkType uint32, r io.Reader, length uint32) error {
+	header := chunkHeader{Length: padding4(length), Type: chunkType}
+	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	n, err := io.CopyN(w, r, int64(length))
+	if err != nil {
+		if n < int64(length) && err == io.EOF {
+			return errors.New("gltf: chunk data shorter than its length")
+		}
+		return err
+	}
+	_, err = w.Write(make([]byte, header.Length-length))
+	return err
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"testing"
 
 	"github.com/go-test/deep"
@@ -73,7 +74,7 @@ func TestEncoder_Encode(t *testing.T) {
 		}}}, false},
 		{"withBuffer", args{&Document{Buffers: []Buffer{
 			{Extras: 8.0, Name: "binary", ByteLength: 3, URI: "a.bin", Data: []uint8{1, 2, 3}},
-			{Extras: 8.0, Name: "embedded", ByteLength: 2, URI: "data:application/octet-stream;base64,YW55ICsgb2xkICYgZGF0YQ==", Data: []byte("any + old & data")},
+			{Extras: 8.0, Name: "embedded", ByteLength: 16, URI: "data:application/octet-stream;base64,YW55ICsgb2xkICYgZGF0YQ==", Data: []byte("any + old & data")},
 			{Extras: 8.0, Name: "external", ByteLength: 4, URI: "b.bin", Data: []uint8{4, 5, 6, 7}},
 			{Extras: 8.0, Name: "external", ByteLength: 4, URI: "a.drc"},
 		}}}, false},
@@ -165,3 +166,205 @@ func TestEncoder_Encode(t *testing.T) {
 		}
 	}
 }
+
+func TestEncoder_Encode_bufferURIs(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      *Document
+		asBinary bool
+		wantURIs []string
+		wantErr  bool
+	}{
+		{"glb", &Document{Buffers: []Buffer{
+			{ByteLength: 2, Data: []byte{1, 2}},
+			{ByteLength: 2, URI: "b.bin", Data: []byte{3, 4}},
+			{ByteLength: 2, URI: "data:application/octet-stream;base64,BQY=", Data: []byte{5, 6}},
+			{ByteLength: 2, URI: "c.bin"},
+		}}, true, []string{"b.bin"}, false},
+		{"gltf", &Document{Buffers: []Buffer{
+			{ByteLength: 2, Data: []byte{1, 2}},
+			{ByteLength: 2, URI: "b.bin", Data: []byte{3, 4}},
+		}}, false, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uris []string
+			cb := func(uri string, size int) (io.WriteCloser, error) {
+				uris = append(uris, uri)
+				return &writeCloser{ioutil.Discard}, nil
+			}
+			buf := new(bytes.Buffer)
+			err := NewEncoder(buf, cb, tt.asBinary).Encode(tt.doc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Encoder.Encode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && buf.Len() > 0 {
+				t.Error("Encoder.Encode() wrote data before failing")
+			}
+			if diff := deep.Equal(uris, tt.wantURIs); diff != nil {
+				t.Errorf("Encoder.Encode() external buffers = %v", diff)
+			}
+		})
+	}
+}
+
+func TestEncoder_SetIndent(t *testing.T) {
+	doc := &Document{Asset: Asset{Version: "2.0"}, Scenes: []Scene{{Name: "s"}}}
+	tests := []struct {
+		name     string
+		asBinary bool
+		want     string
+	}{
+		{"gltf", false, "{\n\t\"asset\": {\n\t\t\"version\": \"2.0\"\n\t},\n\t\"scenes\": [\n\t\t{\n\t\t\t\"name\": \"s\"\n\t\t}\n\t]\n}\n"},
+		{"glb", true, `{"asset":{"version":"2.0"},"scenes":[{"name":"s"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buff := new(bytes.Buffer)
+			if err := NewEncoder(buff, nil, tt.asBinary).SetIndent("", "\t").Encode(doc); err != nil {
+				t.Errorf("Encoder.Encode() error = %v", err)
+				return
+			}
+			got := buff.String()
+			if tt.asBinary {
+				got = string(bytes.TrimRight(buff.Bytes()[20:20+len(tt.want)], " "))
+			}
+			if got != tt.want {
+				t.Errorf("Encoder.Encode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGLBSize(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  *Document
+	}{
+		{"empty", &Document{}},
+		{"noBuffers", &Document{Asset: Asset{Version: "2.0"}, Scenes: []Scene{{Name: "s"}}}},
+		{"aligned", &Document{Buffers: []Buffer{{ByteLength: 4, Data: []byte{1, 2, 3, 4}}}}},
+		{"padded", &Document{Buffers: []Buffer{{ByteLength: 5, Data: []byte{1, 2, 3, 4, 5}}}}},
+		{"external", &Document{Buffers: []Buffer{{ByteLength: 3, Data: []byte{1, 2, 3}}, {ByteLength: 4, URI: "a.bin", Data: []byte{1, 2, 3, 4}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GLBSize(tt.doc)
+			if err != nil {
+				t.Errorf("GLBSize() error = %v", err)
+				return
+			}
+			buff := new(bytes.Buffer)
+			cb := func(string, int) (io.WriteCloser, error) { return &writeCloser{ioutil.Discard}, nil }
+			if err := NewEncoder(buff, cb, true).Encode(tt.doc); err != nil {
+				t.Errorf("Encoder.Encode() error = %v", err)
+				return
+			}
+			if got != int64(buff.Len()) {
+				t.Errorf("GLBSize() = %v, want %v", got, buff.Len())
+			}
+		})
+	}
+}
+
+func TestEncoder_SetExtraChunks(t *testing.T) {
+	chunks := map[uint32][]byte{0x58595a: {1, 2, 3, 4, 5}, 0x123: {6, 7, 8, 9}}
+	tests := []struct {
+		name string
+		doc  *Document
+	}{
+		{"noBuffers", &Document{Asset: Asset{Version: "2.0"}}},
+		{"padded", &Document{Buffers: []Buffer{{ByteLength: 3, Data: []byte{1, 2, 3}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buff := new(bytes.Buffer)
+			if err := NewEncoder(buff, nil, true).SetExtraChunks(chunks).Encode(tt.doc); err != nil {
+				t.Fatalf("Encoder.Encode() error = %v", err)
+			}
+			d := NewDecoder(buff, nil)
+			if err := d.Decode(new(Document)); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			want := map[uint32][]byte{0x58595a: {1, 2, 3, 4, 5, 0, 0, 0}, 0x123: {6, 7, 8, 9}}
+			if diff := deep.Equal(d.ExtraChunks(), want); diff != nil {
+				t.Errorf("Decoder.ExtraChunks() = %v", diff)
+			}
+		})
+	}
+	err := NewEncoder(new(bytes.Buffer), nil, true).SetExtraChunks(map[uint32][]byte{glbChunkBIN: {1}}).Encode(new(Document))
+	if err == nil {
+		t.Error("Encoder.Encode() expected error with a reserved chunk type")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	doc := &Document{
+		Asset:   Asset{Version: "2.0", Generator: "gltf"},
+		Buffers: []Buffer{{ByteLength: 3, Data: []byte{1, 2, 3}}, {ByteLength: 1, URI: "a.bin", Data: []byte{4}}},
+		Scenes:  []Scene{{Name: "s"}},
+	}
+	cb := func(uri string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader([]byte{4})), nil
+	}
+	// The glTF encoding needs an URI for the first buffer, so it is embedded after encoding as GLB.
+	for _, asBinary := range []bool{true, false} {
+		if !asBinary {
+			doc.Buffers[0].EmbeddedResource()
+		}
+		data, err := Marshal(doc, asBinary)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if isGLB := bytes.HasPrefix(data, []byte("glTF")); isGLB != asBinary {
+			t.Errorf("Marshal() binary = %v, want %v", isGLB, asBinary)
+		}
+		got, err := Unmarshal(data, cb)
+		if err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if diff := deep.Equal(got, doc); diff != nil {
+			t.Errorf("Unmarshal() = %v", diff)
+		}
+	}
+	if _, err := Unmarshal([]byte("glTF"), nil); err == nil {
+		t.Error("Unmarshal() expected error")
+	}
+}
+
+func TestWriteGLB(t *testing.T) {
+	jsonText := []byte(`{"asset":{"version":"2.0"},"buffers":[{"byteLength":5}]}`)
+	buff := new(bytes.Buffer)
+	if err := WriteGLB(buff, jsonText, bytes.NewReader([]byte{1, 2, 3, 4, 5, 6}), 5); err != nil {
+		t.Fatalf("WriteGLB() error = %v", err)
+	}
+	if buff.Len()%4 != 0 {
+		t.Errorf("WriteGLB() length %d is not padded", buff.Len())
+	}
+	doc := new(Document)
+	if err := NewDecoder(buff, nil).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	if diff := deep.Equal(doc.Buffers[0].Data, []byte{1, 2, 3, 4, 5}); diff != nil {
+		t.Errorf("WriteGLB() BIN = %v", diff)
+	}
+
+	buff.Reset()
+	if err := WriteGLB(buff, []byte(`{"asset":{"version":"2.0"}}`), nil, 0); err != nil {
+		t.Fatalf("WriteGLB() error = %v", err)
+	}
+	if err := NewDecoder(buff, nil).Decode(new(Document)); err != nil {
+		t.Errorf("Decoder.Decode() error = %v", err)
+	}
+
+	if err := WriteGLB(new(bytes.Buffer), jsonText, bytes.NewReader([]byte{1, 2}), 5); err == nil {
+		t.Error("WriteGLB() expected error with short BIN data")
+	}
+
+	for _, binLen := range []uint32{math.MaxUint32 - 1, math.MaxUint32 - 64} {
+		buff.Reset()
+		if err := WriteGLB(buff, jsonText, bytes.NewReader(nil), binLen); err == nil || buff.Len() != 0 {
+			t.Errorf("WriteGLB() with BIN length %d = %v, wrote %d bytes, expected overflow error", binLen, err, buff.Len())
+		}
+	}
+}
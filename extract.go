@@ -0,0 +1,238 @@
+package gltf
+
+import "fmt"
+
+// ExtractPrimitive returns a new document that only contains the primitive at primitiveIndex of the mesh at meshIndex,
+// instantiated by a single node of the default scene.
+// The accessors, bufferViews, material, textures, samplers and images used by the primitive are copied
+// with their indices rebased, and the referenced binary data is copied into a single buffer.
+// Indices stored inside extensions are not remapped.
+func (d *Document) ExtractPrimitive(meshIndex, primitiveIndex uint32) (*Document, error) {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return nil, err
+	}
+	e := &extractor{
+		src:         d,
+		dst:         &Document{Asset: d.Asset, Buffers: []Buffer{{}}},
+		accessors:   make(map[uint32]uint32),
+		bufferViews: make(map[uint32]uint32),
+		textures:    make(map[uint32]uint32),
+		images:      make(map[uint32]uint32),
+		samplers:    make(map[uint32]uint32),
+	}
+	newPrim := Primitive{Extensions: prim.Extensions, Extras: prim.Extras, Mode: prim.Mode}
+	if newPrim.Attributes, err = e.attributes(prim.Attributes); err != nil {
+		return nil, err
+	}
+	for _, target := range prim.Targets {
+		newTarget, err := e.attributes(target)
+		if err != nil {
+			return nil, err
+		}
+		newPrim.Targets = append(newPrim.Targets, newTarget)
+	}
+	if prim.Indices != nil {
+		if newPrim.Indices, err = e.accessor(*prim.Indices); err != nil {
+			return nil, err
+		}
+	}
+	if prim.Material != nil {
+		if newPrim.Material, err = e.material(*prim.Material); err != nil {
+			return nil, err
+		}
+	}
+	mesh := d.Meshes[meshIndex]
+	e.dst.Meshes = []Mesh{{Name: mesh.Name, Weights: mesh.Weights, Primitives: []Primitive{newPrim}}}
+	e.dst.Nodes = []Node{{Mesh: Index(0)}}
+	e.dst.Scenes = []Scene{{Nodes: []uint32{0}}}
+	e.dst.Scene = Index(0)
+	if e.dst.Buffers[0].ByteLength == 0 {
+		e.dst.Buffers = nil
+	}
+	return e.dst, nil
+}
+
+// extractor copies elements from src to dst, keeping track of the already copied ones.
+type extractor struct {
+	src, dst    *Document
+	accessors   map[uint32]uint32
+	bufferViews map[uint32]uint32
+	textures    map[uint32]uint32
+	images      map[uint32]uint32
+	samplers    map[uint32]uint32
+}
+
+func (e *extractor) attributes(attrs Attribute) (Attribute, error) {
+	out := make(Attribute, len(attrs))
+	for _, name := range sortedAttributes(attrs) {
+		newIndex, err := e.accessor(attrs[name])
+		if err != nil {
+			return nil, err
+		}
+		out[name] = *newIndex
+	}
+	return out, nil
+}
+
+func (e *extractor) accessor(index uint32) (*uint32, error) {
+	if newIndex, ok := e.accessors[index]; ok {
+		return Index(newIndex), nil
+	}
+	if int(index) >= len(e.src.Accessors) {
+		return nil, fmt.Errorf("gltf: accessor index %d out of range", index)
+	}
+	acc := e.src.Accessors[index]
+	acc.Min, acc.Max = append([]float64(nil), acc.Min...), append([]float64(nil), acc.Max...)
+	var err error
+	if acc.BufferView != nil {
+		if acc.BufferView, err = e.bufferView(*acc.BufferView); err != nil {
+			return nil, err
+		}
+	}
+	if acc.Sparse != nil {
+		sparse := *acc.Sparse
+		indices, err := e.bufferView(sparse.Indices.BufferView)
+		if err != nil {
+			return nil, err
+		}
+		values, err := e.bufferView(sparse.Values.BufferView)
+		if err != nil {
+			return nil, err
+		}
+		sparse.Indices.BufferView, sparse.Values.BufferView = *indices, *values
+		acc.Sparse = &sparse
+	}
+	e.dst.Accessors = append(e.dst.Accessors, acc)
+	e.accessors[index] = uint32(len(e.dst.Accessors) - 1)
+	return Index(e.accessors[index]), nil
+}
+
+func (e *extractor) bufferView(index uint32) (*uint32, error) {
+	if newIndex, ok := e.bufferViews[index]; ok {
+		return Index(newIndex), nil
+	}
+	if int(index) >= len(e.src.BufferViews) {
+		return nil, fmt.Errorf("gltf: bufferView index %d out of range", index)
+	}
+	data, err := e.src.bufferViewData(index)
+	if err != nil {
+		return nil, err
+	}
+	bv := e.src.BufferViews[index]
+	newIndex, err := e.dst.appendBufferView(0, data, bv.ByteStride, bv.Target)
+	if err != nil {
+		return nil, err
+	}
+	newView := &e.dst.BufferViews[newIndex]
+	newView.Extensions, newView.Extras = bv.Extensions, bv.Extras
+	e.bufferViews[index] = newIndex
+	return Index(newIndex), nil
+}
+
+func (e *extractor) material(index uint32) (*uint32, error) {
+	if int(index) >= len(e.src.Materials) {
+		return nil, fmt.Errorf("gltf: material index %d out of range", index)
+	}
+	mat := e.src.Materials[index]
+	var err error
+	if mat.PBRMetallicRoughness != nil {
+		pbr := *mat.PBRMetallicRoughness
+		if pbr.BaseColorTexture, err = e.textureInfo(pbr.BaseColorTexture); err != nil {
+			return nil, err
+		}
+		if pbr.MetallicRoughnessTexture, err = e.textureInfo(pbr.MetallicRoughnessTexture); err != nil {
+			return nil, err
+		}
+		mat.PBRMetallicRoughness = &pbr
+	}
+	if mat.EmissiveTexture, err = e.textureInfo(mat.EmissiveTexture); err != nil {
+		return nil, err
+	}
+	if mat.NormalTexture != nil && mat.NormalTexture.Index != nil {
+		normal := *mat.NormalTexture
+		if normal.Index, err = e.texture(*normal.Index); err != nil {
+			return nil, err
+		}
+		mat.NormalTexture = &normal
+	}
+	if mat.OcclusionTexture != nil && mat.OcclusionTexture.Index != nil {
+		occlusion := *mat.OcclusionTexture
+		if occlusion.Index, err = e.texture(*occlusion.Index); err != nil {
+			return nil, err
+		}
+		mat.OcclusionTexture = &occlusion
+	}
+	e.dst.Materials = append(e.dst.Materials, mat)
+	return Index(uint32(len(e.dst.Materials) - 1)), nil
+}
+
+func (e *extractor) textureInfo(info *TextureInfo) (*TextureInfo, error) {
+	if info == nil {
+		return nil, nil
+	}
+	newIndex, err := e.texture(info.Index)
+	if err != nil {
+		return nil, err
+	}
+	newInfo := *info
+	newInfo.Index = *newIndex
+	return &newInfo, nil
+}
+
+func (e *extractor) texture(index uint32) (*uint32, error) {
+	if newIndex, ok := e.textures[index]; ok {
+		return Index(newIndex), nil
+	}
+	if int(index) >= len(e.src.Textures) {
+		return nil, fmt.Errorf("gltf: texture index %d out of range", index)
+	}
+	tex := e.src.Textures[index]
+	var err error
+	if tex.Sampler != nil {
+		if tex.Sampler, err = e.sampler(*tex.Sampler); err != nil {
+			return nil, err
+		}
+	}
+	if tex.Source != nil {
+		if tex.Source, err = e.image(*tex.Source); err != nil {
+			return nil, err
+		}
+	}
+	e.dst.Textures = append(e.dst.Textures, tex)
+	e.textures[index] = uint32(len(e.dst.Textures) - 1)
+	return Index(e.textures[index]), nil
+}
+
+func (e *extractor) sampler(index uint32) (*uint32, error) {
+	if newIndex, ok := e.samplers[index]; ok {
+		return Index(newIndex), nil
+	}
+	if int(index) >= len(e.src.Samplers) {
+		return nil, fmt.Errorf("gltf: sampler index %d out of range", index)
+	}
+	e.dst.Samplers = append(e.dst.Samplers, e.src.Samplers[index])
+	e.samplers[index] = uint32(len(e.dst.Samplers) - 1)
+	return Index(e.samplers[index]), nil
+}
+
+func (e *extractor) image(index uint32) (*uint32, error) {
+	if newIndex, ok := e.images[index]; ok {
+		return Index(newIndex), nil
+	}
+	if int(index) >= len(e.src.Images) {
+		return nil, fmt.Errorf("gltf: image index %d out of range", index)
+	}
+	img := e.src.Images[index]
+	// Images without URI are stored in a bufferView.
+	if img.URI == "" {
+		bv, err := e.bufferView(img.BufferView)
+		if err != nil {
+			return nil, err
+		}
+		img.BufferView = *bv
+	}
+	e.dst.Images = append(e.dst.Images, img)
+	e.images[index] = uint32(len(e.dst.Images) - 1)
+	return Index(e.images[index]), nil
+}
@@ -0,0 +1,113 @@
+package gltf
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDocument_ExtractPrimitive(t *testing.T) {
+	doc := &Document{
+		Asset: Asset{Version: "2.0", Generator: "gltf"},
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: UnsignedByte, Count: 4, Type: Scalar},
+			{BufferView: Index(1), ComponentType: UnsignedByte, Count: 4, Type: Scalar},
+			{ComponentType: Float, Count: 4, Type: Vec3},
+		},
+		BufferViews: []BufferView{
+			{ByteOffset: 0, ByteLength: 4, Target: ElementArrayBuffer},
+			{ByteOffset: 4, ByteLength: 4, Target: ElementArrayBuffer},
+			{ByteOffset: 8, ByteLength: 4},
+		},
+		Buffers: []Buffer{{ByteLength: 12, Data: []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}}},
+		Images:  []Image{{URI: "a.png"}, {MimeType: "image/png", BufferView: 2}},
+		Materials: []Material{{Name: "a"}, {
+			Name:                 "b",
+			PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 1}},
+			NormalTexture:        &NormalTexture{Index: Index(1)},
+		}},
+		Meshes: []Mesh{
+			{Primitives: []Primitive{{Indices: Index(0), Attributes: Attribute{"POSITION": 2}}}},
+			{Name: "m", Primitives: []Primitive{
+				{Indices: Index(0), Attributes: Attribute{"POSITION": 2}},
+				{Indices: Index(1), Material: Index(1), Attributes: Attribute{"POSITION": 2, "_ID": 2}},
+				{Indices: Index(3), Attributes: Attribute{"POSITION": 2}},
+			}},
+		},
+		Samplers: []Sampler{{Name: "s0"}, {Name: "s1"}},
+		Textures: []Texture{{Source: Index(0)}, {Sampler: Index(1), Source: Index(1)}},
+	}
+	want := &Document{
+		Asset: Asset{Version: "2.0", Generator: "gltf"},
+		Accessors: []Accessor{
+			{ComponentType: Float, Count: 4, Type: Vec3},
+			{BufferView: Index(0), ComponentType: UnsignedByte, Count: 4, Type: Scalar},
+		},
+		BufferViews: []BufferView{
+			{ByteOffset: 0, ByteLength: 4, Target: ElementArrayBuffer},
+			{ByteOffset: 4, ByteLength: 4},
+		},
+		Buffers: []Buffer{{ByteLength: 8, Data: []byte{5, 6, 7, 8, 9, 10, 11, 12}}},
+		Images:  []Image{{MimeType: "image/png", BufferView: 1}},
+		Materials: []Material{{
+			Name:                 "b",
+			PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 0}},
+			NormalTexture:        &NormalTexture{Index: Index(0)},
+		}},
+		Meshes:   []Mesh{{Name: "m", Primitives: []Primitive{{Indices: Index(1), Material: Index(0), Attributes: Attribute{"POSITION": 0, "_ID": 0}}}}},
+		Nodes:    []Node{{Mesh: Index(0)}},
+		Scene:    Index(0),
+		Scenes:   []Scene{{Nodes: []uint32{0}}},
+		Samplers: []Sampler{{Name: "s1"}},
+		Textures: []Texture{{Sampler: Index(0), Source: Index(0)}},
+	}
+	got, err := doc.ExtractPrimitive(1, 1)
+	if err != nil {
+		t.Fatalf("Document.ExtractPrimitive() error = %v", err)
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("Document.ExtractPrimitive() = %v", diff)
+	}
+	if doc.Materials[1].PBRMetallicRoughness.BaseColorTexture.Index != 1 || *doc.Materials[1].NormalTexture.Index != 1 {
+		t.Error("Document.ExtractPrimitive() modified the source material")
+	}
+	got, err = doc.ExtractPrimitive(0, 0)
+	if err != nil {
+		t.Fatalf("Document.ExtractPrimitive() error = %v", err)
+	}
+	if len(got.Buffers) != 1 || len(got.Materials) != 0 {
+		t.Errorf("Document.ExtractPrimitive() = %v", got)
+	}
+	if _, err = doc.ExtractPrimitive(1, 2); err == nil {
+		t.Error("Document.ExtractPrimitive() expected accessor error")
+	}
+	if _, err = doc.ExtractPrimitive(2, 0); err == nil {
+		t.Error("Document.ExtractPrimitive() expected mesh error")
+	}
+}
+
+func TestDocument_ExtractPrimitive_Order(t *testing.T) {
+	doc := &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: UnsignedByte, Count: 4, Type: Scalar, Min: []float64{1}, Max: []float64{4}},
+			{BufferView: Index(1), ComponentType: UnsignedByte, Count: 4, Type: Scalar},
+			{BufferView: Index(2), ComponentType: UnsignedByte, Count: 4, Type: Scalar},
+		},
+		BufferViews: []BufferView{{ByteLength: 4}, {ByteOffset: 4, ByteLength: 4}, {ByteOffset: 8, ByteLength: 4}},
+		Buffers:     []Buffer{{ByteLength: 12, Data: []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}}},
+		Meshes:      []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"_C": 0, "_A": 2, "_B": 1}}}}},
+	}
+	for i := 0; i < 10; i++ {
+		got, err := doc.ExtractPrimitive(0, 0)
+		if err != nil {
+			t.Fatalf("Document.ExtractPrimitive() error = %v", err)
+		}
+		if diff := deep.Equal(got.Buffers[0].Data, []byte{9, 10, 11, 12, 5, 6, 7, 8, 1, 2, 3, 4}); diff != nil {
+			t.Fatalf("Document.ExtractPrimitive() = %v", diff)
+		}
+		got.Accessors[2].Min[0] = 0
+		if doc.Accessors[0].Min[0] != 1 {
+			t.Fatal("Document.ExtractPrimitive() shares the accessor bounds with the source")
+		}
+	}
+}
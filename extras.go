@@ -0,0 +1,83 @@
+package gltf
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+var emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// decodeWithExtras decodes the next JSON value of jd into v
+// and then replaces the extras of every property with the value returned by fn.
+func decodeWithExtras(jd *json.Decoder, v interface{}, fn func(json.RawMessage) (interface{}, error)) error {
+	var raw json.RawMessage
+	if err := jd.Decode(&raw); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return err
+	}
+	return walkExtras(reflect.ValueOf(v), raw, fn)
+}
+
+// walkExtras traverses v in parallel with its JSON representation,
+// setting the extras fields to the value returned by fn for the raw extras.
+func walkExtras(v reflect.Value, raw json.RawMessage, fn func(json.RawMessage) (interface{}, error)) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return walkExtras(v.Elem(), raw, fn)
+		}
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(raw, &obj) != nil {
+			return nil
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			value, ok := obj[name]
+			if !ok || field.PkgPath != "" {
+				continue
+			}
+			if name == "extras" && field.Type == emptyInterfaceType {
+				extras, err := fn(value)
+				if err != nil {
+					return err
+				}
+				v.Field(i).Set(reflect.ValueOf(extras))
+			} else if err := walkExtras(v.Field(i), value, fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var arr []json.RawMessage
+		if json.Unmarshal(raw, &arr) != nil {
+			return nil
+		}
+		for i := 0; i < len(arr) && i < v.Len(); i++ {
+			if err := walkExtras(v.Index(i), arr[i], fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		var obj map[string]json.RawMessage
+		if v.Type().Key().Kind() != reflect.String || json.Unmarshal(raw, &obj) != nil {
+			return nil
+		}
+		for key, value := range obj {
+			elem := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+			if elem.IsValid() && elem.Kind() == reflect.Interface {
+				elem = elem.Elem()
+			}
+			if elem.IsValid() && elem.Kind() == reflect.Ptr {
+				if err := walkExtras(elem, value, fn); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
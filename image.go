@@ -0,0 +1,142 @@
+package gltf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// ImageSize returns the dimensions of an image by reading its header with image.DecodeConfig,
+// so the decoder of its format must be registered, for example by importing image/png and image/jpeg.
+// Only images embedded as a data URI or stored in a bufferView can be read,
+// as external images are not loaded by the decoder.
+func (d *Document) ImageSize(imageIndex uint32) (width, height int, err error) {
+	if int(imageIndex) >= len(d.Images) {
+		return 0, 0, fmt.Errorf("gltf: image index %d out of range", imageIndex)
+	}
+	data, err := d.imageData(&d.Images[imageIndex])
+	if err != nil {
+		return 0, 0, err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// DecodeImage decodes an image with image.Decode, so its format is detected from its data.
+// No format is registered by this package: the decoders must be registered with image.RegisterFormat,
+// usually by importing their package for its side effects, such as image/png and image/jpeg
+// for the formats of the core specification.
+// External images are read with cb, which can be nil if the document has none.
+func (d *Document) DecodeImage(imageIndex uint32, cb ReadResourceCallback) (image.Image, error) {
+	if int(imageIndex) >= len(d.Images) {
+		return nil, fmt.Errorf("gltf: image index %d out of range", imageIndex)
+	}
+	img := &d.Images[imageIndex]
+	if img.URI != "" && !img.IsEmbeddedResource() {
+		if cb == nil {
+			return nil, fmt.Errorf("gltf: no callback to read the external image '%s'", img.URI)
+		}
+		r, err := cb(img.URI)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		m, _, err := image.Decode(r)
+		return m, err
+	}
+	data, err := d.imageData(img)
+	if err != nil {
+		return nil, err
+	}
+	m, _, err := image.Decode(bytes.NewReader(data))
+	return m, err
+}
+
+// imageData returns the encoded data of an image embedded as a data URI or stored in a bufferView.
+func (d *Document) imageData(img *Image) ([]byte, error) {
+	switch {
+	case img.IsEmbeddedResource():
+		return img.MarshalData()
+	case img.URI == "":
+		if int(img.BufferView) >= len(d.BufferViews) {
+			return nil, fmt.Errorf("gltf: bufferView index %d out of range", img.BufferView)
+		}
+		return d.bufferViewData(img.BufferView)
+	default:
+		return nil, errors.New("gltf: external image data is not loaded")
+	}
+}
+
+// ExtractImages writes the images embedded as a data URI or stored in a bufferView to files in dir
+// and makes them reference those files with a relative URI.
+// The URI of each image is the name returned by namer, which defaults to image<i> plus the extension of its mime type.
+// The bufferViews that only held image data are removed, but the buffer data is not modified,
+// use MergeBuffers to drop the bytes no longer covered by a bufferView.
+// Images that already reference an external file are left untouched.
+func (d *Document) ExtractImages(dir string, namer func(i int, img *Image) string) error {
+	if namer == nil {
+		namer = defaultImageName
+	}
+	extracted := make(map[uint32]bool)
+	for i := range d.Images {
+		img := &d.Images[i]
+		if img.URI != "" && !img.IsEmbeddedResource() {
+			continue
+		}
+		data, err := d.imageData(img)
+		if err != nil {
+			return err
+		}
+		name := namer(i, img)
+		if err = validateBufferURI(name); err != nil {
+			return fmt.Errorf("gltf: invalid image name '%s'", name)
+		}
+		if err = ioutil.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), data, 0644); err != nil {
+			return err
+		}
+		if img.URI == "" {
+			extracted[img.BufferView] = true
+		}
+		img.URI, img.BufferView = name, 0
+	}
+	if len(extracted) > 0 {
+		keep := make([]bool, len(d.BufferViews))
+		for i := range keep {
+			keep[i] = !extracted[uint32(i)]
+		}
+		d.walkReferences(func(kind elementKind, index *uint32) {
+			if kind == kindBufferView && int(*index) < len(keep) {
+				keep[*index] = true
+			}
+		})
+		d.compact(kindBufferView, keep)
+	}
+	return nil
+}
+
+// defaultImageName returns image<i> with the file extension of the image mime type.
+func defaultImageName(i int, img *Image) string {
+	mimeType := img.MimeType
+	if mimeType == "" {
+		mimeType = strings.TrimSuffix(strings.TrimPrefix(img.embeddedMimetype(), "data:"), ";base64")
+	}
+	ext := ".bin"
+	switch mimeType {
+	case "image/png":
+		ext = ".png"
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/avif":
+		ext = ".avif"
+	case "image/ktx2":
+		ext = ".ktx2"
+	}
+	return fmt.Sprintf("image%d%s", i, ext)
+}
@@ -0,0 +1,159 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func encodePNG(width, height int) []byte {
+	buf := new(bytes.Buffer)
+	png.Encode(buf, image.NewGray(image.Rect(0, 0, width, height)))
+	return buf.Bytes()
+}
+
+func TestDocument_ImageSize(t *testing.T) {
+	data := encodePNG(3, 5)
+	doc := &Document{
+		Images: []Image{
+			{URI: "data:image/png;base64," + base64.StdEncoding.EncodeToString(encodePNG(16, 8))},
+			{BufferView: 0, MimeType: "image/png"},
+			{URI: "a.png"},
+			{BufferView: 1, MimeType: "image/png"},
+			{BufferView: 2, MimeType: "image/png"},
+		},
+		BufferViews: []BufferView{{ByteLength: uint32(len(data))}, {ByteLength: 4}},
+		Buffers:     []Buffer{{ByteLength: uint32(len(data)), Data: data}},
+	}
+	tests := []struct {
+		name       string
+		index      uint32
+		wantWidth  int
+		wantHeight int
+		wantErr    bool
+	}{
+		{"embedded", 0, 16, 8, false},
+		{"bufferView", 1, 3, 5, false},
+		{"external", 2, 0, 0, true},
+		{"invalid", 3, 0, 0, true},
+		{"bufferViewOutOfRange", 4, 0, 0, true},
+		{"outOfRange", 5, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotWidth, gotHeight, err := doc.ImageSize(tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.ImageSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotWidth != tt.wantWidth || gotHeight != tt.wantHeight {
+				t.Errorf("Document.ImageSize() = %v, %v, want %v, %v", gotWidth, gotHeight, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestDocument_DecodeImage(t *testing.T) {
+	data := encodePNG(3, 5)
+	doc := &Document{
+		Images: []Image{
+			{URI: "data:image/png;base64," + base64.StdEncoding.EncodeToString(encodePNG(16, 8))},
+			{BufferView: 0, MimeType: "image/png"},
+			{URI: "a.png"},
+			{BufferView: 1, MimeType: "image/png"},
+			{URI: "b.png"},
+		},
+		BufferViews: []BufferView{{ByteLength: uint32(len(data))}, {ByteLength: 4}},
+		Buffers:     []Buffer{{ByteLength: uint32(len(data)), Data: data}},
+	}
+	external := func(uri string) (io.ReadCloser, error) {
+		if uri != "a.png" {
+			return nil, errors.New("not found")
+		}
+		return ioutil.NopCloser(bytes.NewReader(encodePNG(2, 7))), nil
+	}
+	tests := []struct {
+		name    string
+		index   uint32
+		cb      ReadResourceCallback
+		want    image.Rectangle
+		wantErr bool
+	}{
+		{"embedded", 0, nil, image.Rect(0, 0, 16, 8), false},
+		{"bufferView", 1, nil, image.Rect(0, 0, 3, 5), false},
+		{"external", 2, external, image.Rect(0, 0, 2, 7), false},
+		{"externalWithoutCallback", 2, nil, image.Rectangle{}, true},
+		{"externalNotFound", 4, external, image.Rectangle{}, true},
+		{"invalid", 3, nil, image.Rectangle{}, true},
+		{"outOfRange", 5, nil, image.Rectangle{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.DecodeImage(tt.index, tt.cb)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.DecodeImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Bounds() != tt.want {
+				t.Errorf("Document.DecodeImage() bounds = %v, want %v", got.Bounds(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_ExtractImages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gltf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	data := encodePNG(3, 5)
+	embedded := encodePNG(16, 8)
+	position := encodeData([]float32{1, 2, 3})
+	doc := &Document{
+		Accessors: []Accessor{{BufferView: Index(1), ComponentType: Float, Count: 1, Type: Vec3}},
+		Images: []Image{
+			{URI: "data:image/png;base64," + base64.StdEncoding.EncodeToString(embedded)},
+			{BufferView: 0, MimeType: "image/png", Name: "view"},
+			{URI: "a.png"},
+		},
+		BufferViews: []BufferView{{ByteLength: uint32(len(data))}, {ByteOffset: uint32(len(data)), ByteLength: 12}},
+		Buffers:     []Buffer{{ByteLength: uint32(len(data)) + 12, Data: append(append([]byte{}, data...), position...)}},
+	}
+	if err = doc.ExtractImages(dir, nil); err != nil {
+		t.Fatalf("Document.ExtractImages() error = %v", err)
+	}
+	wantImages := []Image{{URI: "image0.png"}, {URI: "image1.png", MimeType: "image/png", Name: "view"}, {URI: "a.png"}}
+	if diff := deep.Equal(doc.Images, wantImages); diff != nil {
+		t.Errorf("Document.ExtractImages() images = %v", diff)
+	}
+	if len(doc.BufferViews) != 1 || *doc.Accessors[0].BufferView != 0 {
+		t.Errorf("Document.ExtractImages() did not remove the image bufferView")
+	}
+	for name, want := range map[string][]byte{"image0.png": embedded, "image1.png": data} {
+		got, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("Document.ExtractImages() wrote %s = %v, %v", name, len(got), err)
+		}
+	}
+
+	doc = &Document{
+		Images:      []Image{{BufferView: 0, MimeType: "image/png"}},
+		BufferViews: []BufferView{{ByteLength: 4}},
+		Buffers:     []Buffer{{ByteLength: 4, Data: []byte{1, 2, 3, 4}}},
+	}
+	if err = doc.ExtractImages(dir, func(int, *Image) string { return "../a.png" }); err == nil {
+		t.Error("Document.ExtractImages() expected error with an invalid name")
+	}
+	if err = doc.ExtractImages(dir, func(i int, img *Image) string { return "textures/" + img.MimeType }); err == nil {
+		t.Error("Document.ExtractImages() expected error writing to a missing directory")
+	}
+}
@@ -0,0 +1,192 @@
+package lightspunctual
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/qmuntal/gltf"
+)
+
+const (
+	// ExtLightsPunctual defines the LightsPunctual unique key.
+	ExtLightsPunctual = "KHR_lights_punctual"
+)
+
+// New returns a new lightspunctual.LightsPunctual.
+func New() json.Unmarshaler {
+	return new(LightsPunctual)
+}
+
+func init() {
+	gltf.RegisterExtension(ExtLightsPunctual, New)
+}
+
+// LightType specifies the type of a light.
+type LightType string
+
+const (
+	// TypeDirectional lights emit along the -Z axis of their node, as if they were infinitely far away.
+	TypeDirectional LightType = "directional"
+	// TypePoint lights emit in all directions from the position of their node.
+	TypePoint LightType = "point"
+	// TypeSpot lights emit in a cone along the -Z axis of their node.
+	TypeSpot LightType = "spot"
+)
+
+// Spot defines the cone of a spot light, with angles in radians measured from its axis.
+// The intensity falls off from the inner to the outer cone angle.
+type Spot struct {
+	InnerConeAngle float64     `json:"innerConeAngle,omitempty"`
+	OuterConeAngle *float64    `json:"outerConeAngle,omitempty"`
+	Extras         interface{} `json:"extras,omitempty"`
+}
+
+// OuterConeAngleOrDefault returns the outer cone angle if it is not nil, else return the default one.
+func (s *Spot) OuterConeAngleOrDefault() float64 {
+	if s.OuterConeAngle == nil {
+		return math.Pi / 4
+	}
+	return *s.OuterConeAngle
+}
+
+// A Light defines a punctual light source.
+// Intensity is in candela for point and spot lights and in lux for directional lights.
+// Range is the distance at which the intensity reaches zero, infinite when it is nil.
+type Light struct {
+	Type      LightType   `json:"type"`
+	Name      string      `json:"name,omitempty"`
+	Color     *gltf.RGB   `json:"color,omitempty"`
+	Intensity *float64    `json:"intensity,omitempty"`
+	Range     *float64    `json:"range,omitempty"`
+	Spot      *Spot       `json:"spot,omitempty"`
+	Extras    interface{} `json:"extras,omitempty"`
+}
+
+// ColorOrDefault returns the color if it is not nil, else return the default one.
+func (l *Light) ColorOrDefault() gltf.RGB {
+	if l.Color == nil {
+		return *gltf.NewRGB()
+	}
+	return *l.Color
+}
+
+// IntensityOrDefault returns the intensity if it is not nil, else return the default one.
+func (l *Light) IntensityOrDefault() float64 {
+	if l.Intensity == nil {
+		return 1
+	}
+	return *l.Intensity
+}
+
+// LightsPunctual defines the KHR_lights_punctual extension, which is used at two levels.
+// In the document extensions it defines the Lights array,
+// and in the extensions of a node it references with Light the light instantiated by the node.
+type LightsPunctual struct {
+	Lights []Light `json:"lights,omitempty"`
+	Light  *uint32 `json:"light,omitempty"`
+}
+
+// UnmarshalJSON unmarshal the lights punctual extension.
+func (l *LightsPunctual) UnmarshalJSON(data []byte) error {
+	type alias LightsPunctual
+	return json.Unmarshal(data, (*alias)(l))
+}
+
+// Params are the parameters of a light instantiated by a node, in the form consumed by a shader.
+// Position is only set for point and spot lights and Direction, the normalized world -Z axis of the node,
+// only for directional and spot lights. Range is zero when the light has no range.
+// The cone cosines and the angle scale and offset, which give the spot attenuation as
+// saturate(dot(Direction, -l) * AngleScale + AngleOffset) squared, are only set for spot lights.
+type Params struct {
+	Type         LightType
+	Color        [3]float64
+	Intensity    float64
+	Range        float64
+	Position     [3]float64
+	Direction    [3]float64
+	InnerConeCos float64
+	OuterConeCos float64
+	AngleScale   float64
+	AngleOffset  float64
+}
+
+// Attenuation returns the distance attenuation of the light at the given distance,
+// which is the inverse square law windowed to reach zero at the range, if any.
+func (p *Params) Attenuation(distance float64) float64 {
+	if distance <= 0 {
+		return 1
+	}
+	attenuation := 1 / (distance * distance)
+	if p.Range > 0 {
+		window := 1 - math.Pow(distance/p.Range, 4)
+		attenuation *= math.Max(0, math.Min(1, window))
+	}
+	return attenuation
+}
+
+// Effective returns the parameters of the light instantiated by the node at nodeIndex,
+// combining the light definition with the world transform of the node.
+// It fails if the node does not reference a light, the light is not defined in the document,
+// its type is unknown or the node hierarchy contains a cycle.
+func Effective(doc *gltf.Document, nodeIndex uint32) (*Params, error) {
+	if int(nodeIndex) >= len(doc.Nodes) {
+		return nil, fmt.Errorf("gltf: node index %d out of range", nodeIndex)
+	}
+	ref, ok := doc.Nodes[nodeIndex].Extensions[ExtLightsPunctual].(*LightsPunctual)
+	if !ok || ref.Light == nil {
+		return nil, errors.New("gltf: node does not have a light")
+	}
+	var lights []Light
+	if root, ok := doc.Extensions[ExtLightsPunctual].(*LightsPunctual); ok {
+		lights = root.Lights
+	}
+	if int(*ref.Light) >= len(lights) {
+		return nil, fmt.Errorf("gltf: light index %d out of range", *ref.Light)
+	}
+	light := &lights[*ref.Light]
+	world, err := doc.WorldMatrix(nodeIndex)
+	if err != nil {
+		return nil, err
+	}
+	color := light.ColorOrDefault()
+	p := &Params{
+		Type:      light.Type,
+		Color:     [3]float64{color.R, color.G, color.B},
+		Intensity: light.IntensityOrDefault(),
+	}
+	if light.Range != nil {
+		p.Range = *light.Range
+	}
+	switch light.Type {
+	case TypeDirectional:
+		p.Direction = direction(world)
+	case TypePoint:
+		p.Position = [3]float64{world[12], world[13], world[14]}
+	case TypeSpot:
+		p.Position = [3]float64{world[12], world[13], world[14]}
+		p.Direction = direction(world)
+		spot := light.Spot
+		if spot == nil {
+			spot = new(Spot)
+		}
+		p.InnerConeCos = math.Cos(spot.InnerConeAngle)
+		p.OuterConeCos = math.Cos(spot.OuterConeAngleOrDefault())
+		p.AngleScale = 1 / math.Max(0.001, p.InnerConeCos-p.OuterConeCos)
+		p.AngleOffset = -p.OuterConeCos * p.AngleScale
+	default:
+		return nil, fmt.Errorf("gltf: unknown light type %s", light.Type)
+	}
+	return p, nil
+}
+
+// direction returns the normalized -Z axis of a column-major world matrix.
+func direction(world [16]float64) [3]float64 {
+	d := [3]float64{-world[8], -world[9], -world[10]}
+	length := math.Sqrt(d[0]*d[0] + d[1]*d[1] + d[2]*d[2])
+	if length == 0 {
+		return d
+	}
+	return [3]float64{d[0] / length, d[1] / length, d[2] / length}
+}
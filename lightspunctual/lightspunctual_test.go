@@ -0,0 +1,126 @@
+package lightspunctual
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/qmuntal/gltf"
+)
+
+func TestLightsPunctual_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    *LightsPunctual
+		wantErr bool
+	}{
+		{"default", []byte("{}"), new(LightsPunctual), false},
+		{"lights", []byte(`{"lights": [{"type": "point", "color": [1, 0, 0], "range": 5}, {"type": "spot", "spot": {"innerConeAngle": 0.5}}]}`), &LightsPunctual{
+			Lights: []Light{
+				{Type: TypePoint, Color: &gltf.RGB{R: 1}, Range: gltf.Float64(5)},
+				{Type: TypeSpot, Spot: &Spot{InnerConeAngle: 0.5}},
+			},
+		}, false},
+		{"light", []byte(`{"light": 1}`), &LightsPunctual{Light: gltf.Index(1)}, false},
+		{"invalid", []byte(`{"lights": 1}`), new(LightsPunctual), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(LightsPunctual)
+			if err := got.UnmarshalJSON(tt.data); (err != nil) != tt.wantErr {
+				t.Errorf("LightsPunctual.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LightsPunctual.UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffective(t *testing.T) {
+	data := []byte(`{"asset": {"version": "2.0"},
+	"extensionsUsed": ["KHR_lights_punctual"],
+	"extensions": {"KHR_lights_punctual": {"lights": [
+		{"type": "directional", "intensity": 3},
+		{"type": "point", "color": [1, 0.5, 0], "range": 10},
+		{"type": "spot", "spot": {"innerConeAngle": 0, "outerConeAngle": 1.0471975511965976}},
+		{"type": "area"}
+	]}},
+	"nodes": [
+		{"children": [1, 2, 3], "translation": [0, 0, 5]},
+		{"translation": [1, 0, 0], "rotation": [0, 0.7071067811865476, 0, 0.7071067811865476], "extensions": {"KHR_lights_punctual": {"light": 0}}},
+		{"translation": [0, 2, 0], "scale": [3, 3, 3], "extensions": {"KHR_lights_punctual": {"light": 1}}},
+		{"translation": [0, 0, 1], "extensions": {"KHR_lights_punctual": {"light": 2}}},
+		{"extensions": {"KHR_lights_punctual": {"light": 3}}},
+		{"extensions": {"KHR_lights_punctual": {"light": 4}}},
+		{}
+	]}`)
+	doc := new(gltf.Document)
+	if err := gltf.NewDecoder(bytes.NewReader(data), nil).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		node    uint32
+		want    *Params
+		wantErr bool
+	}{
+		{"directional", 1, &Params{Type: TypeDirectional, Color: [3]float64{1, 1, 1}, Intensity: 3, Direction: [3]float64{-1, 0, 0}}, false},
+		{"point", 2, &Params{Type: TypePoint, Color: [3]float64{1, 0.5, 0}, Intensity: 1, Range: 10, Position: [3]float64{0, 2, 5}}, false},
+		{"spot", 3, &Params{Type: TypeSpot, Color: [3]float64{1, 1, 1}, Intensity: 1, Position: [3]float64{0, 0, 6}, Direction: [3]float64{0, 0, -1},
+			InnerConeCos: 1, OuterConeCos: 0.5, AngleScale: 2, AngleOffset: -1}, false},
+		{"unknownType", 4, nil, true},
+		{"lightOutOfRange", 5, nil, true},
+		{"noLight", 6, nil, true},
+		{"nodeOutOfRange", 7, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Effective(doc, tt.node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Effective() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			gotValues := []float64{got.Intensity, got.Range, got.InnerConeCos, got.OuterConeCos, got.AngleScale, got.AngleOffset}
+			wantValues := []float64{tt.want.Intensity, tt.want.Range, tt.want.InnerConeCos, tt.want.OuterConeCos, tt.want.AngleScale, tt.want.AngleOffset}
+			for _, v := range [][2][3]float64{{got.Color, tt.want.Color}, {got.Position, tt.want.Position}, {got.Direction, tt.want.Direction}} {
+				gotValues = append(gotValues, v[0][:]...)
+				wantValues = append(wantValues, v[1][:]...)
+			}
+			if got.Type != tt.want.Type {
+				t.Errorf("Effective() type = %v, want %v", got.Type, tt.want.Type)
+			}
+			for i := range gotValues {
+				if math.Abs(gotValues[i]-wantValues[i]) > 1e-9 {
+					t.Fatalf("Effective() = %+v, want %+v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParams_Attenuation(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        *Params
+		distance float64
+		want     float64
+	}{
+		{"zero", &Params{}, 0, 1},
+		{"infinite", &Params{}, 2, 0.25},
+		{"inRange", &Params{Range: 4}, 2, 0.25 * (1 - 1.0/16)},
+		{"outOfRange", &Params{Range: 1}, 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Attenuation(tt.distance); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Params.Attenuation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package gltf
+
+import "math"
+
+// mulMatrix returns the product a*b of two column-major 4x4 matrices.
+func mulMatrix(a, b [16]float64) [16]float64 {
+	var m [16]float64
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			var sum float64
+			for k := 0; k < 4; k++ {
+				sum += a[k*4+row] * b[col*4+k]
+			}
+			m[col*4+row] = sum
+		}
+	}
+	return m
+}
+
+// composeMatrix returns the column-major matrix equivalent to T * R * S.
+func composeMatrix(t [3]float64, r [4]float64, s [3]float64) [16]float64 {
+	x, y, z, w := r[0], r[1], r[2], r[3]
+	return [16]float64{
+		(1 - 2*(y*y+z*z)) * s[0], (2 * (x*y + z*w)) * s[0], (2 * (x*z - y*w)) * s[0], 0,
+		(2 * (x*y - z*w)) * s[1], (1 - 2*(x*x+z*z)) * s[1], (2 * (y*z + x*w)) * s[1], 0,
+		(2 * (x*z + y*w)) * s[2], (2 * (y*z - x*w)) * s[2], (1 - 2*(x*x+y*y)) * s[2], 0,
+		t[0], t[1], t[2], 1,
+	}
+}
+
+// localMatrix returns the node transform relative to its parent.
+// The matrix property takes precedence over the TRS properties when it is not the default one.
+func localMatrix(n *Node) [16]float64 {
+	if m := n.MatrixOrDefault(); m != DefaultMatrix {
+		return m
+	}
+	return composeMatrix(n.TranslationOrDefault(), n.RotationOrDefault(), n.ScaleOrDefault())
+}
+
+// quaternionLength returns the euclidean norm of the quaternion.
+func quaternionLength(q [4]float64) float64 {
+	return math.Sqrt(q[0]*q[0] + q[1]*q[1] + q[2]*q[2] + q[3]*q[3])
+}
+
+// sub returns the vector a - b.
+func sub(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+// dot returns the dot product of two vectors.
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+// cross returns the cross product of two vectors.
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+// srgbToLinear converts a color component from the sRGB transfer function to linear,
+// as defined by IEC 61966-2-1.
+func srgbToLinear(c float64) float64 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB converts a linear color component to the sRGB transfer function.
+func linearToSRGB(c float64) float64 {
+	if c <= 0.0031308 {
+		return c * 12.92
+	}
+	return 1.055*math.Pow(c, 1/2.4) - 0.055
+}
+
+// invertMatrix returns the inverse of a 4x4 matrix.
+// The boolean is false if the matrix is singular.
+func invertMatrix(m [16]float64) ([16]float64, bool) {
+	var inv [16]float64
+	inv[0] = m[5]*m[10]*m[15] - m[5]*m[11]*m[14] - m[9]*m[6]*m[15] + m[9]*m[7]*m[14] + m[13]*m[6]*m[11] - m[13]*m[7]*m[10]
+	inv[4] = -m[4]*m[10]*m[15] + m[4]*m[11]*m[14] + m[8]*m[6]*m[15] - m[8]*m[7]*m[14] - m[12]*m[6]*m[11] + m[12]*m[7]*m[10]
+	inv[8] = m[4]*m[9]*m[15] - m[4]*m[11]*m[13] - m[8]*m[5]*m[15] + m[8]*m[7]*m[13] + m[12]*m[5]*m[11] - m[12]*m[7]*m[9]
+	inv[12] = -m[4]*m[9]*m[14] + m[4]*m[10]*m[13] + m[8]*m[5]*m[14] - m[8]*m[6]*m[13] - m[12]*m[5]*m[10] + m[12]*m[6]*m[9]
+	inv[1] = -m[1]*m[10]*m[15] + m[1]*m[11]*m[14] + m[9]*m[2]*m[15] - m[9]*m[3]*m[14] - m[13]*m[2]*m[11] + m[13]*m[3]*m[10]
+	inv[5] = m[0]*m[10]*m[15] - m[0]*m[11]*m[14] - m[8]*m[2]*m[15] + m[8]*m[3]*m[14] + m[12]*m[2]*m[11] - m[12]*m[3]*m[10]
+	inv[9] = -m[0]*m[9]*m[15] + m[0]*m[11]*m[13] + m[8]*m[1]*m[15] - m[8]*m[3]*m[13] - m[12]*m[1]*m[11] + m[12]*m[3]*m[9]
+	inv[13] = m[0]*m[9]*m[14] - m[0]*m[10]*m[13] - m[8]*m[1]*m[14] + m[8]*m[2]*m[13] + m[12]*m[1]*m[10] - m[12]*m[2]*m[9]
+	inv[2] = m[1]*m[6]*m[15] - m[1]*m[7]*m[14] - m[5]*m[2]*m[15] + m[5]*m[3]*m[14] + m[13]*m[2]*m[7] - m[13]*m[3]*m[6]
+	inv[6] = -m[0]*m[6]*m[15] + m[0]*m[7]*m[14] + m[4]*m[2]*m[15] - m[4]*m[3]*m[14] - m[12]*m[2]*m[7] + m[12]*m[3]*m[6]
+	inv[10] = m[0]*m[5]*m[15] - m[0]*m[7]*m[13] - m[4]*m[1]*m[15] + m[4]*m[3]*m[13] + m[12]*m[1]*m[7] - m[12]*m[3]*m[5]
+	inv[14] = -m[0]*m[5]*m[14] + m[0]*m[6]*m[13] + m[4]*m[1]*m[14] - m[4]*m[2]*m[13] - m[12]*m[1]*m[6] + m[12]*m[2]*m[5]
+	inv[3] = -m[1]*m[6]*m[11] + m[1]*m[7]*m[10] + m[5]*m[2]*m[11] - m[5]*m[3]*m[10] - m[9]*m[2]*m[7] + m[9]*m[3]*m[6]
+	inv[7] = m[0]*m[6]*m[11] - m[0]*m[7]*m[10] - m[4]*m[2]*m[11] + m[4]*m[3]*m[10] + m[8]*m[2]*m[7] - m[8]*m[3]*m[6]
+	inv[11] = -m[0]*m[5]*m[11] + m[0]*m[7]*m[9] + m[4]*m[1]*m[11] - m[4]*m[3]*m[9] - m[8]*m[1]*m[7] + m[8]*m[3]*m[5]
+	inv[15] = m[0]*m[5]*m[10] - m[0]*m[6]*m[9] - m[4]*m[1]*m[10] + m[4]*m[2]*m[9] + m[8]*m[1]*m[6] - m[8]*m[2]*m[5]
+	det := m[0]*inv[0] + m[1]*inv[4] + m[2]*inv[8] + m[3]*inv[12]
+	if det == 0 {
+		return inv, false
+	}
+	for i := range inv {
+		inv[i] /= det
+	}
+	return inv, true
+}
+
+// decomposeMatrix splits an affine column-major matrix into its translation, rotation and scale.
+// A negative determinant is represented with a negative scale on the x axis. Shear is not preserved.
+func decomposeMatrix(m [16]float64) (t [3]float64, r [4]float64, s [3]float64) {
+	t = [3]float64{m[12], m[13], m[14]}
+	for i := range s {
+		s[i] = math.Sqrt(m[i*4]*m[i*4] + m[i*4+1]*m[i*4+1] + m[i*4+2]*m[i*4+2])
+	}
+	det := m[0]*(m[5]*m[10]-m[6]*m[9]) - m[4]*(m[1]*m[10]-m[2]*m[9]) + m[8]*(m[1]*m[6]-m[2]*m[5])
+	if det < 0 {
+		s[0] = -s[0]
+	}
+	var rm [9]float64 // Rotation matrix in column-major order.
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			if s[col] != 0 {
+				rm[col*3+row] = m[col*4+row] / s[col]
+			}
+		}
+	}
+	r00, r10, r20, r01, r11, r21, r02, r12, r22 := rm[0], rm[1], rm[2], rm[3], rm[4], rm[5], rm[6], rm[7], rm[8]
+	switch trace := r00 + r11 + r22; {
+	case trace > 0:
+		k := 0.5 / math.Sqrt(trace+1)
+		r = [4]float64{(r21 - r12) * k, (r02 - r20) * k, (r10 - r01) * k, 0.25 / k}
+	case r00 > r11 && r00 > r22:
+		k := 2 * math.Sqrt(1+r00-r11-r22)
+		r = [4]float64{0.25 * k, (r01 + r10) / k, (r02 + r20) / k, (r21 - r12) / k}
+	case r11 > r22:
+		k := 2 * math.Sqrt(1+r11-r00-r22)
+		r = [4]float64{(r01 + r10) / k, 0.25 * k, (r12 + r21) / k, (r02 - r20) / k}
+	default:
+		k := 2 * math.Sqrt(1+r22-r00-r11)
+		r = [4]float64{(r02 + r20) / k, (r12 + r21) / k, 0.25 * k, (r10 - r01) / k}
+	}
+	return t, r, s
+}
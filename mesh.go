@@ -0,0 +1,335 @@
+package gltf
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// primitive returns the primitive at primitiveIndex of the mesh at meshIndex.
+func (d *Document) primitive(meshIndex, primitiveIndex uint32) (*Primitive, error) {
+	if int(meshIndex) >= len(d.Meshes) {
+		return nil, fmt.Errorf("gltf: mesh index %d out of range", meshIndex)
+	}
+	mesh := &d.Meshes[meshIndex]
+	if int(primitiveIndex) >= len(mesh.Primitives) {
+		return nil, fmt.Errorf("gltf: primitive index %d out of range", primitiveIndex)
+	}
+	return &mesh.Primitives[primitiveIndex], nil
+}
+
+// AttributeSets returns in ascending order the set indices of the attributes of a semantic family
+// with the form <semantic>_<set>, i.e. the sets of TEXCOORD are 0 and 1 if TEXCOORD_0 and TEXCOORD_1 are defined.
+func AttributeSets(attrs Attribute, semantic string) []uint32 {
+	var sets []uint32
+	prefix := semantic + "_"
+	for name := range attrs {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if set, err := strconv.ParseUint(name[len(prefix):], 10, 32); err == nil {
+			sets = append(sets, uint32(set))
+		}
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i] < sets[j] })
+	return sets
+}
+
+// MaxAttributeSet returns the highest set index of the attributes of a semantic family.
+// The boolean is false if the family does not have any attribute.
+func MaxAttributeSet(attrs Attribute, semantic string) (uint32, bool) {
+	sets := AttributeSets(attrs, semantic)
+	if len(sets) == 0 {
+		return 0, false
+	}
+	return sets[len(sets)-1], true
+}
+
+// RenameAttribute renames the attribute semantic from to the semantic to in all the primitives of a mesh,
+// keeping the referenced accessor. If to is empty the attribute is removed.
+// Primitives without the attribute are left untouched.
+// It fails without modifying the mesh if the resulting attributes of any primitive are not a legal set,
+// because the new semantic already exists, it is not a valid semantic or the indexed sets would not be contiguous.
+func (d *Document) RenameAttribute(meshIndex uint32, from, to string) error {
+	if int(meshIndex) >= len(d.Meshes) {
+		return fmt.Errorf("gltf: mesh index %d out of range", meshIndex)
+	}
+	prims := d.Meshes[meshIndex].Primitives
+	renamed := make([]Attribute, len(prims))
+	for i, prim := range prims {
+		index, ok := prim.Attributes[from]
+		if !ok || from == to {
+			continue
+		}
+		attrs := make(Attribute, len(prim.Attributes))
+		for name, acc := range prim.Attributes {
+			attrs[name] = acc
+		}
+		delete(attrs, from)
+		if to != "" {
+			if _, ok := attrs[to]; ok {
+				return fmt.Errorf("gltf: attribute %s already exists", to)
+			}
+			attrs[to] = index
+		}
+		if err := validateAttributes(attrs); err != nil {
+			return err
+		}
+		renamed[i] = attrs
+	}
+	for i, attrs := range renamed {
+		if attrs != nil {
+			prims[i].Attributes = attrs
+		}
+	}
+	return nil
+}
+
+// indexedSemantics are the attribute semantics that can have multiple sets.
+var indexedSemantics = []string{"TEXCOORD", "COLOR", "JOINTS", "WEIGHTS"}
+
+// validateAttributes checks that the attributes are known semantics, or application-specific ones starting with an underscore,
+// and that the sets of each indexed semantic start with 0 and are contiguous.
+func validateAttributes(attrs Attribute) error {
+	for name := range attrs {
+		if name == "POSITION" || name == "NORMAL" || name == "TANGENT" || strings.HasPrefix(name, "_") {
+			continue
+		}
+		valid := false
+		for _, semantic := range indexedSemantics {
+			if strings.HasPrefix(name, semantic+"_") {
+				_, err := strconv.ParseUint(name[len(semantic)+1:], 10, 32)
+				valid = err == nil
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("gltf: invalid attribute semantic %s", name)
+		}
+	}
+	for _, semantic := range indexedSemantics {
+		for i, set := range AttributeSets(attrs, semantic) {
+			if set != uint32(i) {
+				return fmt.Errorf("gltf: %s sets are not contiguous", semantic)
+			}
+		}
+	}
+	return nil
+}
+
+// InterleaveAttributes copies all the vertex attributes of a primitive into a single new bufferView,
+// with the elements of each vertex stored contiguously and each attribute aligned to 4 bytes,
+// and updates the attribute accessors to point to it.
+// The new bufferView is appended to the buffer that holds the POSITION data, or the first attribute when there is no position.
+// The previous bufferViews are left untouched, even if they are no longer used.
+func (d *Document) InterleaveAttributes(meshIndex, primitiveIndex uint32) error {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return err
+	}
+	names := sortedAttributes(prim.Attributes)
+	if len(names) == 0 {
+		return nil
+	}
+	if _, ok := prim.Attributes["POSITION"]; ok {
+		for i, name := range names {
+			if name == "POSITION" {
+				names[0], names[i] = names[i], names[0]
+				break
+			}
+		}
+	}
+	type source struct {
+		acc    *Accessor
+		r      byteReader
+		size   uint32
+		offset uint32
+	}
+	var (
+		sources = make([]source, len(names))
+		count   uint32
+		stride  uint32
+	)
+	for i, name := range names {
+		index := prim.Attributes[name]
+		if int(index) >= len(d.Accessors) {
+			return fmt.Errorf("gltf: accessor index %d out of range", index)
+		}
+		acc := &d.Accessors[index]
+		if acc.Sparse != nil {
+			return errors.New("gltf: sparse accessors cannot be interleaved")
+		}
+		if i == 0 {
+			count = acc.Count
+		} else if acc.Count != count {
+			return errors.New("gltf: all the attributes of a primitive must have the same count")
+		}
+		view, viewStride, err := d.accessorView(acc)
+		if err != nil {
+			return err
+		}
+		size := elementSize(acc.ComponentType, acc.Type)
+		sources[i] = source{acc: acc, r: byteReader{data: view, stride: viewStride}, size: size, offset: stride}
+		stride += padding4(size)
+	}
+	if count == 0 {
+		return nil
+	}
+	if stride > 252 {
+		return errors.New("gltf: interleaved vertex size exceeds the maximum byteStride")
+	}
+	data := make([]byte, stride*count)
+	for _, src := range sources {
+		for i := uint32(0); i < count; i++ {
+			b, err := src.r.slice(i, 0, src.size)
+			if err != nil {
+				return err
+			}
+			copy(data[i*stride+src.offset:], b)
+		}
+	}
+	bufferIndex := d.BufferViews[*sources[0].acc.BufferView].Buffer
+	bufferView, err := d.appendBufferView(bufferIndex, data, stride, ArrayBuffer)
+	if err != nil {
+		return err
+	}
+	for _, src := range sources {
+		src.acc.BufferView = Index(bufferView)
+		src.acc.ByteOffset = src.offset
+	}
+	return nil
+}
+
+// Unweld converts an indexed primitive into a non-indexed one by duplicating the vertices shared between its elements,
+// so each element has its own vertices. All the attributes and morph targets are expanded consistently
+// into new accessors stored in the buffer that holds the original data.
+// Combined with flat normals it produces flat-shaded geometry.
+// It does nothing if the primitive is not indexed.
+func (d *Document) Unweld(meshIndex, primitiveIndex uint32) error {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return err
+	}
+	if prim.Indices == nil {
+		return nil
+	}
+	indices, err := d.ReadIndices(*prim.Indices)
+	if err != nil {
+		return err
+	}
+	expanded := make(map[uint32]uint32)
+	unweld := func(attrs Attribute) (Attribute, error) {
+		out := make(Attribute, len(attrs))
+		for _, name := range sortedAttributes(attrs) {
+			index := attrs[name]
+			if _, ok := expanded[index]; !ok {
+				newIndex, err := d.unweldAccessor(index, indices)
+				if err != nil {
+					return nil, err
+				}
+				expanded[index] = newIndex
+			}
+			out[name] = expanded[index]
+		}
+		return out, nil
+	}
+	attributes, err := unweld(prim.Attributes)
+	if err != nil {
+		return err
+	}
+	targets := make([]Attribute, len(prim.Targets))
+	for i, target := range prim.Targets {
+		if targets[i], err = unweld(target); err != nil {
+			return err
+		}
+	}
+	prim.Attributes = attributes
+	if len(prim.Targets) > 0 {
+		prim.Targets = targets
+	}
+	prim.Indices = nil
+	return nil
+}
+
+// unweldAccessor creates a new accessor with the elements of the accessor at index in the order defined by indices.
+func (d *Document) unweldAccessor(index uint32, indices []uint32) (uint32, error) {
+	if int(index) >= len(d.Accessors) {
+		return 0, fmt.Errorf("gltf: accessor index %d out of range", index)
+	}
+	acc := d.Accessors[index]
+	if acc.Sparse != nil {
+		return 0, errors.New("gltf: sparse accessors cannot be unwelded")
+	}
+	view, stride, err := d.accessorView(&acc)
+	if err != nil {
+		return 0, err
+	}
+	// Vertex attributes elements must be aligned to 4 bytes.
+	size := elementSize(acc.ComponentType, acc.Type)
+	newStride := padding4(size)
+	data := make([]byte, uint32(len(indices))*newStride)
+	for i, vertex := range indices {
+		if vertex >= acc.Count {
+			return 0, fmt.Errorf("gltf: vertex index %d out of range", vertex)
+		}
+		copy(data[uint32(i)*newStride:], view[vertex*stride:vertex*stride+size])
+	}
+	var byteStride uint32
+	if newStride != size {
+		byteStride = newStride
+	}
+	bufferView, err := d.appendBufferView(d.BufferViews[*acc.BufferView].Buffer, data, byteStride, ArrayBuffer)
+	if err != nil {
+		return 0, err
+	}
+	acc.BufferView = Index(bufferView)
+	acc.ByteOffset = 0
+	acc.Count = uint32(len(indices))
+	d.Accessors = append(d.Accessors, acc)
+	return uint32(len(d.Accessors) - 1), nil
+}
+
+// MorphedAttribute returns the components of the attribute semantic of a primitive, flattened in order,
+// after adding the deltas of the morph targets scaled by their weights: base + Σ weights[i] * target[i].
+// It is commonly used with POSITION, NORMAL and TANGENT. If weights is nil the default weights of the mesh are used,
+// missing weights are 0 and targets without the attribute are skipped.
+// Sparse target accessors, which only store the deltas of the displaced vertices, are supported.
+func (d *Document) MorphedAttribute(meshIndex, primitiveIndex uint32, semantic string, weights []float64) ([]float64, error) {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return nil, err
+	}
+	if weights == nil {
+		weights = d.Meshes[meshIndex].Weights
+	}
+	if len(weights) > len(prim.Targets) {
+		return nil, fmt.Errorf("gltf: %d morph weights for %d morph targets", len(weights), len(prim.Targets))
+	}
+	index, ok := prim.Attributes[semantic]
+	if !ok {
+		return nil, fmt.Errorf("gltf: primitive without %s", semantic)
+	}
+	values, err := d.ReadAccessor(index)
+	if err != nil {
+		return nil, err
+	}
+	for i, weight := range weights {
+		index, ok := prim.Targets[i][semantic]
+		if !ok || weight == 0 {
+			continue
+		}
+		deltas, err := d.ReadAccessor(index)
+		if err != nil {
+			return nil, err
+		}
+		if len(deltas) != len(values) {
+			return nil, fmt.Errorf("gltf: morph target %d %s does not match the attribute size", i, semantic)
+		}
+		for j, delta := range deltas {
+			values[j] += weight * delta
+		}
+	}
+	return values, nil
+}
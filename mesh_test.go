@@ -0,0 +1,266 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func encodeData(data ...interface{}) []byte {
+	buf := new(bytes.Buffer)
+	for _, v := range data {
+		binary.Write(buf, binary.LittleEndian, v)
+	}
+	return buf.Bytes()
+}
+
+func TestDocument_InterleaveAttributes(t *testing.T) {
+	data := encodeData(
+		[]float32{1, 2, 3, 4, 5, 6}, // POSITION
+		[]int8{1, 2, 3, 0, 4, 5, 6}, // NORMAL, second element starts at 4
+		[]uint8{0},
+		[]float32{0.5, 0.25, 0.75, 1}, // TEXCOORD_0
+	)
+	doc := &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: Float, Count: 2, Type: Vec3},
+			{BufferView: Index(1), ComponentType: Byte, Normalized: true, Count: 2, Type: Vec3},
+			{BufferView: Index(2), ComponentType: Float, Count: 2, Type: Vec2},
+		},
+		BufferViews: []BufferView{
+			{Buffer: 0, ByteOffset: 0, ByteLength: 24, Target: ArrayBuffer},
+			{Buffer: 0, ByteOffset: 24, ByteLength: 8, ByteStride: 4, Target: ArrayBuffer},
+			{Buffer: 0, ByteOffset: 32, ByteLength: 16, Target: ArrayBuffer},
+		},
+		Buffers: []Buffer{{ByteLength: uint32(len(data)), Data: data}},
+		Meshes: []Mesh{{Primitives: []Primitive{
+			{Attributes: Attribute{"POSITION": 0, "NORMAL": 1, "TEXCOORD_0": 2}},
+		}}},
+	}
+	if err := doc.InterleaveAttributes(0, 0); err != nil {
+		t.Fatalf("Document.InterleaveAttributes() error = %v", err)
+	}
+	wantView := BufferView{Buffer: 0, ByteOffset: 48, ByteLength: 48, ByteStride: 24, Target: ArrayBuffer}
+	if diff := deep.Equal(doc.BufferViews[3], wantView); diff != nil {
+		t.Errorf("Document.InterleaveAttributes() = %v", diff)
+	}
+	wantData := encodeData(
+		[]float32{1, 2, 3}, []int8{1, 2, 3, 0}, []float32{0.5, 0.25},
+		[]float32{4, 5, 6}, []int8{4, 5, 6, 0}, []float32{0.75, 1},
+	)
+	if got := doc.Buffers[0].Data[48:]; !bytes.Equal(got, wantData) {
+		t.Errorf("Document.InterleaveAttributes() data = %v, want %v", got, wantData)
+	}
+	for i, offset := range []uint32{0, 12, 16} {
+		if acc := doc.Accessors[i]; *acc.BufferView != 3 || acc.ByteOffset != offset {
+			t.Errorf("Document.InterleaveAttributes() accessor %d = (%d, %d), want (3, %d)", i, *acc.BufferView, acc.ByteOffset, offset)
+		}
+	}
+	if doc.Buffers[0].ByteLength != 96 {
+		t.Errorf("Document.InterleaveAttributes() byteLength = %d, want 96", doc.Buffers[0].ByteLength)
+	}
+
+	tests := []struct {
+		name      string
+		doc       *Document
+		mesh      uint32
+		primitive uint32
+	}{
+		{"meshOutOfRange", &Document{}, 0, 0},
+		{"primitiveOutOfRange", &Document{Meshes: []Mesh{{Primitives: []Primitive{{}}}}}, 0, 1},
+		{"countMismatch", &Document{
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Vec3}, {ComponentType: Float, Count: 1, Type: Vec2}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0, "TEXCOORD_0": 1}}}}},
+		}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.doc.InterleaveAttributes(tt.mesh, tt.primitive); err == nil {
+				t.Error("Document.InterleaveAttributes() expected error")
+			}
+		})
+	}
+}
+
+func TestDocument_Unweld(t *testing.T) {
+	data := encodeData(
+		[]uint16{0, 1, 2, 2, 1, 3},                    // indices
+		[]float32{0, 0, 0, 1, 0, 0, 0, 1, 0, 1, 1, 0}, // POSITION
+		[]float32{0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1}, // target POSITION
+	)
+	doc := &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: UnsignedShort, Count: 6, Type: Scalar},
+			{BufferView: Index(1), ComponentType: Float, Count: 4, Type: Vec3, Max: []float64{1, 1, 0}, Min: []float64{0, 0, 0}},
+			{BufferView: Index(2), ComponentType: Float, Count: 4, Type: Vec3},
+		},
+		BufferViews: []BufferView{
+			{Buffer: 0, ByteOffset: 0, ByteLength: 12, Target: ElementArrayBuffer},
+			{Buffer: 0, ByteOffset: 12, ByteLength: 48, Target: ArrayBuffer},
+			{Buffer: 0, ByteOffset: 60, ByteLength: 48, Target: ArrayBuffer},
+		},
+		Buffers: []Buffer{{ByteLength: uint32(len(data)), Data: data}},
+		Meshes: []Mesh{{Primitives: []Primitive{
+			{Indices: Index(0), Attributes: Attribute{"POSITION": 1, "NORMAL": 1}, Targets: []Attribute{{"POSITION": 2}}},
+		}}},
+	}
+	if err := doc.Unweld(0, 0); err != nil {
+		t.Fatalf("Document.Unweld() error = %v", err)
+	}
+	want := Primitive{Attributes: Attribute{"POSITION": 3, "NORMAL": 3}, Targets: []Attribute{{"POSITION": 4}}}
+	if diff := deep.Equal(doc.Meshes[0].Primitives[0], want); diff != nil {
+		t.Errorf("Document.Unweld() = %v", diff)
+	}
+	wantAcc := Accessor{BufferView: Index(3), ComponentType: Float, Count: 6, Type: Vec3, Max: []float64{1, 1, 0}, Min: []float64{0, 0, 0}}
+	if diff := deep.Equal(doc.Accessors[3], wantAcc); diff != nil {
+		t.Errorf("Document.Unweld() = %v", diff)
+	}
+	wantData := encodeData([]float32{0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 1, 0, 1, 0, 0, 1, 1, 0})
+	bv := doc.BufferViews[3]
+	if got := doc.Buffers[0].Data[bv.ByteOffset : bv.ByteOffset+bv.ByteLength]; !bytes.Equal(got, wantData) {
+		t.Errorf("Document.Unweld() data = %v, want %v", got, wantData)
+	}
+	if err := doc.Unweld(0, 0); err != nil {
+		t.Errorf("Document.Unweld() non-indexed error = %v", err)
+	}
+	if err := doc.Unweld(0, 1); err == nil {
+		t.Error("Document.Unweld() expected error")
+	}
+}
+
+func TestAttributeSets(t *testing.T) {
+	attrs := Attribute{"POSITION": 0, "TEXCOORD_0": 1, "TEXCOORD_2": 2, "TEXCOORD_10": 3, "TEXCOORD_": 4, "TEXCOORD_A": 5, "JOINTS_0": 6, "JOINTS_1": 7, "WEIGHTS_0": 8}
+	tests := []struct {
+		semantic string
+		want     []uint32
+		wantMax  uint32
+		wantOk   bool
+	}{
+		{"TEXCOORD", []uint32{0, 2, 10}, 10, true},
+		{"JOINTS", []uint32{0, 1}, 1, true},
+		{"WEIGHTS", []uint32{0}, 0, true},
+		{"COLOR", nil, 0, false},
+		{"POSITION", nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.semantic, func(t *testing.T) {
+			if diff := deep.Equal(AttributeSets(attrs, tt.semantic), tt.want); diff != nil {
+				t.Errorf("AttributeSets() = %v", diff)
+			}
+			got, ok := MaxAttributeSet(attrs, tt.semantic)
+			if got != tt.wantMax || ok != tt.wantOk {
+				t.Errorf("MaxAttributeSet() = %v, %v, want %v, %v", got, ok, tt.wantMax, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestDocument_RenameAttribute(t *testing.T) {
+	unchanged := []Attribute{
+		{"POSITION": 0, "TEXCOORD_0": 1, "TEXCOORD_1": 2, "COLOR_0": 3},
+		{"POSITION": 4, "TEXCOORD_0": 5},
+	}
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		want    []Attribute
+		wantErr bool
+	}{
+		{"remove", "COLOR_0", "", []Attribute{
+			{"POSITION": 0, "TEXCOORD_0": 1, "TEXCOORD_1": 2},
+			{"POSITION": 4, "TEXCOORD_0": 5},
+		}, false},
+		{"rename", "COLOR_0", "_COLOR", []Attribute{
+			{"POSITION": 0, "TEXCOORD_0": 1, "TEXCOORD_1": 2, "_COLOR": 3},
+			{"POSITION": 4, "TEXCOORD_0": 5},
+		}, false},
+		{"missing", "NORMAL", "_NORMAL", unchanged, false},
+		{"exists", "TEXCOORD_1", "TEXCOORD_0", unchanged, true},
+		{"notContiguous", "TEXCOORD_0", "", unchanged, true},
+		{"invalidSemantic", "COLOR_0", "COLOR", unchanged, true},
+		{"invalidSet", "COLOR_0", "COLOR_A", unchanged, true},
+		{"unknownSemantic", "COLOR_0", "OTHER", unchanged, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{Meshes: []Mesh{{Primitives: []Primitive{
+				{Attributes: Attribute{"POSITION": 0, "TEXCOORD_0": 1, "TEXCOORD_1": 2, "COLOR_0": 3}},
+				{Attributes: Attribute{"POSITION": 4, "TEXCOORD_0": 5}},
+			}}}}
+			err := doc.RenameAttribute(0, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.RenameAttribute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			for i, prim := range doc.Meshes[0].Primitives {
+				if diff := deep.Equal(prim.Attributes, tt.want[i]); diff != nil {
+					t.Errorf("Document.RenameAttribute() primitive %d = %v", i, diff)
+				}
+			}
+		})
+	}
+	if err := new(Document).RenameAttribute(0, "COLOR_0", ""); err == nil {
+		t.Error("Document.RenameAttribute() expected mesh error")
+	}
+}
+
+func TestDocument_MorphedAttribute(t *testing.T) {
+	data := encodeData(
+		[]float32{0, 0, 0, 1, 0, 0}, // base positions
+		[]float32{0, 1, 0, 0, 1, 0}, // dense target
+		[]uint16{1, 0},              // sparse indices, padded
+		[]float32{0, 0, 2},          // sparse values
+	)
+	doc := &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: Float, Count: 2, Type: Vec3},
+			{BufferView: Index(1), ComponentType: Float, Count: 2, Type: Vec3},
+			{ComponentType: Float, Count: 2, Type: Vec3, Sparse: &Sparse{Count: 1,
+				Indices: SparseIndices{BufferView: 2, ComponentType: UnsignedShort},
+				Values:  SparseValues{BufferView: 3},
+			}},
+			{BufferView: Index(1), ComponentType: Float, Count: 1, Type: Vec3},
+		},
+		BufferViews: []BufferView{
+			{ByteOffset: 0, ByteLength: 24},
+			{ByteOffset: 24, ByteLength: 24},
+			{ByteOffset: 48, ByteLength: 4},
+			{ByteOffset: 52, ByteLength: 12},
+		},
+		Buffers: []Buffer{{ByteLength: uint32(len(data)), Data: data}},
+		Meshes: []Mesh{{Weights: []float64{1, 0}, Primitives: []Primitive{
+			{Attributes: Attribute{"POSITION": 0}, Targets: []Attribute{{"POSITION": 1}, {"POSITION": 2}}},
+			{Attributes: Attribute{"POSITION": 0}, Targets: []Attribute{{"POSITION": 3}}},
+		}}},
+	}
+	tests := []struct {
+		name      string
+		primitive uint32
+		semantic  string
+		weights   []float64
+		want      []float64
+		wantErr   bool
+	}{
+		{"default", 0, "POSITION", nil, []float64{0, 1, 0, 1, 1, 0}, false},
+		{"weights", 0, "POSITION", []float64{0.5, 0.5}, []float64{0, 0.5, 0, 1, 0.5, 1}, false},
+		{"partial", 0, "POSITION", []float64{0}, []float64{0, 0, 0, 1, 0, 0}, false},
+		{"tooManyWeights", 0, "POSITION", []float64{1, 1, 1}, nil, true},
+		{"missingAttribute", 0, "NORMAL", nil, nil, true},
+		{"countMismatch", 1, "POSITION", []float64{1}, nil, true},
+		{"outOfRange", 2, "POSITION", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.MorphedAttribute(0, tt.primitive, tt.semantic, tt.weights)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.MorphedAttribute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Document.MorphedAttribute() = %v", diff)
+			}
+		})
+	}
+}
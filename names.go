@@ -0,0 +1,45 @@
+package gltf
+
+// NodeByName returns the index of the first node with the given name.
+// The boolean is false if there is no such node.
+func (d *Document) NodeByName(name string) (uint32, bool) {
+	for i := range d.Nodes {
+		if d.Nodes[i].Name == name {
+			return uint32(i), true
+		}
+	}
+	return 0, false
+}
+
+// MeshByName returns the index of the first mesh with the given name.
+// The boolean is false if there is no such mesh.
+func (d *Document) MeshByName(name string) (uint32, bool) {
+	for i := range d.Meshes {
+		if d.Meshes[i].Name == name {
+			return uint32(i), true
+		}
+	}
+	return 0, false
+}
+
+// MaterialByName returns the index of the first material with the given name.
+// The boolean is false if there is no such material.
+func (d *Document) MaterialByName(name string) (uint32, bool) {
+	for i := range d.Materials {
+		if d.Materials[i].Name == name {
+			return uint32(i), true
+		}
+	}
+	return 0, false
+}
+
+// AnimationByName returns the index of the first animation with the given name.
+// The boolean is false if there is no such animation.
+func (d *Document) AnimationByName(name string) (uint32, bool) {
+	for i := range d.Animations {
+		if d.Animations[i].Name == name {
+			return uint32(i), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,71 @@
+package gltf
+
+import (
+	"testing"
+)
+
+func TestDocument_ByName(t *testing.T) {
+	doc := &Document{
+		Nodes:      []Node{{Name: "a"}, {Name: "b"}, {Name: "b"}},
+		Meshes:     []Mesh{{Name: "m"}},
+		Materials:  []Material{{}, {Name: "mat"}},
+		Animations: []Animation{{Name: "walk"}, {Name: "run"}},
+	}
+	tests := []struct {
+		name   string
+		lookup func(string) (uint32, bool)
+		arg    string
+		want   uint32
+		wantOk bool
+	}{
+		{"node", doc.NodeByName, "b", 1, true},
+		{"nodeNotFound", doc.NodeByName, "c", 0, false},
+		{"mesh", doc.MeshByName, "m", 0, true},
+		{"material", doc.MaterialByName, "mat", 1, true},
+		{"materialEmpty", doc.MaterialByName, "", 0, true},
+		{"animation", doc.AnimationByName, "run", 1, true},
+		{"animationNotFound", doc.AnimationByName, "m", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.lookup(tt.arg)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ByName() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestDocument_ValidateNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"empty", new(Document), nil},
+		{"unique", &Document{Nodes: []Node{{Name: "a"}, {Name: "b"}}, Meshes: []Mesh{{Name: "a"}}}, nil},
+		{"unnamed", &Document{Materials: []Material{{}, {}}}, nil},
+		{"duplicated", &Document{
+			Nodes:     []Node{{Name: "a"}, {Name: "b"}, {Name: "a"}, {Name: "a"}},
+			Materials: []Material{{Name: "m"}, {Name: "m"}},
+		}, []*ValidationError{
+			{"/materials/1/name", ErrDuplicateName},
+			{"/nodes/2/name", ErrDuplicateName},
+			{"/nodes/3/name", ErrDuplicateName},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.ValidateNames()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.ValidateNames() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.ValidateNames() = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package gltf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ComputeNormals sets the NORMAL attribute of a triangle primitive to smooth normals that keep the edges
+// sharper than creaseAngle, in radians, as hard edges.
+// The normal of each triangle corner is the area-weighted average of the normals of the triangles that share
+// its position and whose face normal differs from the one of the corner triangle by no more than creaseAngle,
+// so a creaseAngle of 0 produces flat shading and one of math.Pi fully smooth shading.
+// Vertices that need more than one normal are split: the extra copies are appended to every attribute
+// and morph target, as done by Unweld, and the primitive is converted to indexed TRIANGLES.
+// Vertices that do not belong to any non-degenerate triangle get the normal (0, 0, 1).
+// The normals are written with AddInterleaved in a new accessor, the previous one is left untouched.
+func (d *Document) ComputeNormals(meshIndex, primitiveIndex uint32, creaseAngle float64) error {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return err
+	}
+	position, ok := prim.Attributes["POSITION"]
+	if !ok {
+		return errors.New("gltf: primitive without POSITION")
+	}
+	if int(position) >= len(d.Accessors) {
+		return fmt.Errorf("gltf: accessor index %d out of range", position)
+	}
+	if d.Accessors[position].Type != Vec3 {
+		return errors.New("gltf: POSITION accessor has an invalid type")
+	}
+	values, err := d.ReadAccessor(position)
+	if err != nil {
+		return err
+	}
+	positions := make([][3]float64, len(values)/3)
+	for i := range positions {
+		positions[i] = [3]float64{values[i*3], values[i*3+1], values[i*3+2]}
+	}
+	var corners []uint32
+	err = d.WalkTriangles(meshIndex, primitiveIndex, func(a, b, c uint32) error {
+		if int(a) >= len(positions) || int(b) >= len(positions) || int(c) >= len(positions) {
+			return errors.New("gltf: vertex index out of range")
+		}
+		corners = append(corners, a, b, c)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	faces := make([][3]float64, len(corners)/3)
+	for i := range faces {
+		p := positions[corners[i*3]]
+		faces[i] = cross(sub(positions[corners[i*3+1]], p), sub(positions[corners[i*3+2]], p))
+	}
+	// Group the corners by position, so vertices that have been split before are smoothed together.
+	shared := make(map[[3]float64][]int)
+	for i, v := range corners {
+		shared[positions[v]] = append(shared[positions[v]], i/3)
+	}
+	minCos := math.Cos(creaseAngle)
+	normals := make([][3]float64, len(positions))
+	assigned := make([]bool, len(positions))
+	var sources []uint32
+	copies := make(map[uint32][]uint32)
+	newIndex := make([]uint32, len(corners))
+	for i, v := range corners {
+		face := i / 3
+		n := unitVector(faces[face])
+		if n != [3]float64{} {
+			var sum [3]float64
+			for _, other := range shared[positions[v]] {
+				if dot(n, unitVector(faces[other])) >= minCos-1e-9 {
+					sum = [3]float64{sum[0] + faces[other][0], sum[1] + faces[other][1], sum[2] + faces[other][2]}
+				}
+			}
+			n = unitVector(sum)
+		}
+		if n == [3]float64{} {
+			n = [3]float64{0, 0, 1}
+		}
+		switch {
+		case !assigned[v]:
+			normals[v], assigned[v] = n, true
+			newIndex[i] = v
+		case dot(normals[v], n) > 1-1e-6:
+			newIndex[i] = v
+		default:
+			newIndex[i] = findCopy(copies[v], normals, n)
+			if newIndex[i] == ^uint32(0) {
+				newIndex[i] = uint32(len(normals))
+				copies[v] = append(copies[v], newIndex[i])
+				sources = append(sources, v)
+				normals = append(normals, n)
+			}
+		}
+	}
+	for v := range positions {
+		if !assigned[v] {
+			normals[v] = [3]float64{0, 0, 1}
+		}
+	}
+	if len(sources) > 0 {
+		vertices := make([]uint32, len(positions), len(positions)+len(sources))
+		for i := range vertices {
+			vertices[i] = uint32(i)
+		}
+		vertices = append(vertices, sources...)
+		if err := d.splitVertices(prim, vertices); err != nil {
+			return err
+		}
+		indices, err := d.AddIndices(newIndex)
+		if err != nil {
+			return err
+		}
+		prim.Indices = Index(indices)
+		prim.Mode = Triangles
+	}
+	data := make([]float64, 0, len(normals)*3)
+	for _, n := range normals {
+		data = append(data, n[0], n[1], n[2])
+	}
+	attrs, err := d.AddInterleaved([]InterleavedAttribute{{Semantic: "NORMAL", Type: Vec3, ComponentType: Float, Data: data}})
+	if err != nil {
+		return err
+	}
+	prim.Attributes["NORMAL"] = attrs["NORMAL"]
+	return nil
+}
+
+// findCopy returns the index of the copy, among the given copies of a vertex, that already has the normal n,
+// or ^uint32(0) if there is none.
+func findCopy(copies []uint32, normals [][3]float64, n [3]float64) uint32 {
+	for _, c := range copies {
+		if dot(normals[c], n) > 1-1e-6 {
+			return c
+		}
+	}
+	return ^uint32(0)
+}
+
+// splitVertices replaces every attribute, except NORMAL, and every morph target of the primitive
+// with a new accessor that has the elements listed in vertices.
+func (d *Document) splitVertices(prim *Primitive, vertices []uint32) error {
+	expanded := make(map[uint32]uint32)
+	split := func(attrs Attribute, skipNormal bool) (Attribute, error) {
+		out := make(Attribute, len(attrs))
+		for _, name := range sortedAttributes(attrs) {
+			index := attrs[name]
+			if skipNormal && name == "NORMAL" {
+				continue
+			}
+			if _, ok := expanded[index]; !ok {
+				newIndex, err := d.unweldAccessor(index, vertices)
+				if err != nil {
+					return nil, err
+				}
+				expanded[index] = newIndex
+			}
+			out[name] = expanded[index]
+		}
+		return out, nil
+	}
+	attributes, err := split(prim.Attributes, true)
+	if err != nil {
+		return err
+	}
+	targets := make([]Attribute, len(prim.Targets))
+	for i, target := range prim.Targets {
+		if targets[i], err = split(target, false); err != nil {
+			return err
+		}
+	}
+	prim.Attributes = attributes
+	if len(prim.Targets) > 0 {
+		prim.Targets = targets
+	}
+	return nil
+}
+
+// unitVector returns the unit vector with the direction of v, or the zero vector if v has no length.
+func unitVector(v [3]float64) [3]float64 {
+	l := math.Sqrt(dot(v, v))
+	if l == 0 {
+		return [3]float64{}
+	}
+	return [3]float64{v[0] / l, v[1] / l, v[2] / l}
+}
@@ -0,0 +1,82 @@
+package gltf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+// newFoldedDoc returns two triangles folded 90 degrees along the edge between vertices 0 and 1.
+func newFoldedDoc() *Document {
+	doc := new(Document)
+	attrs, _ := doc.AddInterleaved([]InterleavedAttribute{{Semantic: "POSITION", Type: Vec3, ComponentType: Float,
+		Data: []float64{0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 1}}})
+	indices, _ := doc.AddIndices([]uint32{0, 1, 2, 1, 0, 3})
+	doc.Meshes = []Mesh{{Primitives: []Primitive{{Attributes: attrs, Indices: Index(indices)}}}}
+	return doc
+}
+
+func TestDocument_ComputeNormals(t *testing.T) {
+	s := math.Sqrt2 / 2
+	tests := []struct {
+		name          string
+		creaseAngle   float64
+		wantIndices   []uint32
+		wantPositions []float64
+		wantNormals   []float64
+	}{
+		{"smooth", math.Pi, []uint32{0, 1, 2, 1, 0, 3},
+			[]float64{0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 1},
+			[]float64{0, s, s, 0, s, s, 0, 0, 1, 0, 1, 0}},
+		{"crease", math.Pi / 4, []uint32{0, 1, 2, 4, 5, 3},
+			[]float64{0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0},
+			[]float64{0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 1, 0, 0, 1, 0, 0, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := newFoldedDoc()
+			if err := doc.ComputeNormals(0, 0, tt.creaseAngle); err != nil {
+				t.Fatalf("Document.ComputeNormals() error = %v", err)
+			}
+			prim := doc.Meshes[0].Primitives[0]
+			indices, err := doc.ReadIndices(*prim.Indices)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := deep.Equal(indices, tt.wantIndices); diff != nil {
+				t.Errorf("Document.ComputeNormals() indices = %v", diff)
+			}
+			positions, err := doc.ReadAccessor(prim.Attributes["POSITION"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := deep.Equal(positions, tt.wantPositions); diff != nil {
+				t.Errorf("Document.ComputeNormals() positions = %v", diff)
+			}
+			normals, err := doc.ReadAccessor(prim.Attributes["NORMAL"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(normals) != len(tt.wantNormals) {
+				t.Fatalf("Document.ComputeNormals() normals = %v, want %v", normals, tt.wantNormals)
+			}
+			for i := range normals {
+				if math.Abs(normals[i]-tt.wantNormals[i]) > 1e-6 {
+					t.Fatalf("Document.ComputeNormals() normals = %v, want %v", normals, tt.wantNormals)
+				}
+			}
+		})
+	}
+
+	doc := newFoldedDoc()
+	doc.Meshes[0].Primitives[0].Mode = Points
+	if err := doc.ComputeNormals(0, 0, math.Pi); err == nil {
+		t.Error("Document.ComputeNormals() expected error for POINTS")
+	}
+	doc = newFoldedDoc()
+	delete(doc.Meshes[0].Primitives[0].Attributes, "POSITION")
+	if err := doc.ComputeNormals(0, 0, math.Pi); err == nil {
+		t.Error("Document.ComputeNormals() expected error without POSITION")
+	}
+}
@@ -0,0 +1,467 @@
+package gltf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// OptimizeOptions selects the passes run by Optimize.
+type OptimizeOptions struct {
+	Dedupe       bool // Merge duplicated images, samplers, textures, materials and accessors.
+	Interleave   bool // Interleave the vertex attributes of every primitive that supports it.
+	RemoveUnused bool // Remove the elements that are no longer referenced.
+	MergeBuffers bool // Pack all the bufferViews into a single buffer.
+}
+
+// OptimizeStats reports the total length of the buffers before and after running Optimize.
+type OptimizeStats struct {
+	BytesBefore int64
+	BytesAfter  int64
+}
+
+// BytesSaved returns the number of buffer bytes removed by the optimization.
+// It is negative if the document grew, which can happen when attributes are interleaved
+// but the previous data is not removed.
+func (s OptimizeStats) BytesSaved() int64 {
+	return s.BytesBefore - s.BytesAfter
+}
+
+// Optimize runs the passes selected in opts in the order that maximizes their effect:
+// duplicated elements are merged first, then the vertex attributes are interleaved,
+// the elements that are no longer referenced are removed and finally the buffers are packed.
+// Primitives with sparse attributes, attributes without bufferView or with different counts are not interleaved.
+func (d *Document) Optimize(opts OptimizeOptions) (OptimizeStats, error) {
+	stats := OptimizeStats{BytesBefore: d.buffersLength()}
+	if opts.Dedupe {
+		if err := d.Dedupe(); err != nil {
+			return stats, err
+		}
+	}
+	if opts.Interleave {
+		for i := range d.Meshes {
+			for j := range d.Meshes[i].Primitives {
+				if !d.canInterleave(&d.Meshes[i].Primitives[j]) {
+					continue
+				}
+				if err := d.InterleaveAttributes(uint32(i), uint32(j)); err != nil {
+					return stats, err
+				}
+			}
+		}
+	}
+	if opts.RemoveUnused {
+		d.RemoveUnused()
+	}
+	if opts.MergeBuffers {
+		if err := d.MergeBuffers(); err != nil {
+			return stats, err
+		}
+	}
+	stats.BytesAfter = d.buffersLength()
+	return stats, nil
+}
+
+func (d *Document) buffersLength() int64 {
+	var n int64
+	for _, b := range d.Buffers {
+		n += int64(b.ByteLength)
+	}
+	return n
+}
+
+func (d *Document) canInterleave(prim *Primitive) bool {
+	var stride, count uint32
+	for i, name := range sortedAttributes(prim.Attributes) {
+		index := prim.Attributes[name]
+		if int(index) >= len(d.Accessors) {
+			return false
+		}
+		acc := d.Accessors[index]
+		if acc.BufferView == nil || acc.Sparse != nil {
+			return false
+		}
+		if i == 0 {
+			count = acc.Count
+		} else if acc.Count != count {
+			return false
+		}
+		stride += padding4(elementSize(acc.ComponentType, acc.Type))
+	}
+	return stride > 0 && stride <= 252
+}
+
+// RemoveUnused removes the accessors, bufferViews, buffers, materials, textures, samplers, images,
+// meshes, skins and cameras that are not referenced, directly or indirectly, by any node or animation,
+// and updates the indices of the remaining ones. Nodes and scenes are never removed.
+// Indices stored inside extensions are not tracked, so accessors are kept when any node or primitive has extensions,
+// such as the instance attributes of EXT_mesh_gpu_instancing, bufferViews when any primitive has extensions,
+// buffers when any bufferView has extensions, such as the compressed data of EXT_meshopt_compression,
+// textures when any material has extensions and images when any texture has extensions.
+// The buffer data is not modified, use MergeBuffers to drop the bytes no longer covered by a bufferView.
+func (d *Document) RemoveUnused() {
+	pinned := make([]bool, kindCount)
+	for _, mesh := range d.Meshes {
+		for _, prim := range mesh.Primitives {
+			pinned[kindBufferView] = pinned[kindBufferView] || len(prim.Extensions) > 0
+		}
+	}
+	pinned[kindAccessor] = pinned[kindBufferView]
+	for _, node := range d.Nodes {
+		pinned[kindAccessor] = pinned[kindAccessor] || len(node.Extensions) > 0
+	}
+	for _, bv := range d.BufferViews {
+		pinned[kindBuffer] = pinned[kindBuffer] || len(bv.Extensions) > 0
+	}
+	for _, mat := range d.Materials {
+		pinned[kindTexture] = pinned[kindTexture] || len(mat.Extensions) > 0
+	}
+	for _, tex := range d.Textures {
+		pinned[kindImage] = pinned[kindImage] || len(tex.Extensions) > 0
+	}
+	// Removing an element can leave the elements it referenced unused,
+	// so repeat until nothing else can be removed.
+	for removed := true; removed; {
+		used := make([][]bool, kindCount)
+		for kind := range used {
+			used[kind] = make([]bool, d.elements(elementKind(kind)).Len())
+		}
+		d.walkReferences(func(kind elementKind, index *uint32) {
+			if int(*index) < len(used[kind]) {
+				used[kind][*index] = true
+			}
+		})
+		removed = false
+		for kind, keep := range used {
+			if pinned[kind] {
+				continue
+			}
+			for _, ok := range keep {
+				if !ok {
+					d.compact(elementKind(kind), keep)
+					removed = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// Dedupe merges the images, samplers, textures, materials and accessors that are identical,
+// redirecting all the references to the first occurrence.
+// Images are compared by URI or by the content of their bufferView,
+// and accessors by their layout and the bytes of their elements, in both cases ignoring their names.
+// Sparse accessors, accessors without bufferView and elements with extensions are never merged.
+// The duplicated elements are left unreferenced, use RemoveUnused to remove them.
+func (d *Document) Dedupe() error {
+	imageKeys := make([]string, len(d.Images))
+	for i, img := range d.Images {
+		if len(img.Extensions) > 0 {
+			continue
+		}
+		if img.URI != "" {
+			imageKeys[i] = "uri:" + img.URI
+			continue
+		}
+		if int(img.BufferView) >= len(d.BufferViews) {
+			continue
+		}
+		data, err := d.bufferViewData(img.BufferView)
+		if err != nil {
+			return err
+		}
+		imageKeys[i] = img.MimeType + ":" + string(data)
+	}
+	d.redirect(kindImage, dedupeKeys(imageKeys))
+	for _, kind := range []elementKind{kindSampler, kindTexture, kindMaterial} {
+		d.redirect(kind, d.dedupeEqual(kind))
+	}
+	accessorKeys := make([]string, len(d.Accessors))
+	for i := range d.Accessors {
+		acc := &d.Accessors[i]
+		if acc.BufferView == nil || acc.Sparse != nil || len(acc.Extensions) > 0 {
+			continue
+		}
+		view, stride, err := d.accessorView(acc)
+		if err != nil {
+			return err
+		}
+		target, _ := d.accessorTarget(*acc)
+		size := elementSize(acc.ComponentType, acc.Type)
+		r := byteReader{data: view, stride: stride}
+		data := make([]byte, 0, size*acc.Count)
+		for j := uint32(0); j < acc.Count; j++ {
+			b, err := r.slice(j, 0, size)
+			if err != nil {
+				return err
+			}
+			data = append(data, b...)
+		}
+		accessorKeys[i] = fmt.Sprintf("%d/%d/%t/%d/%v/%v:%s", acc.ComponentType, acc.Type, acc.Normalized, target, acc.Min, acc.Max, data)
+	}
+	d.redirect(kindAccessor, dedupeKeys(accessorKeys))
+	return nil
+}
+
+// RemapMaterial replaces every reference to the material from by a reference to the material to,
+// in the primitives and in the primitive extensions that implement MaterialReferrer.
+// If remove is true the material from, which is no longer used, is removed and the indices of the following ones updated.
+func (d *Document) RemapMaterial(from, to uint32, remove bool) error {
+	if int(from) >= len(d.Materials) || int(to) >= len(d.Materials) {
+		return errors.New("gltf: material index out of range")
+	}
+	if from == to {
+		return nil
+	}
+	canon := make([]uint32, len(d.Materials))
+	keep := make([]bool, len(d.Materials))
+	for i := range canon {
+		canon[i], keep[i] = uint32(i), true
+	}
+	canon[from] = to
+	d.redirect(kindMaterial, canon)
+	if remove {
+		keep[from] = false
+		d.compact(kindMaterial, keep)
+	}
+	return nil
+}
+
+// dedupeKeys maps each element to the first element with the same key.
+// Elements with an empty key are not merged.
+func dedupeKeys(keys []string) []uint32 {
+	first := make(map[string]uint32, len(keys))
+	canon := make([]uint32, len(keys))
+	for i, key := range keys {
+		canon[i] = uint32(i)
+		if key == "" {
+			continue
+		}
+		if j, ok := first[key]; ok {
+			canon[i] = j
+		} else {
+			first[key] = uint32(i)
+		}
+	}
+	return canon
+}
+
+// dedupeEqual maps each element to the first element that is deeply equal to it.
+func (d *Document) dedupeEqual(kind elementKind) []uint32 {
+	s := d.elements(kind)
+	canon := make([]uint32, s.Len())
+	for i := range canon {
+		canon[i] = uint32(i)
+		if s.Index(i).FieldByName("Extensions").Len() > 0 {
+			continue
+		}
+		for j := 0; j < i; j++ {
+			if canon[j] == uint32(j) && reflect.DeepEqual(s.Index(i).Interface(), s.Index(j).Interface()) {
+				canon[i] = uint32(j)
+				break
+			}
+		}
+	}
+	return canon
+}
+
+// redirect replaces every reference to an element of the given kind by canon[index].
+func (d *Document) redirect(kind elementKind, canon []uint32) {
+	d.walkReferences(func(k elementKind, index *uint32) {
+		if k == kind && int(*index) < len(canon) {
+			*index = canon[*index]
+		}
+	})
+}
+
+// MergeBuffers copies the data covered by every bufferView into a single buffer, aligned to 4 bytes,
+// and updates the bufferViews to point to it. The bytes not covered by any bufferView are dropped.
+// The merged buffer keeps the name and URI of the first buffer.
+// It fails if the data of a buffer used by a bufferView has not been loaded
+// or if any bufferView has extensions, as they may reference other buffers that would be dropped.
+func (d *Document) MergeBuffers() error {
+	for _, bv := range d.BufferViews {
+		if len(bv.Extensions) > 0 {
+			return errors.New("gltf: bufferViews with extensions can not be merged")
+		}
+	}
+	if len(d.BufferViews) == 0 {
+		d.Buffers = nil
+		return nil
+	}
+	var merged Buffer
+	if len(d.Buffers) > 0 {
+		first := d.Buffers[0]
+		merged = Buffer{Extensions: first.Extensions, Extras: first.Extras, Name: first.Name, URI: first.URI}
+	}
+	offsets := make([]uint32, len(d.BufferViews))
+	for i, bv := range d.BufferViews {
+		if int(bv.Buffer) < len(d.Buffers) && uint32(len(d.Buffers[bv.Buffer].Data)) != d.Buffers[bv.Buffer].ByteLength {
+			return errors.New("gltf: buffer data not loaded")
+		}
+		data, err := d.bufferViewData(uint32(i))
+		if err != nil {
+			return err
+		}
+		if uint64(len(merged.Data))+3+uint64(len(data)) > math.MaxUint32 {
+			return errors.New("gltf: buffer length overflow")
+		}
+		offsets[i] = padding4(uint32(len(merged.Data)))
+		merged.Data = append(merged.Data, make([]byte, offsets[i]-uint32(len(merged.Data)))...)
+		merged.Data = append(merged.Data, data...)
+	}
+	merged.ByteLength = uint32(len(merged.Data))
+	if merged.IsEmbeddedResource() {
+		merged.EmbeddedResource()
+	}
+	for i := range d.BufferViews {
+		d.BufferViews[i].Buffer = 0
+		d.BufferViews[i].ByteOffset = offsets[i]
+	}
+	d.Buffers = []Buffer{merged}
+	return nil
+}
+
+// elementKind identifies the top-level array an index refers to.
+type elementKind int
+
+const (
+	kindAccessor elementKind = iota
+	kindBufferView
+	kindBuffer
+	kindMaterial
+	kindTexture
+	kindImage
+	kindSampler
+	kindMesh
+	kindSkin
+	kindCamera
+	kindCount
+)
+
+// elements returns the slice that holds the elements of the given kind.
+func (d *Document) elements(kind elementKind) reflect.Value {
+	var s interface{}
+	switch kind {
+	case kindAccessor:
+		s = &d.Accessors
+	case kindBufferView:
+		s = &d.BufferViews
+	case kindBuffer:
+		s = &d.Buffers
+	case kindMaterial:
+		s = &d.Materials
+	case kindTexture:
+		s = &d.Textures
+	case kindImage:
+		s = &d.Images
+	case kindSampler:
+		s = &d.Samplers
+	case kindMesh:
+		s = &d.Meshes
+	case kindSkin:
+		s = &d.Skins
+	case kindCamera:
+		s = &d.Cameras
+	}
+	return reflect.ValueOf(s).Elem()
+}
+
+// compact removes the elements of the given kind that are not kept and updates the references to the rest.
+func (d *Document) compact(kind elementKind, keep []bool) {
+	s := d.elements(kind)
+	remap := make([]uint32, s.Len())
+	n := 0
+	for i := 0; i < s.Len(); i++ {
+		if keep[i] {
+			s.Index(n).Set(s.Index(i))
+			remap[i] = uint32(n)
+			n++
+		}
+	}
+	if n == 0 {
+		s.Set(reflect.Zero(s.Type()))
+	} else {
+		s.Set(s.Slice(0, n))
+	}
+	d.redirect(kind, remap)
+}
+
+// walkReferences calls fn with a pointer to every index that refers to an element
+// other than a node or a scene, so it can be read or updated.
+// Indices stored inside extensions are not visited, except the materials of the primitive extensions
+// that implement MaterialReferrer and the textures of the material extensions that implement TextureReferrer.
+func (d *Document) walkReferences(fn func(kind elementKind, index *uint32)) {
+	ref := func(kind elementKind, index *uint32) {
+		if index != nil {
+			fn(kind, index)
+		}
+	}
+	attributes := func(attrs Attribute) {
+		for name, index := range attrs {
+			fn(kindAccessor, &index)
+			attrs[name] = index
+		}
+	}
+	for i := range d.Nodes {
+		node := &d.Nodes[i]
+		ref(kindMesh, node.Mesh)
+		ref(kindSkin, node.Skin)
+		ref(kindCamera, node.Camera)
+	}
+	for i := range d.Meshes {
+		for j := range d.Meshes[i].Primitives {
+			prim := &d.Meshes[i].Primitives[j]
+			attributes(prim.Attributes)
+			for _, target := range prim.Targets {
+				attributes(target)
+			}
+			ref(kindAccessor, prim.Indices)
+			ref(kindMaterial, prim.Material)
+			for _, ext := range prim.Extensions {
+				if mr, ok := ext.(MaterialReferrer); ok {
+					for _, index := range mr.MaterialIndices() {
+						ref(kindMaterial, index)
+					}
+				}
+			}
+		}
+	}
+	for i := range d.Skins {
+		ref(kindAccessor, d.Skins[i].InverseBindMatrices)
+	}
+	for i := range d.Animations {
+		for j := range d.Animations[i].Samplers {
+			sampler := &d.Animations[i].Samplers[j]
+			ref(kindAccessor, sampler.Input)
+			ref(kindAccessor, sampler.Output)
+		}
+	}
+	for i := range d.Materials {
+		d.Materials[i].WalkTextures(func(index, _ *uint32) {
+			fn(kindTexture, index)
+		})
+	}
+	for i := range d.Textures {
+		ref(kindSampler, d.Textures[i].Sampler)
+		ref(kindImage, d.Textures[i].Source)
+	}
+	for i := range d.Images {
+		// Images without URI are stored in a bufferView.
+		if d.Images[i].URI == "" {
+			fn(kindBufferView, &d.Images[i].BufferView)
+		}
+	}
+	for i := range d.Accessors {
+		acc := &d.Accessors[i]
+		ref(kindBufferView, acc.BufferView)
+		if acc.Sparse != nil {
+			fn(kindBufferView, &acc.Sparse.Indices.BufferView)
+			fn(kindBufferView, &acc.Sparse.Values.BufferView)
+		}
+	}
+	for i := range d.BufferViews {
+		fn(kindBuffer, &d.BufferViews[i].Buffer)
+	}
+}
@@ -0,0 +1,245 @@
+package gltf
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func newOptimizeDoc() *Document {
+	position := encodeData([]float32{1, 2, 3})
+	return &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: Float, Count: 1, Type: Vec3},
+			{BufferView: Index(1), ComponentType: Float, Count: 1, Type: Vec3},
+			{BufferView: Index(0), ComponentType: Float, Count: 1, Type: Vec3, Name: "unused"},
+		},
+		BufferViews: []BufferView{
+			{Buffer: 0, ByteLength: 12, Target: ArrayBuffer},
+			{Buffer: 1, ByteLength: 12, Target: ArrayBuffer},
+		},
+		Buffers: []Buffer{
+			{ByteLength: 16, Data: append(append([]byte{}, position...), 9, 9, 9, 9)},
+			{ByteLength: 12, Data: position},
+		},
+		Materials: []Material{{Name: "unused"}, {Name: "used"}},
+		Meshes: []Mesh{
+			{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}, Material: Index(1)}}},
+			{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 1}}}},
+			{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 2}}}},
+		},
+		Nodes: []Node{{Mesh: Index(0)}, {Mesh: Index(1)}},
+	}
+}
+
+func TestDocument_RemoveUnused(t *testing.T) {
+	doc := newOptimizeDoc()
+	doc.RemoveUnused()
+	if got := len(doc.Accessors); got != 2 {
+		t.Errorf("Document.RemoveUnused() accessors = %d, want 2", got)
+	}
+	if diff := deep.Equal(doc.Materials, []Material{{Name: "used"}}); diff != nil {
+		t.Errorf("Document.RemoveUnused() materials = %v", diff)
+	}
+	wantMeshes := []Mesh{
+		{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}, Material: Index(0)}}},
+		{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 1}}}},
+	}
+	if diff := deep.Equal(doc.Meshes, wantMeshes); diff != nil {
+		t.Errorf("Document.RemoveUnused() meshes = %v", diff)
+	}
+	if len(doc.BufferViews) != 2 || len(doc.Buffers) != 2 {
+		t.Errorf("Document.RemoveUnused() removed used bufferViews or buffers")
+	}
+
+	doc.Nodes = nil
+	doc.RemoveUnused()
+	if doc.Accessors != nil || doc.BufferViews != nil || doc.Buffers != nil || doc.Materials != nil || doc.Meshes != nil {
+		t.Errorf("Document.RemoveUnused() kept elements of an empty scene graph")
+	}
+}
+
+func TestDocument_RemoveUnused_Extensions(t *testing.T) {
+	doc := &Document{
+		Materials: []Material{{Extensions: Extensions{"EXT_material": nil}}},
+		Textures:  []Texture{{Source: Index(0)}},
+		Images:    []Image{{URI: "a.png"}},
+	}
+	doc.RemoveUnused()
+	if len(doc.Textures) != 1 || len(doc.Images) != 1 {
+		t.Errorf("Document.RemoveUnused() removed textures that can be referenced from extensions")
+	}
+
+	doc = &Document{
+		BufferViews: []BufferView{{Buffer: 0, ByteLength: 4, Extensions: Extensions{"EXT_meshopt_compression": nil}}},
+		Buffers:     []Buffer{{ByteLength: 4}, {ByteLength: 8}},
+		Meshes:      []Mesh{{Primitives: []Primitive{{Extensions: Extensions{"EXT_mesh": nil}}}}},
+	}
+	doc.RemoveUnused()
+	if len(doc.Buffers) != 2 {
+		t.Errorf("Document.RemoveUnused() removed buffers that can be referenced from bufferView extensions")
+	}
+
+	doc = &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: Float, Count: 1, Type: Vec3},
+			{BufferView: Index(1), ComponentType: Float, Count: 1, Type: Vec4},
+		},
+		BufferViews: []BufferView{{ByteLength: 12}, {ByteOffset: 12, ByteLength: 16}},
+		Buffers:     []Buffer{{ByteLength: 28}},
+		Nodes:       []Node{{Extensions: Extensions{"EXT_mesh_gpu_instancing": nil}}},
+	}
+	doc.RemoveUnused()
+	if len(doc.Accessors) != 2 || len(doc.BufferViews) != 2 || len(doc.Buffers) != 1 {
+		t.Errorf("Document.RemoveUnused() removed accessors that can be referenced from node extensions")
+	}
+}
+
+func TestDocument_Dedupe(t *testing.T) {
+	doc := newOptimizeDoc()
+	doc.Samplers = []Sampler{{WrapS: ClampToEdge}, {WrapS: ClampToEdge}}
+	doc.Images = []Image{{URI: "a.png"}, {URI: "a.png", Name: "copy"}}
+	doc.Textures = []Texture{{Sampler: Index(0), Source: Index(0)}, {Sampler: Index(1), Source: Index(1)}}
+	doc.Materials = []Material{
+		{PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 0}}},
+		{PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 1}}},
+	}
+	doc.Meshes[1].Primitives[0].Material = Index(1)
+	if err := doc.Dedupe(); err != nil {
+		t.Fatalf("Document.Dedupe() error = %v", err)
+	}
+	if got := doc.Meshes[1].Primitives[0].Attributes["POSITION"]; got != 0 {
+		t.Errorf("Document.Dedupe() POSITION = %d, want 0", got)
+	}
+	if got := *doc.Meshes[1].Primitives[0].Material; got != 0 {
+		t.Errorf("Document.Dedupe() material = %d, want 0", got)
+	}
+	if got := doc.Meshes[2].Primitives[0].Attributes["POSITION"]; got != 0 {
+		t.Errorf("Document.Dedupe() unnamed POSITION = %d, want 0", got)
+	}
+	if *doc.Textures[1].Sampler != 0 || *doc.Textures[1].Source != 0 {
+		t.Errorf("Document.Dedupe() texture = %v, want sampler and source 0", doc.Textures[1])
+	}
+	if len(doc.Accessors) != 3 || len(doc.Materials) != 2 {
+		t.Errorf("Document.Dedupe() removed elements")
+	}
+
+	doc = newOptimizeDoc()
+	doc.Buffers[1].Data = nil
+	if err := doc.Dedupe(); err == nil {
+		t.Error("Document.Dedupe() expected error with buffer data not loaded")
+	}
+}
+
+func TestDocument_MergeBuffers(t *testing.T) {
+	doc := newOptimizeDoc()
+	doc.Buffers[0].Name = "first"
+	if err := doc.MergeBuffers(); err != nil {
+		t.Fatalf("Document.MergeBuffers() error = %v", err)
+	}
+	position := encodeData([]float32{1, 2, 3})
+	wantBuffers := []Buffer{{Name: "first", ByteLength: 24, Data: append(append([]byte{}, position...), position...)}}
+	if diff := deep.Equal(doc.Buffers, wantBuffers); diff != nil {
+		t.Errorf("Document.MergeBuffers() buffers = %v", diff)
+	}
+	wantViews := []BufferView{
+		{Buffer: 0, ByteLength: 12, Target: ArrayBuffer},
+		{Buffer: 0, ByteOffset: 12, ByteLength: 12, Target: ArrayBuffer},
+	}
+	if diff := deep.Equal(doc.BufferViews, wantViews); diff != nil {
+		t.Errorf("Document.MergeBuffers() bufferViews = %v", diff)
+	}
+
+	doc = newOptimizeDoc()
+	doc.Buffers[1].Data = nil
+	if err := doc.MergeBuffers(); err == nil {
+		t.Error("Document.MergeBuffers() expected error with buffer data not loaded")
+	}
+
+	doc = newOptimizeDoc()
+	doc.BufferViews[0].Extensions = Extensions{"EXT_meshopt_compression": nil}
+	if err := doc.MergeBuffers(); err == nil {
+		t.Error("Document.MergeBuffers() expected error with bufferView extensions")
+	}
+}
+
+func TestDocument_Optimize(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    OptimizeOptions
+		want    OptimizeStats
+		buffers int
+	}{
+		{"none", OptimizeOptions{}, OptimizeStats{28, 28}, 2},
+		{"prune", OptimizeOptions{RemoveUnused: true}, OptimizeStats{28, 28}, 2},
+		{"merge", OptimizeOptions{MergeBuffers: true}, OptimizeStats{28, 24}, 1},
+		{"dedupe", OptimizeOptions{Dedupe: true, RemoveUnused: true, MergeBuffers: true}, OptimizeStats{28, 12}, 1},
+		{"all", OptimizeOptions{Dedupe: true, Interleave: true, RemoveUnused: true, MergeBuffers: true}, OptimizeStats{28, 12}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := newOptimizeDoc()
+			got, err := doc.Optimize(tt.opts)
+			if err != nil {
+				t.Fatalf("Document.Optimize() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Document.Optimize() = %v, want %v", got, tt.want)
+			}
+			if len(doc.Buffers) != tt.buffers {
+				t.Errorf("Document.Optimize() buffers = %d, want %d", len(doc.Buffers), tt.buffers)
+			}
+			if _, err = doc.ReadAccessor(doc.Meshes[1].Primitives[0].Attributes["POSITION"]); err != nil {
+				t.Errorf("Document.Optimize() left an invalid accessor: %v", err)
+			}
+		})
+	}
+	if saved := (OptimizeStats{28, 12}).BytesSaved(); saved != 16 {
+		t.Errorf("OptimizeStats.BytesSaved() = %d, want 16", saved)
+	}
+}
+
+func TestDocument_RemapMaterial(t *testing.T) {
+	materials := func(doc *Document) []*uint32 {
+		return []*uint32{doc.Meshes[0].Primitives[0].Material, doc.Meshes[0].Primitives[1].Material,
+			doc.Meshes[1].Primitives[0].Material, doc.Meshes[1].Primitives[1].Material}
+	}
+	tests := []struct {
+		name          string
+		from, to      uint32
+		remove        bool
+		want          []*uint32
+		wantMaterials []Material
+		wantErr       bool
+	}{
+		{"keep", 0, 2, false, []*uint32{Index(2), Index(1), Index(2), nil}, []Material{{Name: "a"}, {Name: "b"}, {Name: "c"}}, false},
+		{"remove", 0, 2, true, []*uint32{Index(1), Index(0), Index(1), nil}, []Material{{Name: "b"}, {Name: "c"}}, false},
+		{"removeLast", 2, 1, true, []*uint32{Index(0), Index(1), Index(1), nil}, []Material{{Name: "a"}, {Name: "b"}}, false},
+		{"same", 1, 1, true, []*uint32{Index(0), Index(1), Index(2), nil}, []Material{{Name: "a"}, {Name: "b"}, {Name: "c"}}, false},
+		{"outOfRange", 0, 3, false, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{
+				Materials: []Material{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				Meshes: []Mesh{
+					{Primitives: []Primitive{{Material: Index(0)}, {Material: Index(1)}}},
+					{Primitives: []Primitive{{Material: Index(2)}, {}}},
+				},
+			}
+			err := doc.RemapMaterial(tt.from, tt.to, tt.remove)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.RemapMaterial() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := deep.Equal(materials(doc), tt.want); diff != nil {
+				t.Errorf("Document.RemapMaterial() references = %v", diff)
+			}
+			if diff := deep.Equal(doc.Materials, tt.wantMaterials); diff != nil {
+				t.Errorf("Document.RemapMaterial() materials = %v", diff)
+			}
+		})
+	}
+}
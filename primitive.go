@@ -0,0 +1,431 @@
+package gltf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// WalkTriangles calls fn with the vertex indices of each triangle of a TRIANGLES, TRIANGLE_STRIP or TRIANGLE_FAN primitive,
+// keeping a consistent winding order for strips.
+// Non-indexed primitives use sequential indices.
+// If fn returns an error the walk is stopped and the error is returned.
+func (d *Document) WalkTriangles(meshIndex, primitiveIndex uint32, fn func(a, b, c uint32) error) error {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return err
+	}
+	indices, err := d.primitiveIndices(prim)
+	if err != nil {
+		return err
+	}
+	n := len(indices)
+	switch prim.Mode {
+	case Triangles:
+		for i := 0; i+2 < n; i += 3 {
+			if err := fn(indices[i], indices[i+1], indices[i+2]); err != nil {
+				return err
+			}
+		}
+	case TriangleStrip:
+		for i := 0; i+2 < n; i++ {
+			a, b := indices[i], indices[i+1]
+			if i%2 == 1 {
+				a, b = b, a
+			}
+			if err := fn(a, b, indices[i+2]); err != nil {
+				return err
+			}
+		}
+	case TriangleFan:
+		for i := 1; i+1 < n; i++ {
+			if err := fn(indices[0], indices[i], indices[i+1]); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.New("gltf: primitive mode is not a triangle mode")
+	}
+	return nil
+}
+
+// WalkLines calls fn with the vertex indices of each segment of a LINES, LINE_STRIP or LINE_LOOP primitive.
+// Non-indexed primitives use sequential indices.
+// If fn returns an error the walk is stopped and the error is returned.
+func (d *Document) WalkLines(meshIndex, primitiveIndex uint32, fn func(a, b uint32) error) error {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return err
+	}
+	indices, err := d.primitiveIndices(prim)
+	if err != nil {
+		return err
+	}
+	n := len(indices)
+	switch prim.Mode {
+	case Lines:
+		for i := 0; i+1 < n; i += 2 {
+			if err := fn(indices[i], indices[i+1]); err != nil {
+				return err
+			}
+		}
+	case LineStrip, LineLoop:
+		for i := 0; i+1 < n; i++ {
+			if err := fn(indices[i], indices[i+1]); err != nil {
+				return err
+			}
+		}
+		if prim.Mode == LineLoop && n > 1 {
+			return fn(indices[n-1], indices[0])
+		}
+	default:
+		return errors.New("gltf: primitive mode is not a line mode")
+	}
+	return nil
+}
+
+// WalkPoints calls fn with the vertex index of each point of a POINTS primitive.
+// Non-indexed primitives use sequential indices.
+// If fn returns an error the walk is stopped and the error is returned.
+func (d *Document) WalkPoints(meshIndex, primitiveIndex uint32, fn func(a uint32) error) error {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return err
+	}
+	if prim.Mode != Points {
+		return errors.New("gltf: primitive mode is not points")
+	}
+	indices, err := d.primitiveIndices(prim)
+	if err != nil {
+		return err
+	}
+	for _, i := range indices {
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TriangleCount returns the number of triangles rendered by a primitive,
+// which is zero for points and lines. Only the accessors metadata is used, so the buffers do not need to be loaded.
+func (d *Document) TriangleCount(meshIndex, primitiveIndex uint32) (uint32, error) {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return 0, err
+	}
+	var n uint32
+	if prim.Indices != nil {
+		if int(*prim.Indices) >= len(d.Accessors) {
+			return 0, fmt.Errorf("gltf: accessor index %d out of range", *prim.Indices)
+		}
+		n = d.Accessors[*prim.Indices].Count
+	} else if n, err = d.positionCount(prim); err != nil {
+		return 0, err
+	}
+	switch prim.Mode {
+	case Triangles:
+		return n / 3, nil
+	case TriangleStrip, TriangleFan:
+		if n < 3 {
+			return 0, nil
+		}
+		return n - 2, nil
+	}
+	return 0, nil
+}
+
+// TotalTriangleCount returns the number of triangles of all the primitives of all the meshes,
+// as returned by TriangleCount. Primitives whose accessors do not exist are not counted.
+// If instanced is true each mesh is counted once per node that references it, so unused meshes are not counted.
+// Only the accessors metadata is used, so the buffers do not need to be loaded.
+func (d *Document) TotalTriangleCount(instanced bool) uint64 {
+	instances := make([]uint64, len(d.Meshes))
+	for i := range instances {
+		if !instanced {
+			instances[i] = 1
+		}
+	}
+	if instanced {
+		for _, node := range d.Nodes {
+			if node.Mesh != nil && int(*node.Mesh) < len(instances) {
+				instances[*node.Mesh]++
+			}
+		}
+	}
+	var total uint64
+	for i, mesh := range d.Meshes {
+		if instances[i] == 0 {
+			continue
+		}
+		for j := range mesh.Primitives {
+			if n, err := d.TriangleCount(uint32(i), uint32(j)); err == nil {
+				total += uint64(n) * instances[i]
+			}
+		}
+	}
+	return total
+}
+
+// VertexCount returns the number of vertices of a primitive, as defined by its POSITION accessor.
+// Only the accessors metadata is used, so the buffers do not need to be loaded.
+func (d *Document) VertexCount(meshIndex, primitiveIndex uint32) (uint32, error) {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return 0, err
+	}
+	return d.positionCount(prim)
+}
+
+func (d *Document) positionCount(prim *Primitive) (uint32, error) {
+	index, ok := prim.Attributes["POSITION"]
+	if !ok {
+		return 0, nil
+	}
+	if int(index) >= len(d.Accessors) {
+		return 0, fmt.Errorf("gltf: accessor index %d out of range", index)
+	}
+	return d.Accessors[index].Count, nil
+}
+
+// PrimitiveSurfaceArea returns the sum of the areas of the triangles of a primitive,
+// in the units of its POSITION attribute and without applying any node transform.
+// It fails if the primitive mode is not a triangle mode.
+func (d *Document) PrimitiveSurfaceArea(meshIndex, primitiveIndex uint32) (float64, error) {
+	var area float64
+	err := d.walkTrianglePositions(meshIndex, primitiveIndex, func(a, b, c [3]float64) {
+		n := cross(sub(b, a), sub(c, a))
+		area += math.Sqrt(dot(n, n)) / 2
+	})
+	return area, err
+}
+
+// PrimitiveVolume returns the signed volume enclosed by the triangles of a primitive,
+// computed with the divergence theorem as the sum of the signed volumes of the tetrahedra
+// formed by each triangle and the origin.
+// The volume is positive when the triangles are wound counter-clockwise seen from outside.
+// It is only meaningful for closed meshes, for open ones the result depends on the position of the origin.
+// It fails if the primitive mode is not a triangle mode.
+func (d *Document) PrimitiveVolume(meshIndex, primitiveIndex uint32) (float64, error) {
+	var volume float64
+	err := d.walkTrianglePositions(meshIndex, primitiveIndex, func(a, b, c [3]float64) {
+		volume += dot(a, cross(b, c)) / 6
+	})
+	return volume, err
+}
+
+// walkTrianglePositions calls fn with the positions of the vertices of each triangle of a primitive.
+func (d *Document) walkTrianglePositions(meshIndex, primitiveIndex uint32, fn func(a, b, c [3]float64)) error {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return err
+	}
+	index, ok := prim.Attributes["POSITION"]
+	if !ok {
+		return errors.New("gltf: primitive without POSITION")
+	}
+	if int(index) < len(d.Accessors) && d.Accessors[index].Type != Vec3 {
+		return errors.New("gltf: position accessor is not a VEC3")
+	}
+	positions, err := d.ReadAccessor(index)
+	if err != nil {
+		return err
+	}
+	count := uint32(len(positions) / 3)
+	position := func(i uint32) [3]float64 {
+		return [3]float64{positions[3*i], positions[3*i+1], positions[3*i+2]}
+	}
+	return d.WalkTriangles(meshIndex, primitiveIndex, func(a, b, c uint32) error {
+		if a >= count || b >= count || c >= count {
+			return errors.New("gltf: vertex index out of range")
+		}
+		fn(position(a), position(b), position(c))
+		return nil
+	})
+}
+
+// WireframeIndices returns the vertex indices of the edges of the triangles of a primitive, as pairs for a LINES primitive.
+// Each edge shared by adjacent triangles is only returned once, regardless of its direction,
+// and the edges are sorted by their first appearance. Degenerate edges are skipped.
+func (d *Document) WireframeIndices(meshIndex, primitiveIndex uint32) ([]uint32, error) {
+	var lines []uint32
+	seen := make(map[[2]uint32]struct{})
+	edge := func(a, b uint32) {
+		if a == b {
+			return
+		}
+		key := [2]uint32{a, b}
+		if a > b {
+			key = [2]uint32{b, a}
+		}
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			lines = append(lines, a, b)
+		}
+	}
+	err := d.WalkTriangles(meshIndex, primitiveIndex, func(a, b, c uint32) error {
+		edge(a, b)
+		edge(b, c)
+		edge(c, a)
+		return nil
+	})
+	return lines, err
+}
+
+// AddWireframe appends to the mesh a LINES primitive with the edges of a triangle primitive, as returned by WireframeIndices,
+// which shares the vertex attributes of the triangles and has no material.
+// The new indices are written with AddIndices. The return value is the index of the new primitive.
+func (d *Document) AddWireframe(meshIndex, primitiveIndex uint32) (uint32, error) {
+	lines, err := d.WireframeIndices(meshIndex, primitiveIndex)
+	if err != nil {
+		return 0, err
+	}
+	indices, err := d.AddIndices(lines)
+	if err != nil {
+		return 0, err
+	}
+	mesh := &d.Meshes[meshIndex]
+	attrs := make(Attribute, len(mesh.Primitives[primitiveIndex].Attributes))
+	for name, index := range mesh.Primitives[primitiveIndex].Attributes {
+		attrs[name] = index
+	}
+	mesh.Primitives = append(mesh.Primitives, Primitive{Attributes: attrs, Indices: Index(indices), Mode: Lines})
+	return uint32(len(mesh.Primitives) - 1), nil
+}
+
+// TexCoordBounds returns the minimum and maximum texture coordinates of the TEXCOORD_<set> attribute of a primitive.
+// The bounds are always computed from the data, so normalized integer coordinates are converted to the [0, 1] range.
+func (d *Document) TexCoordBounds(meshIndex, primitiveIndex, set uint32) (min, max [2]float64, err error) {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return min, max, err
+	}
+	index, ok := prim.Attributes[fmt.Sprintf("TEXCOORD_%d", set)]
+	if !ok {
+		return min, max, fmt.Errorf("gltf: primitive without TEXCOORD_%d", set)
+	}
+	if int(index) < len(d.Accessors) && d.Accessors[index].Type != Vec2 {
+		return min, max, errors.New("gltf: texture coordinates accessor is not a VEC2")
+	}
+	values, err := d.ReadAccessor(index)
+	if err != nil {
+		return min, max, err
+	}
+	if len(values) == 0 {
+		return min, max, errors.New("gltf: bounds of an empty accessor")
+	}
+	min = [2]float64{values[0], values[1]}
+	max = min
+	for i := 2; i < len(values); i++ {
+		c := i % 2
+		if values[i] < min[c] {
+			min[c] = values[i]
+		} else if values[i] > max[c] {
+			max[c] = values[i]
+		}
+	}
+	return min, max, nil
+}
+
+// UnusedTexCoords returns in ascending order the TEXCOORD sets of a primitive
+// that are not used by any texture of its material.
+// All the sets are unused when the primitive has no material.
+// Texture coordinates referenced from material extensions are not inspected.
+func (d *Document) UnusedTexCoords(meshIndex, primitiveIndex uint32) ([]uint32, error) {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[uint32]bool)
+	if prim.Material != nil {
+		if int(*prim.Material) >= len(d.Materials) {
+			return nil, fmt.Errorf("gltf: material index %d out of range", *prim.Material)
+		}
+		for _, ref := range materialTextures(&d.Materials[*prim.Material]) {
+			used[ref.texCoord] = true
+		}
+	}
+	var unused []uint32
+	for _, set := range AttributeSets(prim.Attributes, "TEXCOORD") {
+		if !used[set] {
+			unused = append(unused, set)
+		}
+	}
+	return unused, nil
+}
+
+// RemoveUnusedTexCoords removes the TEXCOORD attributes that are not used by the material of their primitive,
+// also from its morph targets, and then removes the accessors of those attributes that are no longer referenced.
+// Only the sets above the highest used one are removed, so the remaining sets stay contiguous
+// and the texCoord of the materials, which may be shared, do not need to be changed.
+// Primitives whose material has extensions are skipped, as the extensions may use other sets.
+// It returns the number of attributes removed.
+func (d *Document) RemoveUnusedTexCoords() int {
+	count := 0
+	removed := make(map[uint32]bool)
+	for i := range d.Meshes {
+		for j := range d.Meshes[i].Primitives {
+			prim := &d.Meshes[i].Primitives[j]
+			if prim.Material != nil && int(*prim.Material) < len(d.Materials) && len(d.Materials[*prim.Material].Extensions) > 0 {
+				continue
+			}
+			unused, err := d.UnusedTexCoords(uint32(i), uint32(j))
+			if err != nil {
+				continue
+			}
+			sets := AttributeSets(prim.Attributes, "TEXCOORD")
+			// Only a trailing run of unused sets can be dropped.
+			for k := len(unused) - 1; k >= 0 && len(sets) > 0 && unused[k] == sets[len(sets)-1]; k-- {
+				name := fmt.Sprintf("TEXCOORD_%d", unused[k])
+				removed[prim.Attributes[name]] = true
+				delete(prim.Attributes, name)
+				for _, target := range prim.Targets {
+					if index, ok := target[name]; ok {
+						removed[index] = true
+						delete(target, name)
+					}
+				}
+				sets = sets[:len(sets)-1]
+				count++
+			}
+		}
+	}
+	if len(removed) > 0 {
+		keep := make([]bool, len(d.Accessors))
+		for i := range keep {
+			keep[i] = !removed[uint32(i)]
+		}
+		d.walkReferences(func(kind elementKind, index *uint32) {
+			if kind == kindAccessor && int(*index) < len(keep) {
+				keep[*index] = true
+			}
+		})
+		d.compact(kindAccessor, keep)
+	}
+	return count
+}
+
+// primitiveIndices returns the indices of the primitive vertices.
+// When the primitive is not indexed it returns sequential indices up to the count of the POSITION accessor,
+// or of any other attribute if there is no position.
+func (d *Document) primitiveIndices(prim *Primitive) ([]uint32, error) {
+	if prim.Indices != nil {
+		return d.ReadIndices(*prim.Indices)
+	}
+	index, ok := prim.Attributes["POSITION"]
+	if !ok {
+		names := sortedAttributes(prim.Attributes)
+		if len(names) == 0 {
+			return nil, nil
+		}
+		index = prim.Attributes[names[0]]
+	}
+	if int(index) >= len(d.Accessors) {
+		return nil, fmt.Errorf("gltf: accessor index %d out of range", index)
+	}
+	indices := make([]uint32, d.Accessors[index].Count)
+	for i := range indices {
+		indices[i] = uint32(i)
+	}
+	return indices, nil
+}
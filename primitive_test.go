@@ -0,0 +1,443 @@
+package gltf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func newPrimitiveDoc(mode PrimitiveMode, indexed bool) *Document {
+	doc := &Document{
+		Accessors: []Accessor{
+			{ComponentType: Float, Count: 5, Type: Vec3},
+			{BufferView: Index(0), ComponentType: UnsignedByte, Count: 5, Type: Scalar},
+		},
+		BufferViews: []BufferView{{ByteLength: 5}},
+		Buffers:     []Buffer{{ByteLength: 5, Data: []byte{4, 3, 2, 1, 0}}},
+		Meshes:      []Mesh{{Primitives: []Primitive{{Mode: mode, Attributes: Attribute{"POSITION": 0}}}}},
+	}
+	if indexed {
+		doc.Meshes[0].Primitives[0].Indices = Index(1)
+	}
+	return doc
+}
+
+func TestDocument_WalkTriangles(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		want    [][3]uint32
+		wantErr bool
+	}{
+		{"triangles", newPrimitiveDoc(Triangles, false), [][3]uint32{{0, 1, 2}}, false},
+		{"indexed", newPrimitiveDoc(Triangles, true), [][3]uint32{{4, 3, 2}}, false},
+		{"strip", newPrimitiveDoc(TriangleStrip, false), [][3]uint32{{0, 1, 2}, {2, 1, 3}, {2, 3, 4}}, false},
+		{"fan", newPrimitiveDoc(TriangleFan, true), [][3]uint32{{4, 3, 2}, {4, 2, 1}, {4, 1, 0}}, false},
+		{"lines", newPrimitiveDoc(Lines, false), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][3]uint32
+			err := tt.doc.WalkTriangles(0, 0, func(a, b, c uint32) error {
+				got = append(got, [3]uint32{a, b, c})
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.WalkTriangles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Document.WalkTriangles() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_WalkLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		want    [][2]uint32
+		wantErr bool
+	}{
+		{"lines", newPrimitiveDoc(Lines, false), [][2]uint32{{0, 1}, {2, 3}}, false},
+		{"strip", newPrimitiveDoc(LineStrip, true), [][2]uint32{{4, 3}, {3, 2}, {2, 1}, {1, 0}}, false},
+		{"loop", newPrimitiveDoc(LineLoop, false), [][2]uint32{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0}}, false},
+		{"points", newPrimitiveDoc(Points, false), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][2]uint32
+			err := tt.doc.WalkLines(0, 0, func(a, b uint32) error {
+				got = append(got, [2]uint32{a, b})
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.WalkLines() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Document.WalkLines() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_WalkPoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		want    []uint32
+		wantErr bool
+	}{
+		{"points", newPrimitiveDoc(Points, false), []uint32{0, 1, 2, 3, 4}, false},
+		{"indexed", newPrimitiveDoc(Points, true), []uint32{4, 3, 2, 1, 0}, false},
+		{"triangles", newPrimitiveDoc(Triangles, false), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []uint32
+			err := tt.doc.WalkPoints(0, 0, func(a uint32) error {
+				got = append(got, a)
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.WalkPoints() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Document.WalkPoints() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_TriangleCount(t *testing.T) {
+	doc := &Document{
+		Accessors: []Accessor{{Count: 8}, {Count: 7}, {Count: 2}},
+		Meshes: []Mesh{{Primitives: []Primitive{
+			{Attributes: Attribute{"POSITION": 0}},
+			{Attributes: Attribute{"POSITION": 0}, Indices: Index(1)},
+			{Attributes: Attribute{"POSITION": 0}, Mode: TriangleStrip},
+			{Attributes: Attribute{"POSITION": 0}, Indices: Index(1), Mode: TriangleFan},
+			{Attributes: Attribute{"POSITION": 2}, Mode: TriangleStrip},
+			{Attributes: Attribute{"POSITION": 0}, Mode: Lines},
+			{Attributes: Attribute{"POSITION": 0}, Mode: Points},
+			{Attributes: Attribute{"NORMAL": 0}},
+			{Attributes: Attribute{"POSITION": 3}},
+			{Attributes: Attribute{"POSITION": 0}, Indices: Index(3)},
+		}}},
+	}
+	tests := []struct {
+		name            string
+		index           uint32
+		wantTris        uint32
+		wantVertices    uint32
+		wantTrisErr     bool
+		wantVerticesErr bool
+	}{
+		{"triangles", 0, 2, 8, false, false},
+		{"indexed", 1, 2, 8, false, false},
+		{"strip", 2, 6, 8, false, false},
+		{"fan", 3, 5, 8, false, false},
+		{"short", 4, 0, 2, false, false},
+		{"lines", 5, 0, 8, false, false},
+		{"points", 6, 0, 8, false, false},
+		{"noPosition", 7, 0, 0, false, false},
+		{"positionOutOfRange", 8, 0, 0, true, true},
+		{"indicesOutOfRange", 9, 0, 8, true, false},
+		{"outOfRange", 10, 0, 0, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.TriangleCount(0, tt.index)
+			if (err != nil) != tt.wantTrisErr {
+				t.Errorf("Document.TriangleCount() error = %v, wantErr %v", err, tt.wantTrisErr)
+			} else if got != tt.wantTris {
+				t.Errorf("Document.TriangleCount() = %v, want %v", got, tt.wantTris)
+			}
+			got, err = doc.VertexCount(0, tt.index)
+			if (err != nil) != tt.wantVerticesErr {
+				t.Errorf("Document.VertexCount() error = %v, wantErr %v", err, tt.wantVerticesErr)
+			} else if got != tt.wantVertices {
+				t.Errorf("Document.VertexCount() = %v, want %v", got, tt.wantVertices)
+			}
+		})
+	}
+}
+
+func TestDocument_TotalTriangleCount(t *testing.T) {
+	doc := &Document{
+		Accessors: []Accessor{{Count: 6}, {Count: 4}},
+		Meshes: []Mesh{
+			{Primitives: []Primitive{
+				{Attributes: Attribute{"POSITION": 0}},
+				{Attributes: Attribute{"POSITION": 1}, Mode: TriangleStrip},
+				{Attributes: Attribute{"POSITION": 5}},
+			}},
+			{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}, Mode: TriangleFan}}},
+			{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}}}},
+		},
+		Nodes: []Node{{Mesh: Index(0)}, {Mesh: Index(0)}, {Mesh: Index(1)}, {Mesh: Index(7)}, {}},
+	}
+	tests := []struct {
+		name      string
+		instanced bool
+		want      uint64
+	}{
+		{"meshes", false, 10},
+		{"instanced", true, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := doc.TotalTriangleCount(tt.instanced); got != tt.want {
+				t.Errorf("Document.TotalTriangleCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_TexCoordBounds(t *testing.T) {
+	doc := &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: Float, Count: 3, Type: Vec2},
+			{BufferView: Index(1), ComponentType: UnsignedByte, Normalized: true, Count: 2, Type: Vec2},
+			{BufferView: Index(1), ComponentType: UnsignedByte, Count: 1, Type: Scalar},
+		},
+		BufferViews: []BufferView{{ByteLength: 24}, {ByteOffset: 24, ByteLength: 4}},
+		Buffers:     []Buffer{{ByteLength: 28, Data: encodeData([]float32{0.5, -1, 2, 0.25, 0, 0.75}, []uint8{255, 0, 51, 102})}},
+		Meshes: []Mesh{{Primitives: []Primitive{
+			{Attributes: Attribute{"TEXCOORD_0": 0, "TEXCOORD_1": 1, "TEXCOORD_2": 2}},
+		}}},
+	}
+	tests := []struct {
+		name     string
+		set      uint32
+		min, max [2]float64
+		wantErr  bool
+	}{
+		{"float", 0, [2]float64{0, -1}, [2]float64{2, 0.75}, false},
+		{"normalized", 1, [2]float64{0.2, 0}, [2]float64{1, 0.4}, false},
+		{"notVec2", 2, [2]float64{}, [2]float64{}, true},
+		{"missing", 3, [2]float64{}, [2]float64{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			min, max, err := doc.TexCoordBounds(0, 0, tt.set)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.TexCoordBounds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if min != tt.min || max != tt.max {
+				t.Errorf("Document.TexCoordBounds() = %v, %v, want %v, %v", min, max, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestDocument_UnusedTexCoords(t *testing.T) {
+	doc := &Document{
+		Materials: []Material{
+			{EmissiveTexture: &TextureInfo{TexCoord: 1}},
+			{NormalTexture: &NormalTexture{Index: Index(0)}},
+		},
+		Meshes: []Mesh{{Primitives: []Primitive{
+			{Attributes: Attribute{"TEXCOORD_0": 0, "TEXCOORD_1": 1, "TEXCOORD_2": 2}, Material: Index(0)},
+			{Attributes: Attribute{"TEXCOORD_0": 0, "TEXCOORD_1": 1}},
+			{Attributes: Attribute{"TEXCOORD_0": 0}, Material: Index(1)},
+			{Attributes: Attribute{"TEXCOORD_0": 0}, Material: Index(2)},
+		}}},
+	}
+	tests := []struct {
+		name      string
+		primitive uint32
+		want      []uint32
+		wantErr   bool
+	}{
+		{"material", 0, []uint32{0, 2}, false},
+		{"noMaterial", 1, []uint32{0, 1}, false},
+		{"allUsed", 2, nil, false},
+		{"invalidMaterial", 3, nil, true},
+		{"invalidPrimitive", 4, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.UnusedTexCoords(0, tt.primitive)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.UnusedTexCoords() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Document.UnusedTexCoords() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_RemoveUnusedTexCoords(t *testing.T) {
+	doc := &Document{
+		Accessors: []Accessor{{Name: "uv0"}, {Name: "uv1"}, {Name: "uv2"}, {Name: "morph"}, {Name: "shared"}, {Name: "other"}},
+		Materials: []Material{
+			{EmissiveTexture: &TextureInfo{TexCoord: 1}},
+			{Extensions: Extensions{"EXT_material": nil}},
+		},
+		Meshes: []Mesh{{Primitives: []Primitive{
+			{
+				Attributes: Attribute{"TEXCOORD_0": 0, "TEXCOORD_1": 1, "TEXCOORD_2": 2, "TEXCOORD_3": 4},
+				Targets:    []Attribute{{"TEXCOORD_2": 3}},
+				Material:   Index(0),
+			},
+			{Attributes: Attribute{"TEXCOORD_0": 4}, Material: Index(1)},
+		}}},
+	}
+	if got := doc.RemoveUnusedTexCoords(); got != 2 {
+		t.Errorf("Document.RemoveUnusedTexCoords() = %d, want 2", got)
+	}
+	wantAccessors := []Accessor{{Name: "uv0"}, {Name: "uv1"}, {Name: "shared"}, {Name: "other"}}
+	if diff := deep.Equal(doc.Accessors, wantAccessors); diff != nil {
+		t.Errorf("Document.RemoveUnusedTexCoords() accessors = %v", diff)
+	}
+	wantPrims := []Primitive{
+		{Attributes: Attribute{"TEXCOORD_0": 0, "TEXCOORD_1": 1}, Targets: []Attribute{{}}, Material: Index(0)},
+		{Attributes: Attribute{"TEXCOORD_0": 2}, Material: Index(1)},
+	}
+	if diff := deep.Equal(doc.Meshes[0].Primitives, wantPrims); diff != nil {
+		t.Errorf("Document.RemoveUnusedTexCoords() primitives = %v", diff)
+	}
+}
+
+// newCubeDoc returns a document with a unit cube centered at the origin, wound counter-clockwise seen from outside.
+func newCubeDoc(mode PrimitiveMode) *Document {
+	positions := []float32{
+		-0.5, -0.5, -0.5, 0.5, -0.5, -0.5, 0.5, 0.5, -0.5, -0.5, 0.5, -0.5,
+		-0.5, -0.5, 0.5, 0.5, -0.5, 0.5, 0.5, 0.5, 0.5, -0.5, 0.5, 0.5,
+	}
+	indices := []uint8{
+		0, 2, 1, 0, 3, 2, // back
+		4, 5, 6, 4, 6, 7, // front
+		0, 1, 5, 0, 5, 4, // bottom
+		3, 7, 6, 3, 6, 2, // top
+		0, 4, 7, 0, 7, 3, // left
+		1, 2, 6, 1, 6, 5, // right
+	}
+	data := append(encodeData(positions), indices...)
+	return &Document{
+		Accessors: []Accessor{
+			{BufferView: Index(0), ComponentType: Float, Count: 8, Type: Vec3},
+			{BufferView: Index(1), ComponentType: UnsignedByte, Count: 36, Type: Scalar},
+		},
+		BufferViews: []BufferView{{ByteLength: 96}, {ByteOffset: 96, ByteLength: 36}},
+		Buffers:     []Buffer{{ByteLength: uint32(len(data)), Data: data}},
+		Meshes:      []Mesh{{Primitives: []Primitive{{Mode: mode, Attributes: Attribute{"POSITION": 0}, Indices: Index(1)}}}},
+	}
+}
+
+func TestDocument_WireframeIndices(t *testing.T) {
+	quad := &Document{
+		Accessors: []Accessor{{ComponentType: Float, Count: 4, Type: Vec3}},
+		Meshes:    []Mesh{{Primitives: []Primitive{{Mode: TriangleStrip, Attributes: Attribute{"POSITION": 0}}}}},
+	}
+	tests := []struct {
+		name    string
+		doc     *Document
+		want    []uint32
+		wantErr bool
+	}{
+		{"cube", newCubeDoc(Triangles), []uint32{0, 2, 2, 1, 1, 0, 0, 3, 3, 2, 4, 5, 5, 6, 6, 4, 6, 7, 7, 4, 1, 5, 5, 0, 4, 0, 3, 7, 6, 3, 6, 2, 7, 0, 6, 1}, false},
+		{"strip", quad, []uint32{0, 1, 1, 2, 2, 0, 1, 3, 3, 2}, false},
+		{"lines", newCubeDoc(Lines), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.doc.WireframeIndices(0, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.WireframeIndices() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(got, tt.want); !tt.wantErr && diff != nil {
+				t.Errorf("Document.WireframeIndices() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_AddWireframe(t *testing.T) {
+	doc := newCubeDoc(Triangles)
+	got, err := doc.AddWireframe(0, 0)
+	if err != nil {
+		t.Fatalf("Document.AddWireframe() error = %v", err)
+	}
+	if got != 1 {
+		t.Errorf("Document.AddWireframe() = %d, want 1", got)
+	}
+	prim := doc.Meshes[0].Primitives[1]
+	if prim.Mode != Lines || prim.Attributes["POSITION"] != 0 || prim.Material != nil {
+		t.Errorf("Document.AddWireframe() primitive = %v", prim)
+	}
+	var count int
+	err = doc.WalkLines(0, 1, func(a, b uint32) error {
+		count++
+		return nil
+	})
+	if err != nil || count != 18 {
+		t.Errorf("Document.AddWireframe() edges = %d, want 18 (%v)", count, err)
+	}
+}
+
+func TestDocument_PrimitiveSurfaceArea(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		want    float64
+		wantErr bool
+	}{
+		{"cube", newCubeDoc(Triangles), 6, false},
+		{"lines", newCubeDoc(Lines), 0, true},
+		{"noPosition", newPrimitiveDoc(Triangles, false), 0, true},
+	}
+	tests[2].doc.Meshes[0].Primitives[0].Attributes = Attribute{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.doc.PrimitiveSurfaceArea(0, 0)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.PrimitiveSurfaceArea() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Document.PrimitiveSurfaceArea() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_PrimitiveVolume(t *testing.T) {
+	inverted := newCubeDoc(Triangles)
+	for i := 0; i < 36; i += 3 {
+		data := inverted.Buffers[0].Data[96+i:]
+		data[1], data[2] = data[2], data[1]
+	}
+	outOfRange := newCubeDoc(Triangles)
+	outOfRange.Buffers[0].Data[96] = 8
+	tests := []struct {
+		name    string
+		doc     *Document
+		want    float64
+		wantErr bool
+	}{
+		{"cube", newCubeDoc(Triangles), 1, false},
+		{"inverted", inverted, -1, false},
+		{"outOfRange", outOfRange, 0, true},
+		{"points", newCubeDoc(Points), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.doc.PrimitiveVolume(0, 0)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.PrimitiveVolume() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Document.PrimitiveVolume() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package gltf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ExtMeshQuantization is the name of the KHR_mesh_quantization extension,
+// which allows vertex attributes to be stored with integer component types.
+const ExtMeshQuantization = "KHR_mesh_quantization"
+
+// QuantizeMesh stores the float vertex attributes of every primitive of a mesh with smaller normalized integer types,
+// as allowed by KHR_mesh_quantization, which is added to the used and required extensions:
+// POSITION as SHORT, NORMAL and TANGENT as BYTE, and TEXCOORD_n as UNSIGNED_SHORT when all its values are in [0, 1].
+// The quantized attributes of each primitive are interleaved in a new bufferView written with AddInterleaved,
+// other attributes are left untouched.
+//
+// The positions are mapped to [-1, 1] by subtracting the center of the bounding box of the mesh
+// and dividing by the half of its largest extent, so the scale is uniform and the normals are not distorted.
+// The inverse transform, a translation to the center and the scale, is multiplied into every node that uses the mesh,
+// which therefore can not have children, as they would inherit it, nor a skin,
+// and can not be the target of animation channels that would overwrite their transform.
+// Meshes with morph targets or with primitives whose positions are not FLOAT VEC3 are not supported.
+func (d *Document) QuantizeMesh(meshIndex uint32) error {
+	if int(meshIndex) >= len(d.Meshes) {
+		return fmt.Errorf("gltf: mesh index %d out of range", meshIndex)
+	}
+	mesh := &d.Meshes[meshIndex]
+	var nodes []int
+	for i := range d.Nodes {
+		node := &d.Nodes[i]
+		if node.Mesh == nil || *node.Mesh != meshIndex {
+			continue
+		}
+		if len(node.Children) > 0 || node.Skin != nil {
+			return fmt.Errorf("gltf: node %d that uses the mesh has children or a skin", i)
+		}
+		if d.transformAnimated(uint32(i)) {
+			return fmt.Errorf("gltf: node %d that uses the mesh has an animated transform", i)
+		}
+		nodes = append(nodes, i)
+	}
+	for _, prim := range mesh.Primitives {
+		if len(prim.Targets) > 0 {
+			return errors.New("gltf: meshes with morph targets can not be quantized")
+		}
+		if _, ok := prim.Attributes["POSITION"]; ok {
+			if _, ok := d.floatAttribute(prim.Attributes, "POSITION", Vec3); !ok {
+				return errors.New("gltf: meshes with positions that are not FLOAT VEC3 can not be quantized")
+			}
+		}
+	}
+	center, scale, err := d.positionQuantization(mesh)
+	if err != nil {
+		return err
+	}
+	for i := range mesh.Primitives {
+		if err := d.quantizePrimitive(&mesh.Primitives[i], center, scale); err != nil {
+			return err
+		}
+	}
+	dequantize := composeMatrix(center, DefaultRotation, [3]float64{scale, scale, scale})
+	for _, i := range nodes {
+		node := &d.Nodes[i]
+		m := mulMatrix(localMatrix(node), dequantize)
+		if node.MatrixOrDefault() != DefaultMatrix {
+			node.Matrix = m
+			continue
+		}
+		s := node.ScaleOrDefault()
+		node.Translation = [3]float64{m[12], m[13], m[14]}
+		node.Scale = [3]float64{s[0] * scale, s[1] * scale, s[2] * scale}
+	}
+	d.ExtensionsUsed = appendExtension(d.ExtensionsUsed, ExtMeshQuantization)
+	d.ExtensionsRequired = appendExtension(d.ExtensionsRequired, ExtMeshQuantization)
+	return nil
+}
+
+// transformAnimated reports whether an animation channel targets the translation, rotation, scale or matrix of the node,
+// either with a core path or with a KHR_animation_pointer pointer.
+func (d *Document) transformAnimated(nodeIndex uint32) bool {
+	for _, anim := range d.Animations {
+		for _, channel := range anim.Channels {
+			target := channel.Target
+			switch target.Path {
+			case Translation, Rotation, Scale:
+				if target.Node != nil && *target.Node == nodeIndex {
+					return true
+				}
+			case Pointer:
+				prefix := fmt.Sprintf("/nodes/%d/", nodeIndex)
+				switch strings.TrimPrefix(channelPointer(target), prefix) {
+				case "translation", "rotation", "scale", "matrix":
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// channelPointer returns the JSON pointer of the KHR_animation_pointer extension of a channel target,
+// which may be decoded as a registered extension or kept raw.
+func channelPointer(target ChannelTarget) string {
+	ext, ok := target.Extensions["KHR_animation_pointer"]
+	if !ok {
+		return ""
+	}
+	data, err := json.Marshal(ext)
+	if err != nil {
+		return ""
+	}
+	var pointer struct {
+		Pointer string `json:"pointer"`
+	}
+	json.Unmarshal(data, &pointer)
+	return pointer.Pointer
+}
+
+// positionQuantization returns the center of the bounding box of the float positions of the mesh
+// and the half of its largest extent, which is 1 if the box is empty.
+func (d *Document) positionQuantization(mesh *Mesh) (center [3]float64, scale float64, err error) {
+	min := [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	max := [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, prim := range mesh.Primitives {
+		acc, ok := d.floatAttribute(prim.Attributes, "POSITION", Vec3)
+		if !ok {
+			continue
+		}
+		values, err := d.readAccessor(acc)
+		if err != nil {
+			return center, 0, err
+		}
+		for i, v := range values {
+			min[i%3] = math.Min(min[i%3], v)
+			max[i%3] = math.Max(max[i%3], v)
+		}
+	}
+	scale = 1
+	if math.IsInf(min[0], 1) {
+		return center, scale, nil
+	}
+	var extent float64
+	for i := range center {
+		center[i] = (min[i] + max[i]) / 2
+		extent = math.Max(extent, (max[i]-min[i])/2)
+	}
+	if extent > 0 {
+		scale = extent
+	}
+	return center, scale, nil
+}
+
+// floatAttribute returns the accessor of the attribute if it stores floats of the given type.
+func (d *Document) floatAttribute(attrs Attribute, semantic string, accessorType AccessorType) (*Accessor, bool) {
+	index, ok := attrs[semantic]
+	if !ok || int(index) >= len(d.Accessors) {
+		return nil, false
+	}
+	acc := &d.Accessors[index]
+	return acc, acc.ComponentType == Float && acc.Type == accessorType
+}
+
+// quantizePrimitive rewrites the float attributes of the primitive that can be quantized.
+func (d *Document) quantizePrimitive(prim *Primitive, center [3]float64, scale float64) error {
+	var attrs []InterleavedAttribute
+	quantize := func(semantic string, accessorType AccessorType, componentType ComponentType, fn func([]float64) bool) error {
+		acc, ok := d.floatAttribute(prim.Attributes, semantic, accessorType)
+		if !ok {
+			return nil
+		}
+		values, err := d.readAccessor(acc)
+		if err != nil {
+			return err
+		}
+		if fn != nil && !fn(values) {
+			return nil
+		}
+		attrs = append(attrs, InterleavedAttribute{Semantic: semantic, Type: accessorType, ComponentType: componentType, Normalized: true, Data: values})
+		return nil
+	}
+	err := quantize("POSITION", Vec3, Short, func(values []float64) bool {
+		for i := range values {
+			values[i] = (values[i] - center[i%3]) / scale
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if err := quantize("NORMAL", Vec3, Byte, nil); err != nil {
+		return err
+	}
+	if err := quantize("TANGENT", Vec4, Byte, nil); err != nil {
+		return err
+	}
+	for _, set := range AttributeSets(prim.Attributes, "TEXCOORD") {
+		err := quantize(fmt.Sprintf("TEXCOORD_%d", set), Vec2, UnsignedShort, func(values []float64) bool {
+			for _, v := range values {
+				if v < 0 || v > 1 {
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+	quantized, err := d.AddInterleaved(attrs)
+	if err != nil {
+		return err
+	}
+	for semantic, index := range quantized {
+		prim.Attributes[semantic] = index
+	}
+	return nil
+}
+
+// appendExtension adds the extension name to the list if it is not already there.
+func appendExtension(names []string, name string) []string {
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
@@ -0,0 +1,98 @@
+package gltf
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDocument_QuantizeMesh(t *testing.T) {
+	doc := &Document{}
+	attrs, err := doc.AddInterleaved([]InterleavedAttribute{
+		{Semantic: "POSITION", Type: Vec3, ComponentType: Float, Data: []float64{0, 0, 0, 2, 4, 0, 1, 1, 0}},
+		{Semantic: "NORMAL", Type: Vec3, ComponentType: Float, Data: []float64{0, 0, 1, 0, 0, 1, 0, 0, 1}},
+		{Semantic: "TEXCOORD_0", Type: Vec2, ComponentType: Float, Data: []float64{0, 0, 1, 0.5, 0.5, 1}},
+		{Semantic: "TEXCOORD_1", Type: Vec2, ComponentType: Float, Data: []float64{0, 0, 2, 0, 0, 2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	texCoord1 := attrs["TEXCOORD_1"]
+	doc.Meshes = []Mesh{{Primitives: []Primitive{{Attributes: attrs}}}}
+	doc.Nodes = []Node{
+		{Mesh: Index(0), Translation: [3]float64{10, 0, 0}},
+		{Mesh: Index(0), Matrix: [16]float64{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 1}},
+	}
+	if err := doc.QuantizeMesh(0); err != nil {
+		t.Fatalf("Document.QuantizeMesh() error = %v", err)
+	}
+	prim := doc.Meshes[0].Primitives[0]
+	want := map[string]ComponentType{"POSITION": Short, "NORMAL": Byte, "TEXCOORD_0": UnsignedShort, "TEXCOORD_1": Float}
+	for semantic, componentType := range want {
+		acc := doc.Accessors[prim.Attributes[semantic]]
+		if acc.ComponentType != componentType || acc.Normalized != (componentType != Float) {
+			t.Errorf("Document.QuantizeMesh() %s = %v normalized %v, want %v", semantic, acc.ComponentType, acc.Normalized, componentType)
+		}
+	}
+	if prim.Attributes["TEXCOORD_1"] != texCoord1 {
+		t.Error("Document.QuantizeMesh() modified out of range texture coordinates")
+	}
+	if diff := deep.Equal(doc.Nodes[0].Translation, [3]float64{11, 2, 0}); diff != nil {
+		t.Errorf("Document.QuantizeMesh() translation = %v", diff)
+	}
+	if diff := deep.Equal(doc.Nodes[0].Scale, [3]float64{2, 2, 2}); diff != nil {
+		t.Errorf("Document.QuantizeMesh() scale = %v", diff)
+	}
+	if diff := deep.Equal(doc.Nodes[1].Matrix, [16]float64{4, 0, 0, 0, 0, 4, 0, 0, 0, 0, 4, 0, 2, 4, 0, 1}); diff != nil {
+		t.Errorf("Document.QuantizeMesh() matrix = %v", diff)
+	}
+	acc := doc.Accessors[prim.Attributes["POSITION"]]
+	if diff := deep.Equal([][]float64{acc.Min, acc.Max}, [][]float64{{-16384, -32767, 0}, {16384, 32767, 0}}); diff != nil {
+		t.Errorf("Document.QuantizeMesh() position bounds = %v", diff)
+	}
+	positions, err := doc.ReadAccessor(prim.Attributes["POSITION"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := []float64{0, 0, 0, 2, 4, 0, 1, 1, 0}
+	center := [3]float64{1, 2, 0}
+	for i, p := range positions {
+		if got := p*2 + center[i%3]; math.Abs(got-original[i]) > 1e-3 {
+			t.Errorf("Document.QuantizeMesh() dequantized position %d = %v, want %v", i, got, original[i])
+		}
+	}
+	if diff := deep.Equal(doc.ExtensionsUsed, []string{ExtMeshQuantization}); diff != nil {
+		t.Errorf("Document.QuantizeMesh() extensionsUsed = %v", diff)
+	}
+	if diff := deep.Equal(doc.ExtensionsRequired, []string{ExtMeshQuantization}); diff != nil {
+		t.Errorf("Document.QuantizeMesh() extensionsRequired = %v", diff)
+	}
+}
+
+func TestDocument_QuantizeMesh_unsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  *Document
+	}{
+		{"outOfRange", &Document{}},
+		{"children", &Document{Meshes: []Mesh{{}}, Nodes: []Node{{Mesh: Index(0), Children: []uint32{1}}, {}}}},
+		{"skin", &Document{Meshes: []Mesh{{}}, Nodes: []Node{{Mesh: Index(0), Skin: Index(0)}}}},
+		{"targets", &Document{Meshes: []Mesh{{Primitives: []Primitive{{Targets: []Attribute{{}}}}}}}},
+		{"animated", &Document{Meshes: []Mesh{{}}, Nodes: []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{Channels: []Channel{{Target: ChannelTarget{Node: Index(0), Path: Rotation}}}}}}},
+		{"animationPointer", &Document{Meshes: []Mesh{{}}, Nodes: []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{Channels: []Channel{{Target: ChannelTarget{Path: Pointer,
+				Extensions: Extensions{"KHR_animation_pointer": json.RawMessage(`{"pointer": "/nodes/0/matrix"}`)}}}}}}}},
+		{"mixedPositions", &Document{Accessors: []Accessor{{ComponentType: Short, Type: Vec3}},
+			Meshes: []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}}}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.doc.QuantizeMesh(0); err == nil {
+				t.Error("Document.QuantizeMesh() expected error")
+			}
+		})
+	}
+}
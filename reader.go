@@ -0,0 +1,98 @@
+package gltf
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// byteReader reads little endian values from the elements of a bufferView,
+// checking the bounds of every access so corrupt data returns an error instead of panicking.
+type byteReader struct {
+	data   []byte
+	stride uint32 // Number of bytes between the start of two consecutive elements.
+}
+
+// slice returns size bytes starting at offset inside the element at index.
+func (r byteReader) slice(index, offset, size uint32) ([]byte, error) {
+	start := uint64(index)*uint64(r.stride) + uint64(offset)
+	if start+uint64(size) > uint64(len(r.data)) {
+		return nil, errors.New("gltf: read out of bufferView bounds")
+	}
+	return r.data[start : start+uint64(size)], nil
+}
+
+func (r byteReader) readUint8(index, offset uint32) (uint8, error) {
+	b, err := r.slice(index, offset, 1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (r byteReader) readInt8(index, offset uint32) (int8, error) {
+	v, err := r.readUint8(index, offset)
+	return int8(v), err
+}
+
+func (r byteReader) readUint16(index, offset uint32) (uint16, error) {
+	b, err := r.slice(index, offset, 2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+func (r byteReader) readInt16(index, offset uint32) (int16, error) {
+	v, err := r.readUint16(index, offset)
+	return int16(v), err
+}
+
+func (r byteReader) readUint32(index, offset uint32) (uint32, error) {
+	b, err := r.slice(index, offset, 4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (r byteReader) readFloat32(index, offset uint32) (float32, error) {
+	v, err := r.readUint32(index, offset)
+	return math.Float32frombits(v), err
+}
+
+// readComponent decodes a single component of the given type.
+// Normalized integers are converted to floating-point values as defined by the glTF spec.
+func (r byteReader) readComponent(index, offset uint32, componentType ComponentType, normalized bool) (float64, error) {
+	switch componentType {
+	case Byte:
+		v, err := r.readInt8(index, offset)
+		if normalized {
+			return math.Max(float64(v)/127, -1), err
+		}
+		return float64(v), err
+	case UnsignedByte:
+		v, err := r.readUint8(index, offset)
+		if normalized {
+			return float64(v) / 255, err
+		}
+		return float64(v), err
+	case Short:
+		v, err := r.readInt16(index, offset)
+		if normalized {
+			return math.Max(float64(v)/32767, -1), err
+		}
+		return float64(v), err
+	case UnsignedShort:
+		v, err := r.readUint16(index, offset)
+		if normalized {
+			return float64(v) / 65535, err
+		}
+		return float64(v), err
+	case UnsignedInt:
+		v, err := r.readUint32(index, offset)
+		return float64(v), err
+	}
+	v, err := r.readFloat32(index, offset)
+	return float64(v), err
+}
@@ -0,0 +1,38 @@
+package gltf
+
+import "testing"
+
+func Test_byteReader_readComponent(t *testing.T) {
+	r := byteReader{data: encodeData([]byte{0xff, 0x80, 0x00, 0x80}, float32(1.5)), stride: 4}
+	tests := []struct {
+		name          string
+		index, offset uint32
+		componentType ComponentType
+		normalized    bool
+		want          float64
+		wantErr       bool
+	}{
+		{"byte", 0, 1, Byte, false, -128, false},
+		{"normalizedByte", 0, 1, Byte, true, -1, false},
+		{"unsignedByte", 0, 0, UnsignedByte, true, 1, false},
+		{"short", 0, 2, Short, false, -32768, false},
+		{"unsignedShort", 0, 0, UnsignedShort, false, 0x80ff, false},
+		{"unsignedInt", 0, 0, UnsignedInt, false, 0x800080ff, false},
+		{"float", 1, 0, Float, false, 1.5, false},
+		{"lastByte", 1, 3, UnsignedByte, false, 0x3f, false},
+		{"outOfElement", 1, 2, Float, false, 0, true},
+		{"outOfRange", 2, 0, UnsignedByte, false, 0, true},
+		{"overflow", 0xffffffff, 0xffffffff, UnsignedInt, false, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.readComponent(tt.index, tt.offset, tt.componentType, tt.normalized)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("byteReader.readComponent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("byteReader.readComponent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package gltf
+
+// ExternalResources returns the URIs of all the buffers and images that are not embedded in the document,
+// in order of appearance and without duplicates.
+// The resources are not loaded, so it can be used to resolve the dependencies ahead of time.
+func (d *Document) ExternalResources() []string {
+	var uris []string
+	seen := make(map[string]struct{})
+	add := func(uri string) {
+		if _, ok := seen[uri]; uri == "" || ok {
+			return
+		}
+		seen[uri] = struct{}{}
+		uris = append(uris, uri)
+	}
+	for i := range d.Buffers {
+		if !d.Buffers[i].IsEmbeddedResource() {
+			add(d.Buffers[i].URI)
+		}
+	}
+	for i := range d.Images {
+		if !d.Images[i].IsEmbeddedResource() {
+			add(d.Images[i].URI)
+		}
+	}
+	return uris
+}
@@ -0,0 +1,31 @@
+package gltf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocument_ExternalResources(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  *Document
+		want []string
+	}{
+		{"empty", new(Document), nil},
+		{"embedded", &Document{
+			Buffers: []Buffer{{URI: "data:application/octet-stream;base64,AAAA"}, {}},
+			Images:  []Image{{URI: "data:image/png;base64,AAAA"}, {BufferView: 1, MimeType: "image/png"}},
+		}, nil},
+		{"external", &Document{
+			Buffers: []Buffer{{URI: "a.bin"}, {URI: "data:application/octet-stream;base64,AAAA"}, {URI: "b.bin"}, {URI: "a.bin"}},
+			Images:  []Image{{URI: "a.png"}, {URI: "textures/b.jpg"}, {URI: "a.png"}, {URI: "data:image/png;base64,AAAA"}},
+		}, []string{"a.bin", "b.bin", "a.png", "textures/b.jpg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.doc.ExternalResources(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Document.ExternalResources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
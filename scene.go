@@ -0,0 +1,180 @@
+package gltf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// WalkScene traverses depth-first the node hierarchy of the scene at sceneIndex,
+// calling fn for each node with the world matrix that results of composing the transforms of all its ancestors.
+// If fn returns an error the traversal stops and the error is returned.
+func (d *Document) WalkScene(sceneIndex uint32, fn func(node *Node, worldMatrix [16]float64) error) error {
+	if int(sceneIndex) >= len(d.Scenes) {
+		return fmt.Errorf("gltf: scene index %d out of range", sceneIndex)
+	}
+	visiting := make([]bool, len(d.Nodes))
+	for _, root := range d.Scenes[sceneIndex].Nodes {
+		if err := d.walkNode(root, DefaultMatrix, visiting, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Document) walkNode(index uint32, parent [16]float64, visiting []bool, fn func(*Node, [16]float64) error) error {
+	if int(index) >= len(d.Nodes) {
+		return fmt.Errorf("gltf: node index %d out of range", index)
+	}
+	if visiting[index] {
+		return errors.New("gltf: node hierarchy contains a cycle")
+	}
+	node := &d.Nodes[index]
+	world := mulMatrix(parent, localMatrix(node))
+	if err := fn(node, world); err != nil {
+		return err
+	}
+	visiting[index] = true
+	for _, child := range node.Children {
+		if err := d.walkNode(child, world, visiting, fn); err != nil {
+			return err
+		}
+	}
+	visiting[index] = false
+	return nil
+}
+
+// NormalizeRotations scales the rotation of all the nodes to unit quaternions,
+// as exporters sometimes write quaternions that are not normalized and produce skewed rotations.
+func (d *Document) NormalizeRotations() {
+	for i := range d.Nodes {
+		d.Nodes[i].NormalizeRotation()
+	}
+}
+
+// ClampNodeScales repairs the scale of the nodes that have a component whose magnitude is less than min,
+// such as zero, which collapses their geometry, by setting it to min, and returns the number of modified nodes.
+// Negative components are made positive unless keepMirror is true, in which case only their magnitude is clamped
+// and the mirroring, which flips the winding order of the triangles, is kept.
+// The empty scale, which stands for the default one, and the node matrices are left untouched.
+func (d *Document) ClampNodeScales(min float64, keepMirror bool) int {
+	var n int
+	for i := range d.Nodes {
+		node := &d.Nodes[i]
+		if node.Scale == emptyScale {
+			continue
+		}
+		scale := node.Scale
+		for j, s := range scale {
+			if s < 0 && keepMirror {
+				scale[j] = -math.Max(-s, min)
+			} else {
+				scale[j] = math.Max(math.Abs(s), min)
+			}
+		}
+		if scale != node.Scale {
+			node.Scale = scale
+			n++
+		}
+	}
+	return n
+}
+
+// SetWorldTransform sets the local translation, rotation and scale of the node at nodeIndex
+// so its world transform, composed with the transforms of all its ancestors, is the given column-major matrix.
+// The node matrix is reset to the identity. Shear can not be represented with TRS properties and is lost.
+// It fails if the transform of the parent is not invertible or the node hierarchy contains a cycle.
+func (d *Document) SetWorldTransform(nodeIndex uint32, world [16]float64) error {
+	if int(nodeIndex) >= len(d.Nodes) {
+		return fmt.Errorf("gltf: node index %d out of range", nodeIndex)
+	}
+	parent, err := d.parentWorldMatrix(nodeIndex)
+	if err != nil {
+		return err
+	}
+	inv, ok := invertMatrix(parent)
+	if !ok {
+		return errors.New("gltf: parent transform is not invertible")
+	}
+	node := &d.Nodes[nodeIndex]
+	node.Matrix = DefaultMatrix
+	node.Translation, node.Rotation, node.Scale = decomposeMatrix(mulMatrix(inv, world))
+	return nil
+}
+
+// CameraViewMatrix returns the view matrix of the camera instantiated by the node at nodeIndex,
+// which is the inverse of the node world transform, composed with the transforms of all its ancestors,
+// as a column-major matrix that transforms world coordinates to camera coordinates.
+// It fails if the node has no camera, its world transform is not invertible or the node hierarchy contains a cycle.
+func (d *Document) CameraViewMatrix(nodeIndex uint32) ([16]float64, error) {
+	if int(nodeIndex) >= len(d.Nodes) {
+		return DefaultMatrix, fmt.Errorf("gltf: node index %d out of range", nodeIndex)
+	}
+	node := &d.Nodes[nodeIndex]
+	if node.Camera == nil {
+		return DefaultMatrix, errors.New("gltf: node does not have a camera")
+	}
+	world, err := d.WorldMatrix(nodeIndex)
+	if err != nil {
+		return DefaultMatrix, err
+	}
+	view, ok := invertMatrix(world)
+	if !ok {
+		return DefaultMatrix, errors.New("gltf: camera transform is not invertible")
+	}
+	return view, nil
+}
+
+// WorldMatrix returns the column-major world matrix of the node at nodeIndex,
+// which is its local transform composed with the transforms of all its ancestors.
+// It fails if the node hierarchy contains a cycle.
+func (d *Document) WorldMatrix(nodeIndex uint32) ([16]float64, error) {
+	if int(nodeIndex) >= len(d.Nodes) {
+		return DefaultMatrix, fmt.Errorf("gltf: node index %d out of range", nodeIndex)
+	}
+	parent, err := d.parentWorldMatrix(nodeIndex)
+	if err != nil {
+		return DefaultMatrix, err
+	}
+	return mulMatrix(parent, localMatrix(&d.Nodes[nodeIndex])), nil
+}
+
+// parentWorldMatrix returns the world matrix of the parent of a node, or the identity if the node is a root.
+func (d *Document) parentWorldMatrix(nodeIndex uint32) ([16]float64, error) {
+	parents := make(map[uint32]uint32, len(d.Nodes))
+	for i, node := range d.Nodes {
+		for _, child := range node.Children {
+			parents[child] = uint32(i)
+		}
+	}
+	world := DefaultMatrix
+	index, ok := parents[nodeIndex]
+	for steps := 0; ok; steps++ {
+		if steps == len(d.Nodes) {
+			return world, errors.New("gltf: node hierarchy contains a cycle")
+		}
+		world = mulMatrix(localMatrix(&d.Nodes[index]), world)
+		index, ok = parents[index]
+	}
+	return world, nil
+}
+
+// AddScene appends a new scene with the given root nodes and returns its index.
+// If the document has no default scene the new one becomes the default.
+func (d *Document) AddScene(name string, rootNodes []uint32) uint32 {
+	d.Scenes = append(d.Scenes, Scene{Name: name, Nodes: rootNodes})
+	index := uint32(len(d.Scenes) - 1)
+	if d.Scene == nil {
+		d.Scene = Index(index)
+	}
+	return index
+}
+
+// SetDefaultScene sets the scene at sceneIndex as the one to be rendered at load time.
+func (d *Document) SetDefaultScene(sceneIndex uint32) error {
+	if int(sceneIndex) >= len(d.Scenes) {
+		return fmt.Errorf("gltf: scene index %d out of range", sceneIndex)
+	}
+	d.Scene = Index(sceneIndex)
+	return nil
+}
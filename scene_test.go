@@ -0,0 +1,258 @@
+package gltf
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDocument_WalkScene(t *testing.T) {
+	doc := &Document{
+		Nodes: []Node{
+			{Name: "root", Children: []uint32{1, 2}, Translation: [3]float64{1, 0, 0}},
+			{Name: "child", Translation: [3]float64{0, 1, 0}, Scale: [3]float64{2, 2, 2}},
+			{Name: "matrix", Matrix: [16]float64{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 3, 1}},
+			{Name: "cycle", Children: []uint32{3}},
+			{Name: "dangling", Children: []uint32{10}},
+		},
+		Scenes: []Scene{{Nodes: []uint32{0}}, {Nodes: []uint32{3}}, {Nodes: []uint32{4}}},
+	}
+	type visit struct {
+		name  string
+		world [16]float64
+	}
+	tests := []struct {
+		name    string
+		scene   uint32
+		stopAt  string
+		want    []visit
+		wantErr bool
+	}{
+		{"base", 0, "", []visit{
+			{"root", [16]float64{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1, 0, 0, 1}},
+			{"child", [16]float64{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, 1, 1, 0, 1}},
+			{"matrix", [16]float64{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1, 0, 3, 1}},
+		}, false},
+		{"stop", 0, "child", []visit{
+			{"root", [16]float64{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1, 0, 0, 1}},
+			{"child", [16]float64{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, 1, 1, 0, 1}},
+		}, true},
+		{"cycle", 1, "", []visit{{"cycle", DefaultMatrix}}, true},
+		{"dangling", 2, "", []visit{{"dangling", DefaultMatrix}}, true},
+		{"outOfRange", 3, "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []visit
+			err := doc.WalkScene(tt.scene, func(node *Node, world [16]float64) error {
+				got = append(got, visit{node.Name, world})
+				if node.Name == tt.stopAt {
+					return errors.New("stop")
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.WalkScene() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Document.WalkScene() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_SetWorldTransform(t *testing.T) {
+	near := func(a, b []float64) bool {
+		for i := range a {
+			if math.Abs(a[i]-b[i]) > 1e-9 {
+				return false
+			}
+		}
+		return true
+	}
+	sqrt2 := math.Sqrt2 / 2
+	tests := []struct {
+		name      string
+		world     [16]float64
+		wantT     [3]float64
+		wantR     [4]float64
+		wantS     [3]float64
+		wantWorld [16]float64
+	}{
+		{"rotated", composeMatrix([3]float64{5, 5, 5}, [4]float64{0, 0, sqrt2, sqrt2}, [3]float64{2, 2, 2}),
+			[3]float64{2, 1.5, 1}, [4]float64{0, 0, sqrt2, sqrt2}, [3]float64{1, 1, 1},
+			composeMatrix([3]float64{5, 5, 5}, [4]float64{0, 0, sqrt2, sqrt2}, [3]float64{2, 2, 2})},
+		{"flipped", composeMatrix([3]float64{1, 2, 3}, [4]float64{1, 0, 0, 0}, [3]float64{-2, 4, 2}),
+			[3]float64{0, 0, 0}, [4]float64{1, 0, 0, 0}, [3]float64{-1, 2, 1},
+			composeMatrix([3]float64{1, 2, 3}, [4]float64{1, 0, 0, 0}, [3]float64{-2, 4, 2})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{
+				Nodes: []Node{
+					{Children: []uint32{1}, Translation: [3]float64{1, 2, 3}, Scale: [3]float64{2, 2, 2}},
+					{Matrix: [16]float64{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 1}},
+				},
+				Scenes: []Scene{{Nodes: []uint32{0}}},
+			}
+			if err := doc.SetWorldTransform(1, tt.world); err != nil {
+				t.Fatalf("Document.SetWorldTransform() error = %v", err)
+			}
+			node := doc.Nodes[1]
+			if node.Matrix != DefaultMatrix || !near(node.Translation[:], tt.wantT[:]) || !near(node.Rotation[:], tt.wantR[:]) || !near(node.Scale[:], tt.wantS[:]) {
+				t.Errorf("Document.SetWorldTransform() = %v, %v, %v, %v", node.Matrix, node.Translation, node.Rotation, node.Scale)
+			}
+			doc.WalkScene(0, func(n *Node, world [16]float64) error {
+				if n == &doc.Nodes[1] && !near(world[:], tt.wantWorld[:]) {
+					t.Errorf("Document.SetWorldTransform() world = %v, want %v", world, tt.wantWorld)
+				}
+				return nil
+			})
+		})
+	}
+
+	doc := &Document{Nodes: []Node{{Children: []uint32{1}, Scale: [3]float64{0, 1, 1}}, {}}}
+	if err := doc.SetWorldTransform(1, DefaultMatrix); err == nil {
+		t.Error("Document.SetWorldTransform() expected error with a singular parent")
+	}
+	doc = &Document{Nodes: []Node{{Children: []uint32{1}}, {Children: []uint32{0}}}}
+	if err := doc.SetWorldTransform(1, DefaultMatrix); err == nil {
+		t.Error("Document.SetWorldTransform() expected error with a cycle")
+	}
+	if err := doc.SetWorldTransform(2, DefaultMatrix); err == nil {
+		t.Error("Document.SetWorldTransform() expected error with an invalid node")
+	}
+}
+
+func TestDocument_AddScene(t *testing.T) {
+	doc := new(Document)
+	if got := doc.AddScene("a", []uint32{0}); got != 0 {
+		t.Errorf("Document.AddScene() = %d, want 0", got)
+	}
+	if got := doc.AddScene("b", nil); got != 1 {
+		t.Errorf("Document.AddScene() = %d, want 1", got)
+	}
+	wantScenes := []Scene{{Name: "a", Nodes: []uint32{0}}, {Name: "b"}}
+	if diff := deep.Equal(doc.Scenes, wantScenes); diff != nil {
+		t.Errorf("Document.AddScene() = %v", diff)
+	}
+	if doc.Scene == nil || *doc.Scene != 0 {
+		t.Errorf("Document.AddScene() default scene = %v, want 0", doc.Scene)
+	}
+	if err := doc.SetDefaultScene(1); err != nil || *doc.Scene != 1 {
+		t.Errorf("Document.SetDefaultScene() = %v, %v", doc.Scene, err)
+	}
+	if err := doc.SetDefaultScene(2); err == nil || *doc.Scene != 1 {
+		t.Errorf("Document.SetDefaultScene() expected error, got %v", err)
+	}
+}
+
+func TestDocument_CameraViewMatrix(t *testing.T) {
+	sqrt2 := math.Sqrt2 / 2
+	doc := &Document{
+		Cameras: []Camera{{Perspective: &Perspective{Yfov: 1, Znear: 0.1}}},
+		Nodes: []Node{
+			{Children: []uint32{1, 2, 3}, Translation: [3]float64{0, 0, 5}},
+			// Looking towards +X from (1, 0, 5).
+			{Camera: Index(0), Translation: [3]float64{1, 0, 0}, Rotation: [4]float64{0, -sqrt2, 0, sqrt2}},
+			{Translation: [3]float64{1, 0, 0}},
+			{Camera: Index(0), Scale: [3]float64{0, 1, 1}},
+		},
+	}
+	tests := []struct {
+		name    string
+		node    uint32
+		point   [3]float64
+		want    [3]float64
+		wantErr bool
+	}{
+		{"origin", 1, [3]float64{1, 0, 5}, [3]float64{0, 0, 0}, false},
+		{"ahead", 1, [3]float64{3, 0, 5}, [3]float64{0, 0, -2}, false},
+		{"up", 1, [3]float64{1, 1, 5}, [3]float64{0, 1, 0}, false},
+		{"noCamera", 2, [3]float64{}, [3]float64{}, true},
+		{"singular", 3, [3]float64{}, [3]float64{}, true},
+		{"outOfRange", 4, [3]float64{}, [3]float64{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view, err := doc.CameraViewMatrix(tt.node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.CameraViewMatrix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			p := tt.point
+			for i := 0; i < 3; i++ {
+				got := view[i]*p[0] + view[4+i]*p[1] + view[8+i]*p[2] + view[12+i]
+				if math.Abs(got-tt.want[i]) > 1e-9 {
+					t.Errorf("Document.CameraViewMatrix() transforms %v to component %d = %v, want %v", p, i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDocument_WorldMatrix(t *testing.T) {
+	doc := &Document{
+		Nodes: []Node{
+			{Children: []uint32{1}, Translation: [3]float64{1, 2, 3}},
+			{Scale: [3]float64{2, 2, 2}},
+			{Children: []uint32{3}},
+			{Children: []uint32{2}},
+		},
+	}
+	tests := []struct {
+		name    string
+		node    uint32
+		want    [16]float64
+		wantErr bool
+	}{
+		{"root", 0, [16]float64{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1, 2, 3, 1}, false},
+		{"child", 1, [16]float64{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, 1, 2, 3, 1}, false},
+		{"cycle", 2, DefaultMatrix, true},
+		{"outOfRange", 4, DefaultMatrix, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.WorldMatrix(tt.node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.WorldMatrix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Document.WorldMatrix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_ClampNodeScales(t *testing.T) {
+	tests := []struct {
+		name       string
+		doc        *Document
+		keepMirror bool
+		want       [][3]float64
+	}{
+		{"abs", &Document{Nodes: []Node{
+			{}, {Scale: [3]float64{1, 2, 3}}, {Scale: [3]float64{1, 0, 1}}, {Scale: [3]float64{-2, 1, -0.0001}},
+		}}, false, [][3]float64{{}, {1, 2, 3}, {1, 0.01, 1}, {2, 1, 0.01}}},
+		{"keepMirror", &Document{Nodes: []Node{
+			{}, {Scale: [3]float64{1, 2, 3}}, {Scale: [3]float64{1, 0, 1}}, {Scale: [3]float64{-2, 1, -0.0001}},
+		}}, true, [][3]float64{{}, {1, 2, 3}, {1, 0.01, 1}, {-2, 1, -0.01}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.doc.ClampNodeScales(0.01, tt.keepMirror); got != 2 {
+				t.Errorf("Document.ClampNodeScales() = %d, want 2", got)
+			}
+			for i, node := range tt.doc.Nodes {
+				if node.Scale != tt.want[i] {
+					t.Errorf("Document.ClampNodeScales() node %d scale = %v, want %v", i, node.Scale, tt.want[i])
+				}
+			}
+		})
+	}
+}
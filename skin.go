@@ -0,0 +1,140 @@
+package gltf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// AddSkin appends a new skin with the given joints and returns its index.
+// The inverse bind matrices, one per joint in column-major order, are written as float MAT4 elements
+// in a new bufferView of the first buffer, which is created if the document does not have any.
+// If inverseBindMatrices is empty each matrix is assumed to be the identity and no accessor is created.
+func (d *Document) AddSkin(joints []uint32, inverseBindMatrices [][16]float64, skeleton *uint32) (uint32, error) {
+	skin := Skin{Joints: joints, Skeleton: skeleton}
+	if len(inverseBindMatrices) > 0 {
+		if len(inverseBindMatrices) != len(joints) {
+			return 0, errors.New("gltf: the number of inverse bind matrices must match the number of joints")
+		}
+		data := make([]byte, 64*len(inverseBindMatrices))
+		for i, m := range inverseBindMatrices {
+			for j, v := range m {
+				binary.LittleEndian.PutUint32(data[i*64+j*4:], math.Float32bits(float32(v)))
+			}
+		}
+		if len(d.Buffers) == 0 {
+			d.Buffers = append(d.Buffers, Buffer{})
+		}
+		view, err := d.appendBufferView(0, data, 0, None)
+		if err != nil {
+			return 0, err
+		}
+		d.Accessors = append(d.Accessors, Accessor{
+			BufferView:    Index(view),
+			ComponentType: Float,
+			Count:         uint32(len(inverseBindMatrices)),
+			Type:          Mat4,
+		})
+		skin.InverseBindMatrices = Index(uint32(len(d.Accessors) - 1))
+	}
+	d.Skins = append(d.Skins, skin)
+	return uint32(len(d.Skins) - 1), nil
+}
+
+// InverseBindMatrices returns the inverse bind matrix of each joint of the skin in column-major order.
+// If the skin does not define them each matrix is the identity, as defined by the glTF spec.
+// It fails if the accessor is not a MAT4 or its count does not match the number of joints.
+func (d *Document) InverseBindMatrices(skinIndex uint32) ([][16]float64, error) {
+	if int(skinIndex) >= len(d.Skins) {
+		return nil, fmt.Errorf("gltf: skin index %d out of range", skinIndex)
+	}
+	skin := d.Skins[skinIndex]
+	matrices := make([][16]float64, len(skin.Joints))
+	if skin.InverseBindMatrices == nil {
+		for i := range matrices {
+			matrices[i] = DefaultMatrix
+		}
+		return matrices, nil
+	}
+	index := *skin.InverseBindMatrices
+	if int(index) < len(d.Accessors) && d.Accessors[index].Type != Mat4 {
+		return nil, errors.New("gltf: inverse bind matrices accessor is not a MAT4")
+	}
+	values, err := d.ReadMatrices(index)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != len(matrices) {
+		return nil, errors.New("gltf: the number of inverse bind matrices must match the number of joints")
+	}
+	for i, m := range values {
+		copy(matrices[i][:], m)
+	}
+	return matrices, nil
+}
+
+// JointInfluences summarizes the joint weights of the vertices of a skinned primitive.
+type JointInfluences struct {
+	MaxInfluences int      // The highest number of non-zero weights of a vertex.
+	Histogram     []int    // Histogram[n] is the number of vertices with n non-zero weights.
+	Unnormalized  []uint32 // The vertices whose weights do not sum to 1.
+}
+
+// JointInfluenceStats reads the WEIGHTS_n attributes of a primitive, together with their JOINTS_n counterparts,
+// and returns how many joints influence each vertex and which vertices have weights that do not sum to 1.
+// It fails if the primitive is not skinned or the sets of both semantics do not match.
+func (d *Document) JointInfluenceStats(meshIndex, primitiveIndex uint32) (*JointInfluences, error) {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return nil, err
+	}
+	sets := AttributeSets(prim.Attributes, "WEIGHTS")
+	if len(sets) == 0 {
+		return nil, errors.New("gltf: primitive without WEIGHTS_0")
+	}
+	var sums []float64
+	var influences []int
+	for _, set := range sets {
+		joints, ok := prim.Attributes[fmt.Sprintf("JOINTS_%d", set)]
+		if !ok {
+			return nil, fmt.Errorf("gltf: primitive without JOINTS_%d", set)
+		}
+		index := prim.Attributes[fmt.Sprintf("WEIGHTS_%d", set)]
+		if int(index) >= len(d.Accessors) || int(joints) >= len(d.Accessors) {
+			return nil, errors.New("gltf: accessor index out of range")
+		}
+		acc := d.Accessors[index]
+		if acc.Type != Vec4 || d.Accessors[joints].Type != Vec4 || d.Accessors[joints].Count != acc.Count {
+			return nil, fmt.Errorf("gltf: JOINTS_%d and WEIGHTS_%d must be VEC4 accessors with the same count", set, set)
+		}
+		if sums == nil {
+			sums = make([]float64, acc.Count)
+			influences = make([]int, acc.Count)
+		} else if len(sums) != int(acc.Count) {
+			return nil, errors.New("gltf: all the attributes of a primitive must have the same count")
+		}
+		weights, err := d.ReadAccessor(index)
+		if err != nil {
+			return nil, err
+		}
+		for i, w := range weights {
+			sums[i/4] += w
+			if w != 0 {
+				influences[i/4]++
+			}
+		}
+	}
+	stats := &JointInfluences{Histogram: make([]int, 4*len(sets)+1)}
+	for i, n := range influences {
+		stats.Histogram[n]++
+		if n > stats.MaxInfluences {
+			stats.MaxInfluences = n
+		}
+		if math.Abs(sums[i]-1) > unitTolerance {
+			stats.Unnormalized = append(stats.Unnormalized, uint32(i))
+		}
+	}
+	stats.Histogram = stats.Histogram[:stats.MaxInfluences+1]
+	return stats, nil
+}
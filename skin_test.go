@@ -0,0 +1,126 @@
+package gltf
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDocument_AddSkin(t *testing.T) {
+	ibm := [][16]float64{DefaultMatrix, {1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, -1, -2.5, 3, 1}}
+	doc := &Document{Buffers: []Buffer{{ByteLength: 2, Data: []byte{1, 2}}}}
+	got, err := doc.AddSkin([]uint32{1, 2}, ibm, Index(0))
+	if err != nil {
+		t.Fatalf("Document.AddSkin() error = %v", err)
+	}
+	if got != 0 {
+		t.Errorf("Document.AddSkin() = %v, want 0", got)
+	}
+	wantSkin := Skin{Joints: []uint32{1, 2}, Skeleton: Index(0), InverseBindMatrices: Index(0)}
+	if diff := deep.Equal(doc.Skins[0], wantSkin); diff != nil {
+		t.Errorf("Document.AddSkin() = %v", diff)
+	}
+	if diff := deep.Equal(doc.BufferViews[0], BufferView{ByteOffset: 4, ByteLength: 128}); diff != nil {
+		t.Errorf("Document.AddSkin() = %v", diff)
+	}
+	matrices, err := doc.ReadMatrices(*doc.Skins[0].InverseBindMatrices)
+	if err != nil {
+		t.Fatalf("Document.ReadMatrices() error = %v", err)
+	}
+	for i := range ibm {
+		if diff := deep.Equal(matrices[i], ibm[i][:]); diff != nil {
+			t.Errorf("Document.AddSkin() matrix %d = %v", i, diff)
+		}
+	}
+
+	doc = new(Document)
+	if _, err = doc.AddSkin([]uint32{0}, ibm[:1], nil); err != nil || len(doc.Buffers) != 1 || doc.Buffers[0].ByteLength != 64 {
+		t.Errorf("Document.AddSkin() new buffer = %v, %v", doc.Buffers, err)
+	}
+	if got, err = doc.AddSkin([]uint32{0, 1}, nil, nil); err != nil || got != 1 || doc.Skins[1].InverseBindMatrices != nil {
+		t.Errorf("Document.AddSkin() identity = %v, %v", got, err)
+	}
+	if _, err = doc.AddSkin([]uint32{0, 1}, ibm[:1], nil); err == nil {
+		t.Error("Document.AddSkin() expected error")
+	}
+}
+
+func TestDocument_InverseBindMatrices(t *testing.T) {
+	ibm := [][16]float64{DefaultMatrix, {1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, -1, -2.5, 3, 1}}
+	doc := new(Document)
+	doc.AddSkin([]uint32{1, 2}, ibm, nil)
+	doc.AddSkin([]uint32{1, 2}, nil, nil)
+	doc.AddSkin([]uint32{1}, ibm[:1], nil)
+	doc.Skins[2].InverseBindMatrices = Index(0)
+	doc.Accessors = append(doc.Accessors, Accessor{BufferView: Index(0), ComponentType: Float, Count: 8, Type: Vec4})
+	doc.Skins = append(doc.Skins, Skin{Joints: []uint32{1, 2}, InverseBindMatrices: Index(2)})
+	tests := []struct {
+		name    string
+		skin    uint32
+		want    [][16]float64
+		wantErr bool
+	}{
+		{"accessor", 0, ibm, false},
+		{"identity", 1, [][16]float64{DefaultMatrix, DefaultMatrix}, false},
+		{"count mismatch", 2, nil, true},
+		{"not mat4", 3, nil, true},
+		{"out of range", 4, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.InverseBindMatrices(tt.skin)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Document.InverseBindMatrices() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Document.InverseBindMatrices() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_JointInfluenceStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		want    *JointInfluences
+		wantErr bool
+	}{
+		{"single", &Document{
+			Accessors:   []Accessor{{BufferView: Index(0), ComponentType: Float, Count: 3, Type: Vec4}, {ComponentType: UnsignedByte, Count: 3, Type: Vec4}},
+			BufferViews: []BufferView{{ByteLength: 48}},
+			Buffers:     []Buffer{{ByteLength: 48, Data: encodeData([]float32{1, 0, 0, 0, 0.5, 0.5, 0, 0, 0.5, 0.25, 0.25, 0.1})}},
+			Meshes:      []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"JOINTS_0": 1, "WEIGHTS_0": 0}}}}},
+		}, &JointInfluences{MaxInfluences: 4, Histogram: []int{0, 1, 1, 0, 1}, Unnormalized: []uint32{2}}, false},
+		{"twoSets", &Document{
+			Accessors: []Accessor{
+				{BufferView: Index(0), ComponentType: Float, Count: 3, Type: Vec4},
+				{BufferView: Index(1), ComponentType: Float, Count: 3, Type: Vec4},
+				{ComponentType: UnsignedByte, Count: 3, Type: Vec4},
+			},
+			BufferViews: []BufferView{{ByteLength: 48}, {ByteOffset: 48, ByteLength: 48}},
+			Buffers: []Buffer{{ByteLength: 96, Data: encodeData(
+				[]float32{0.5, 0, 0, 0, 0.25, 0.25, 0.25, 0.25, 0, 0, 0, 0},
+				[]float32{0.5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			)}},
+			Meshes: []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"JOINTS_0": 2, "WEIGHTS_0": 0, "JOINTS_1": 2, "WEIGHTS_1": 1}}}}},
+		}, &JointInfluences{MaxInfluences: 4, Histogram: []int{1, 0, 1, 0, 1}, Unnormalized: []uint32{2}}, false},
+		{"noWeights", &Document{Meshes: []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}}}}}}, nil, true},
+		{"noJoints", &Document{
+			Accessors: []Accessor{{ComponentType: Float, Count: 3, Type: Vec4}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"WEIGHTS_0": 0}}}}},
+		}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.doc.JointInfluenceStats(0, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.JointInfluenceStats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("Document.JointInfluenceStats() = %v", diff)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package specular
 import (
 	"bytes"
 	"encoding/json"
+	"math"
 
 	"github.com/qmuntal/gltf"
 )
@@ -30,6 +31,15 @@ type PBRSpecularGlossiness struct {
 	SpecularGlossinessTexture *gltf.TextureInfo `json:"specularGlossinessTexture,omitempty"`
 }
 
+// WalkTextures calls fn with the index and texture coordinates set of the diffuse and specular-glossiness textures.
+func (p *PBRSpecularGlossiness) WalkTextures(fn func(index, texCoord *uint32)) {
+	for _, ti := range []*gltf.TextureInfo{p.DiffuseTexture, p.SpecularGlossinessTexture} {
+		if ti != nil {
+			fn(&ti.Index, &ti.TexCoord)
+		}
+	}
+}
+
 // UnmarshalJSON unmarshal the pbr with the correct default values.
 func (p *PBRSpecularGlossiness) UnmarshalJSON(data []byte) error {
 	type alias PBRSpecularGlossiness
@@ -69,3 +79,64 @@ func sanitizeJSON(b []byte) []byte {
 	b = bytes.Replace(b, []byte(`{,`), []byte("{"), 1)
 	return bytes.Replace(b, []byte(`,}`), []byte("}"), 1)
 }
+
+// dielectricSpecular is the specular reflectance of dielectric materials assumed by the metallic-roughness model.
+const dielectricSpecular = 0.04
+
+// ToMetallicRoughness returns an approximation of the material in the core metallic-roughness model,
+// for renderers that do not support the extension.
+// The factors are converted with the method used by the Khronos sample viewers, while the diffuse texture
+// is used as base color texture without modification and the specular-glossiness texture is dropped,
+// as converting them requires processing the images.
+func (p *PBRSpecularGlossiness) ToMetallicRoughness() *gltf.PBRMetallicRoughness {
+	diffuse, specular, glossiness := *gltf.NewRGBA(), *gltf.NewRGB(), 1.0
+	if p.DiffuseFactor != nil {
+		diffuse = *p.DiffuseFactor
+	}
+	if p.SpecularFactor != nil {
+		specular = *p.SpecularFactor
+	}
+	if p.GlossinessFactor != nil {
+		glossiness = *p.GlossinessFactor
+	}
+	oneMinusSpecularStrength := 1 - math.Max(specular.R, math.Max(specular.G, specular.B))
+	metallic := solveMetallic(perceivedBrightness(diffuse.R, diffuse.G, diffuse.B), perceivedBrightness(specular.R, specular.G, specular.B), oneMinusSpecularStrength)
+	const epsilon = 1e-6
+	t := metallic * metallic
+	base := func(d, s float64) float64 {
+		fromDiffuse := d * oneMinusSpecularStrength / (1 - dielectricSpecular) / math.Max(1-metallic, epsilon)
+		fromSpecular := (s - dielectricSpecular*(1-metallic)) / math.Max(metallic, epsilon)
+		return clamp(fromDiffuse + (fromSpecular-fromDiffuse)*t)
+	}
+	pbr := &gltf.PBRMetallicRoughness{
+		BaseColorFactor: &gltf.RGBA{R: base(diffuse.R, specular.R), G: base(diffuse.G, specular.G), B: base(diffuse.B, specular.B), A: diffuse.A},
+		MetallicFactor:  gltf.Float64(metallic),
+		RoughnessFactor: gltf.Float64(1 - glossiness),
+	}
+	if p.DiffuseTexture != nil {
+		tex := *p.DiffuseTexture
+		pbr.BaseColorTexture = &tex
+	}
+	return pbr
+}
+
+// perceivedBrightness returns the luminance of a linear color.
+func perceivedBrightness(r, g, b float64) float64 {
+	return math.Sqrt(0.299*r*r + 0.587*g*g + 0.114*b*b)
+}
+
+// solveMetallic returns the metallic value that produces the given diffuse and specular brightness.
+func solveMetallic(diffuse, specular, oneMinusSpecularStrength float64) float64 {
+	if specular < dielectricSpecular {
+		return 0
+	}
+	a := dielectricSpecular
+	b := diffuse*oneMinusSpecularStrength/(1-dielectricSpecular) + specular - 2*dielectricSpecular
+	c := dielectricSpecular - specular
+	d := b*b - 4*a*c
+	return clamp((-b + math.Sqrt(d)) / (2 * a))
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -2,6 +2,7 @@ package specular
 
 import (
 	"encoding/json"
+	"math"
 	"reflect"
 	"testing"
 
@@ -77,3 +78,60 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestPBRSpecularGlossiness_WalkTextures(t *testing.T) {
+	mat := &gltf.Material{
+		EmissiveTexture: &gltf.TextureInfo{Index: 0},
+		Extensions: gltf.Extensions{ExtPBRSpecularGlossiness: &PBRSpecularGlossiness{
+			DiffuseTexture:            &gltf.TextureInfo{Index: 1, TexCoord: 1},
+			SpecularGlossinessTexture: &gltf.TextureInfo{Index: 2},
+		}},
+	}
+	var got [][2]uint32
+	mat.WalkTextures(func(index, texCoord *uint32) {
+		got = append(got, [2]uint32{*index, *texCoord})
+	})
+	want := [][2]uint32{{0, 0}, {1, 1}, {2, 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Material.WalkTextures() = %v, want %v", got, want)
+	}
+}
+
+func TestPBRSpecularGlossiness_ToMetallicRoughness(t *testing.T) {
+	tests := []struct {
+		name string
+		p    *PBRSpecularGlossiness
+		want *gltf.PBRMetallicRoughness
+	}{
+		{"default", &PBRSpecularGlossiness{DiffuseTexture: &gltf.TextureInfo{Index: 1}}, &gltf.PBRMetallicRoughness{
+			BaseColorFactor: gltf.NewRGBA(), MetallicFactor: gltf.Float64(1), RoughnessFactor: gltf.Float64(0), BaseColorTexture: &gltf.TextureInfo{Index: 1},
+		}},
+		{"dielectric", &PBRSpecularGlossiness{
+			DiffuseFactor: &gltf.RGBA{R: 0.5, G: 0.25, B: 0.5, A: 0.8}, SpecularFactor: &gltf.RGB{R: 0.04, G: 0.04, B: 0.04}, GlossinessFactor: gltf.Float64(0.25),
+		}, &gltf.PBRMetallicRoughness{
+			BaseColorFactor: &gltf.RGBA{R: 0.5, G: 0.25, B: 0.5, A: 0.8}, MetallicFactor: gltf.Float64(0), RoughnessFactor: gltf.Float64(0.75),
+		}},
+		{"metal", &PBRSpecularGlossiness{
+			DiffuseFactor: &gltf.RGBA{A: 1}, SpecularFactor: &gltf.RGB{R: 1, G: 1, B: 1}, GlossinessFactor: gltf.Float64(0.5),
+		}, &gltf.PBRMetallicRoughness{
+			BaseColorFactor: gltf.NewRGBA(), MetallicFactor: gltf.Float64(1), RoughnessFactor: gltf.Float64(0.5),
+		}},
+		{"nonMetal", &PBRSpecularGlossiness{
+			DiffuseFactor: &gltf.RGBA{R: 1, A: 1}, SpecularFactor: &gltf.RGB{R: 0.01, G: 0.01, B: 0.01},
+		}, &gltf.PBRMetallicRoughness{
+			BaseColorFactor: &gltf.RGBA{R: 1, A: 1}, MetallicFactor: gltf.Float64(0), RoughnessFactor: gltf.Float64(0),
+		}},
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-6 }
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.p.ToMetallicRoughness()
+			c, w := got.BaseColorFactor, tt.want.BaseColorFactor
+			if !near(c.R, w.R) || !near(c.G, w.G) || !near(c.B, w.B) || !near(c.A, w.A) ||
+				!near(*got.MetallicFactor, *tt.want.MetallicFactor) || !near(*got.RoughnessFactor, *tt.want.RoughnessFactor) ||
+				!reflect.DeepEqual(got.BaseColorTexture, tt.want.BaseColorTexture) {
+				t.Errorf("PBRSpecularGlossiness.ToMetallicRoughness() = %v %v %v, want %v %v %v", *c, *got.MetallicFactor, *got.RoughnessFactor, *w, *tt.want.MetallicFactor, *tt.want.RoughnessFactor)
+			}
+		})
+	}
+}
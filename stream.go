@@ -0,0 +1,138 @@
+package gltf
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// StreamCallbacks defines the functions called by DecodeStream for each element of the top-level arrays.
+// The elements of an array with a callback are not stored in the document.
+// If a callback returns an error the decoding stops and the error is returned.
+type StreamCallbacks struct {
+	OnAccessor   func(index uint32, accessor *Accessor) error
+	OnAnimation  func(index uint32, animation *Animation) error
+	OnBufferView func(index uint32, bufferView *BufferView) error
+	OnCamera     func(index uint32, camera *Camera) error
+	OnImage      func(index uint32, image *Image) error
+	OnMaterial   func(index uint32, material *Material) error
+	OnMesh       func(index uint32, mesh *Mesh) error
+	OnNode       func(index uint32, node *Node) error
+	OnSampler    func(index uint32, sampler *Sampler) error
+	OnScene      func(index uint32, scene *Scene) error
+	OnSkin       func(index uint32, skin *Skin) error
+	OnTexture    func(index uint32, texture *Texture) error
+}
+
+// streamHandler decodes the next element of a top-level array and passes it to its callback.
+type streamHandler = func(jd *json.Decoder, index uint32) error
+
+// handlers returns a streamHandler for each non-nil callback, which decodes the element as d does
+// and then passes it to the callback. With strict colors, materials out of range are reported before the callback.
+func (cb *StreamCallbacks) handlers(d *Decoder) map[string]streamHandler {
+	table := []struct {
+		property string
+		set      bool
+		elem     func() interface{}
+		call     func(uint32, interface{}) error
+	}{
+		{"accessors", cb.OnAccessor != nil, func() interface{} { return new(Accessor) }, func(i uint32, v interface{}) error { return cb.OnAccessor(i, v.(*Accessor)) }},
+		{"animations", cb.OnAnimation != nil, func() interface{} { return new(Animation) }, func(i uint32, v interface{}) error { return cb.OnAnimation(i, v.(*Animation)) }},
+		{"bufferViews", cb.OnBufferView != nil, func() interface{} { return new(BufferView) }, func(i uint32, v interface{}) error { return cb.OnBufferView(i, v.(*BufferView)) }},
+		{"cameras", cb.OnCamera != nil, func() interface{} { return new(Camera) }, func(i uint32, v interface{}) error { return cb.OnCamera(i, v.(*Camera)) }},
+		{"images", cb.OnImage != nil, func() interface{} { return new(Image) }, func(i uint32, v interface{}) error { return cb.OnImage(i, v.(*Image)) }},
+		{"materials", cb.OnMaterial != nil, func() interface{} { return new(Material) }, func(i uint32, v interface{}) error { return cb.OnMaterial(i, v.(*Material)) }},
+		{"meshes", cb.OnMesh != nil, func() interface{} { return new(Mesh) }, func(i uint32, v interface{}) error { return cb.OnMesh(i, v.(*Mesh)) }},
+		{"nodes", cb.OnNode != nil, func() interface{} { return new(Node) }, func(i uint32, v interface{}) error { return cb.OnNode(i, v.(*Node)) }},
+		{"samplers", cb.OnSampler != nil, func() interface{} { return new(Sampler) }, func(i uint32, v interface{}) error { return cb.OnSampler(i, v.(*Sampler)) }},
+		{"scenes", cb.OnScene != nil, func() interface{} { return new(Scene) }, func(i uint32, v interface{}) error { return cb.OnScene(i, v.(*Scene)) }},
+		{"skins", cb.OnSkin != nil, func() interface{} { return new(Skin) }, func(i uint32, v interface{}) error { return cb.OnSkin(i, v.(*Skin)) }},
+		{"textures", cb.OnTexture != nil, func() interface{} { return new(Texture) }, func(i uint32, v interface{}) error { return cb.OnTexture(i, v.(*Texture)) }},
+	}
+	h := make(map[string]streamHandler)
+	for _, e := range table {
+		if !e.set {
+			continue
+		}
+		e := e
+		h[e.property] = func(jd *json.Decoder, i uint32) error {
+			v := e.elem()
+			if err := d.decodeValue(jd, v); err != nil {
+				return err
+			}
+			if mat, ok := v.(*Material); ok && d.strictColors {
+				var errs ValidationErrors
+				validateMaterialColors(mat, int(i), &errs)
+				if len(errs) > 0 {
+					return errs
+				}
+			}
+			return e.call(i, v)
+		}
+	}
+	return h
+}
+
+// DecodeStream reads the next JSON-encoded value from its input as Decode does,
+// but the elements of the top-level arrays that have a callback are passed to it as soon as they are parsed
+// instead of being stored in doc, so the memory used by huge documents stays bounded.
+// The rest of properties are stored in doc and the buffers are loaded when the decoding finishes.
+// The quotas on the number of elements also apply to the streamed arrays,
+// and the extras and strict colors are handled as configured by SetRawExtras, SetUseNumber and SetStrictColors.
+func (d *Decoder) DecodeStream(doc *Document, cb *StreamCallbacks) error {
+	jd, lr, err := d.jsonDecoder()
+	if err != nil {
+		return err
+	}
+	if err = d.decodeProperties(jd, doc, cb.handlers(d)); err != nil {
+		return err
+	}
+	if err = d.checkColors(doc); err != nil {
+		return err
+	}
+	if err = skipJSONChunk(lr); err != nil {
+		return err
+	}
+	return d.decodeBuffers(doc, lr != nil)
+}
+
+// DecodeProperty reads the next JSON-encoded document from its input, as glTF or GLB,
+// and only stores the top-level property with the given name, such as "materials", in the value pointed to by v.
+// The rest of properties are skipped without being decoded, and the buffers are not loaded,
+// so it is much lighter than Decode for tools that only need a small part of the document.
+// v is left untouched if the document does not have the property.
+// The input is not consumed beyond the JSON chunk.
+func (d *Decoder) DecodeProperty(name string, v interface{}) error {
+	jd, _, err := d.jsonDecoder()
+	if err != nil {
+		return err
+	}
+	if err = expectDelim(jd, '{'); err != nil {
+		return err
+	}
+	for jd.More() {
+		tok, err := jd.Token()
+		if err != nil {
+			return err
+		}
+		if key, _ := tok.(string); key == name {
+			return jd.Decode(v)
+		}
+		var raw json.RawMessage
+		if err = jd.Decode(&raw); err != nil {
+			return err
+		}
+	}
+	return expectDelim(jd, '}')
+}
+
+// expectDelim reads the next token and checks that it is the given delimiter.
+func expectDelim(jd *json.Decoder, delim json.Delim) error {
+	tok, err := jd.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return errors.New("gltf: invalid JSON document")
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDecoder_DecodeStream(t *testing.T) {
+	src := &Document{
+		Asset:       Asset{Version: "2.0"},
+		Accessors:   []Accessor{{BufferView: Index(0), ComponentType: UnsignedByte, Count: 4, Type: Scalar}},
+		BufferViews: []BufferView{{ByteLength: 4}},
+		Buffers:     []Buffer{{ByteLength: 4, Data: []byte{1, 2, 3, 4}}},
+		Meshes:      []Mesh{{Name: "m", Primitives: []Primitive{{Indices: Index(0)}}}},
+		Nodes:       []Node{{Name: "a", Children: []uint32{1}}, {Name: "b", Mesh: Index(0)}},
+		Scenes:      []Scene{{Nodes: []uint32{0}}},
+	}
+	for _, asBinary := range []bool{true, false} {
+		if !asBinary {
+			src.Buffers[0].EmbeddedResource()
+		}
+		buf := new(bytes.Buffer)
+		if err := NewEncoder(buf, nil, asBinary).Encode(src); err != nil {
+			t.Fatalf("Encoder.Encode() error = %v", err)
+		}
+		var nodes []string
+		var accessors []uint32
+		cb := &StreamCallbacks{
+			OnNode: func(index uint32, node *Node) error {
+				if index != uint32(len(nodes)) {
+					t.Errorf("OnNode() index = %d, want %d", index, len(nodes))
+				}
+				nodes = append(nodes, node.Name)
+				return nil
+			},
+			OnAccessor: func(index uint32, acc *Accessor) error {
+				accessors = append(accessors, acc.Count)
+				return nil
+			},
+		}
+		doc := new(Document)
+		if err := NewDecoder(buf, nil).DecodeStream(doc, cb); err != nil {
+			t.Fatalf("Decoder.DecodeStream() binary %v error = %v", asBinary, err)
+		}
+		if diff := deep.Equal(nodes, []string{"a", "b"}); diff != nil {
+			t.Errorf("Decoder.DecodeStream() nodes = %v", diff)
+		}
+		if diff := deep.Equal(accessors, []uint32{4}); diff != nil {
+			t.Errorf("Decoder.DecodeStream() accessors = %v", diff)
+		}
+		if len(doc.Nodes) != 0 || len(doc.Accessors) != 0 {
+			t.Errorf("Decoder.DecodeStream() stored streamed elements")
+		}
+		if diff := deep.Equal(doc.Meshes, src.Meshes); diff != nil {
+			t.Errorf("Decoder.DecodeStream() meshes = %v", diff)
+		}
+		if diff := deep.Equal(doc.Buffers[0].Data, src.Buffers[0].Data); diff != nil {
+			t.Errorf("Decoder.DecodeStream() buffer = %v", diff)
+		}
+	}
+}
+
+func TestDecoder_DecodeStream_Errors(t *testing.T) {
+	errCallback := errors.New("callback")
+	cb := &StreamCallbacks{OnNode: func(uint32, *Node) error { return errCallback }}
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"notObject", "[]"},
+		{"notArray", `{"nodes": {}}`},
+		{"invalidElement", `{"nodes": [1]}`},
+		{"callback", `{"nodes": [{}]}`},
+		{"invalidRest", `{"asset": 1}`},
+		{"unfinished", `{"nodes": [{}`},
+		{"version", `{"asset": {"version": "1.0"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewDecoder(bytes.NewBufferString(tt.data), nil).DecodeStream(new(Document), cb); err == nil {
+				t.Error("Decoder.DecodeStream() expected error")
+			}
+		})
+	}
+}
+
+func TestDecoder_DecodeStream_Extras(t *testing.T) {
+	data := `{"asset":{"version":"2.0","extras":{"id":12345678901234567890}},"nodes":[{"extras":[1,2.50]}]}`
+	tests := []struct {
+		name      string
+		rawExtras bool
+		useNumber bool
+		wantAsset interface{}
+		wantNode  interface{}
+	}{
+		{"raw", true, false, json.RawMessage(`{"id":12345678901234567890}`), json.RawMessage(`[1,2.50]`)},
+		{"number", false, true, map[string]interface{}{"id": json.Number("12345678901234567890")}, []interface{}{json.Number("1"), json.Number("2.50")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var node interface{}
+			cb := &StreamCallbacks{OnNode: func(_ uint32, n *Node) error {
+				node = n.Extras
+				return nil
+			}}
+			doc := new(Document)
+			d := NewDecoder(bytes.NewBufferString(data), nil).SetRawExtras(tt.rawExtras).SetUseNumber(tt.useNumber)
+			if err := d.DecodeStream(doc, cb); err != nil {
+				t.Fatalf("Decoder.DecodeStream() error = %v", err)
+			}
+			if diff := deep.Equal(doc.Asset.Extras, tt.wantAsset); diff != nil {
+				t.Errorf("Decoder.DecodeStream() asset = %v", diff)
+			}
+			if diff := deep.Equal(node, tt.wantNode); diff != nil {
+				t.Errorf("Decoder.DecodeStream() node = %v", diff)
+			}
+		})
+	}
+}
+
+func TestStreamCallbacks_handlers(t *testing.T) {
+	// Every callback must have an entry in the handlers table.
+	cb := new(StreamCallbacks)
+	v := reflect.ValueOf(cb).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		v.Field(i).Set(reflect.MakeFunc(v.Field(i).Type(), func([]reflect.Value) []reflect.Value {
+			return []reflect.Value{reflect.Zero(reflect.TypeOf((*error)(nil)).Elem())}
+		}))
+	}
+	if got := len(cb.handlers(NewDecoder(nil, nil))); got != v.NumField() {
+		t.Errorf("StreamCallbacks.handlers() = %d handlers, want %d", got, v.NumField())
+	}
+}
+
+func TestDecoder_DecodeStream_StrictColors(t *testing.T) {
+	data := `{"asset": {"version": "2.0"}, "materials": [{}, {"emissiveFactor": [0.5, 2, 0.5]}]}`
+	want := ValidationErrors{{"/materials/1/emissiveFactor/1", ErrColorOutOfRange}}
+	var called []uint32
+	cb := &StreamCallbacks{OnMaterial: func(i uint32, _ *Material) error {
+		called = append(called, i)
+		return nil
+	}}
+	err := NewDecoder(bytes.NewBufferString(data), nil).SetStrictColors(true).DecodeStream(new(Document), cb)
+	if diff := diffValidationErrors(err, want); diff != nil {
+		t.Errorf("Decoder.DecodeStream() streamed = %v", diff)
+	}
+	if diff := deep.Equal(called, []uint32{0}); diff != nil {
+		t.Errorf("Decoder.DecodeStream() callbacks = %v", diff)
+	}
+	err = NewDecoder(bytes.NewBufferString(data), nil).SetStrictColors(true).DecodeStream(new(Document), &StreamCallbacks{})
+	if diff := diffValidationErrors(err, want); diff != nil {
+		t.Errorf("Decoder.DecodeStream() stored = %v", diff)
+	}
+	if err = NewDecoder(bytes.NewBufferString(data), nil).DecodeStream(new(Document), cb); err != nil {
+		t.Errorf("Decoder.DecodeStream() error = %v", err)
+	}
+}
+
+func TestDecoder_DecodeProperty(t *testing.T) {
+	src := &Document{
+		Asset:     Asset{Version: "2.0"},
+		Buffers:   []Buffer{{ByteLength: 4, Data: []byte{1, 2, 3, 4}}},
+		Materials: []Material{{Name: "a", AlphaMode: Mask, AlphaCutoff: Float64(0.2)}, {Name: "b", AlphaCutoff: Float64(0.5)}},
+		Nodes:     []Node{{Name: "n"}},
+	}
+	for _, asBinary := range []bool{true, false} {
+		data, err := Marshal(src, asBinary)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		var materials []Material
+		if err = NewDecoder(bytes.NewReader(data), nil).DecodeProperty("materials", &materials); err != nil {
+			t.Fatalf("Decoder.DecodeProperty() error = %v", err)
+		}
+		if diff := deep.Equal(materials, src.Materials); diff != nil {
+			t.Errorf("Decoder.DecodeProperty() = %v", diff)
+		}
+		// The first buffer has no URI in the glTF encoding.
+		src.Buffers[0].EmbeddedResource()
+	}
+
+	var skins []Skin
+	if err := NewDecoder(bytes.NewBufferString(`{"asset": {"version": "2.0"}}`), nil).DecodeProperty("skins", &skins); err != nil || skins != nil {
+		t.Errorf("Decoder.DecodeProperty() = %v, %v, want nil", skins, err)
+	}
+	if err := NewDecoder(bytes.NewBufferString(`{"materials": 1}`), nil).DecodeProperty("materials", &skins); err == nil {
+		t.Error("Decoder.DecodeProperty() expected error")
+	}
+	if err := NewDecoder(bytes.NewBufferString(`[]`), nil).DecodeProperty("materials", &skins); err == nil {
+		t.Error("Decoder.DecodeProperty() expected error")
+	}
+}
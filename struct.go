@@ -6,7 +6,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 )
 
 // Index is an utility function that returns a pointer to a uint32.
@@ -52,6 +55,48 @@ type Document struct {
 	Textures           []Texture    `json:"textures,omitempty" validate:"dive"`
 }
 
+// NewDocument returns a minimal valid document, targeting glTF 2.0 and generated by this package,
+// with an empty scene that is the default one.
+func NewDocument() *Document {
+	return &Document{
+		Asset:  Asset{Generator: "qmuntal/gltf", Version: "2.0"},
+		Scene:  Index(0),
+		Scenes: []Scene{{}},
+	}
+}
+
+// Reset clears the document so it can be reused by Decode, keeping the capacity of its slices
+// and the allocation of its extensions map to reduce the garbage generated when many files are decoded.
+// Every element of the slices, up to their capacity, is set to its zero value,
+// so no data of the previous document leaks into the next one, but the elements themselves are not pooled:
+// the buffers data and the nested slices and maps are released.
+// After a reset the top-level slices are empty but not nil.
+// Decode does not reset the document, properties missing in the input keep their previous value.
+func (d *Document) Reset() {
+	v := reflect.ValueOf(d).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		switch f.Kind() {
+		case reflect.Slice:
+			if f.IsNil() {
+				continue
+			}
+			f.Set(f.Slice(0, f.Cap()))
+			zero := reflect.Zero(f.Type().Elem())
+			for j := 0; j < f.Len(); j++ {
+				f.Index(j).Set(zero)
+			}
+			f.SetLen(0)
+		case reflect.Map:
+			for _, key := range f.MapKeys() {
+				f.SetMapIndex(key, reflect.Value{})
+			}
+		default:
+			f.Set(reflect.Zero(f.Type()))
+		}
+	}
+}
+
 // An Accessor is a typed view into a bufferView.
 // An accessor provides a typed view into a bufferView or a subset of a bufferView
 // similar to how WebGL's vertexAttribPointer() defines an attribute in a buffer.
@@ -123,14 +168,40 @@ func (b *Buffer) marshalData() ([]uint8, error) {
 	if !b.IsEmbeddedResource() {
 		return nil, nil
 	}
-	startPos := len(mimetypeApplicationOctet) + 1
-	sl, err := base64.StdEncoding.DecodeString(b.URI[startPos:])
+	encoded, ok := b.embeddedData()
+	if !ok {
+		return nil, errors.New("gltf: embedded buffer URI without data separator")
+	}
+	sl, err := decodeBase64(encoded)
 	if len(sl) == 0 || err != nil {
 		return nil, err
 	}
 	return sl, nil
 }
 
+// embeddedData returns the base64 data of an embedded buffer URI, which follows the "," separator.
+// The boolean is false if the URI does not have the separator after the media type.
+func (b *Buffer) embeddedData() (string, bool) {
+	data := strings.TrimPrefix(b.URI, mimetypeApplicationOctet)
+	if !strings.HasPrefix(data, ",") {
+		return "", false
+	}
+	return data[1:], true
+}
+
+// embeddedLength returns the number of bytes encoded in the URI of an embedded buffer without decoding them.
+// The boolean is false if the buffer is not an embedded resource or its URI does not have the data separator.
+func (b *Buffer) embeddedLength() (int, bool) {
+	if !b.IsEmbeddedResource() {
+		return 0, false
+	}
+	encoded, ok := b.embeddedData()
+	if !ok {
+		return 0, false
+	}
+	return base64.RawStdEncoding.DecodedLen(len(strings.TrimRight(encoded, "="))), true
+}
+
 // BufferView is a view into a buffer generally representing a subset of the buffer.
 type BufferView struct {
 	Extensions Extensions  `json:"extensions,omitempty"`
@@ -183,6 +254,18 @@ func (n *Node) RotationOrDefault() [4]float64 {
 	return n.Rotation
 }
 
+// NormalizeRotation scales the node rotation to a unit quaternion.
+// Empty rotations are left untouched, as they already represent the default one.
+func (n *Node) NormalizeRotation() {
+	if n.Rotation == emptyRotation {
+		return
+	}
+	l := quaternionLength(n.Rotation)
+	for i := range n.Rotation {
+		n.Rotation[i] /= l
+	}
+}
+
 // ScaleOrDefault returns the node scale if it represents a valid scale factor, else return the default one.
 func (n *Node) ScaleOrDefault() [3]float64 {
 	if n.Scale == emptyScale {
@@ -339,6 +422,44 @@ type Material struct {
 	DoubleSided          bool                  `json:"doubleSided,omitempty"`
 }
 
+// A TextureReferrer is implemented by the material extensions that reference textures,
+// such as KHR_materials_pbrSpecularGlossiness, so Material.WalkTextures can visit them.
+type TextureReferrer interface {
+	WalkTextures(fn func(index, texCoord *uint32))
+}
+
+// WalkTextures calls fn with pointers to the index and the texture coordinates set of every texture referenced by the material,
+// so they can be read or updated: base color, metallic-roughness, normal, occlusion and emissive textures,
+// followed by the textures of the extensions that implement TextureReferrer, in order of extension name.
+func (m *Material) WalkTextures(fn func(index, texCoord *uint32)) {
+	info := func(ti *TextureInfo) {
+		if ti != nil {
+			fn(&ti.Index, &ti.TexCoord)
+		}
+	}
+	if pbr := m.PBRMetallicRoughness; pbr != nil {
+		info(pbr.BaseColorTexture)
+		info(pbr.MetallicRoughnessTexture)
+	}
+	if m.NormalTexture != nil && m.NormalTexture.Index != nil {
+		fn(m.NormalTexture.Index, &m.NormalTexture.TexCoord)
+	}
+	if m.OcclusionTexture != nil && m.OcclusionTexture.Index != nil {
+		fn(m.OcclusionTexture.Index, &m.OcclusionTexture.TexCoord)
+	}
+	info(m.EmissiveTexture)
+	keys := make([]string, 0, len(m.Extensions))
+	for key := range m.Extensions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if tr, ok := m.Extensions[key].(TextureReferrer); ok {
+			tr.WalkTextures(fn)
+		}
+	}
+}
+
 // AlphaCutoffOrDefault returns the scale if it is not nil, else return the default one.
 func (m *Material) AlphaCutoffOrDefault() float64 {
 	if m.AlphaCutoff == nil {
@@ -359,19 +480,24 @@ func (m *Material) UnmarshalJSON(data []byte) error {
 }
 
 // MarshalJSON marshal the material with the correct default values.
+// The emissiveFactor is omitted when it is the default black and the alphaCutoff when it is the default 0.5.
+// As omitempty has no effect on arrays, both fields are shadowed by optional pointers
+// instead of removing them from the encoded text,
+// so the same values inside extensions or extras are never touched.
 func (m *Material) MarshalJSON() ([]byte, error) {
 	type alias Material
-	out, err := json.Marshal(&struct{ *alias }{alias: (*alias)(m)})
-	if err == nil {
-		if m.AlphaCutoff != nil && *m.AlphaCutoff == 0.5 {
-			out = removeProperty([]byte(`"alphaCutoff":0.5`), out)
-		}
-		if m.EmissiveFactor == [3]float64{0, 0, 0} {
-			out = removeProperty([]byte(`"emissiveFactor":[0,0,0]`), out)
-		}
-		out = sanitizeJSON(out)
+	tmp := struct {
+		*alias
+		EmissiveFactor *[3]float64 `json:"emissiveFactor,omitempty"`
+		AlphaCutoff    *float64    `json:"alphaCutoff,omitempty"`
+	}{alias: (*alias)(m)}
+	if m.EmissiveFactor != [3]float64{} {
+		tmp.EmissiveFactor = &m.EmissiveFactor
 	}
-	return out, err
+	if m.AlphaCutoff != nil && *m.AlphaCutoff != 0.5 {
+		tmp.AlphaCutoff = m.AlphaCutoff
+	}
+	return json.Marshal(&tmp)
 }
 
 // A NormalTexture references to a normal texture.
@@ -486,6 +612,18 @@ func (c *RGBA) MarshalJSON() ([]byte, error) {
 	return json.Marshal([4]float64{c.R, c.G, c.B, c.A})
 }
 
+// ToLinear returns the color converted from the sRGB color space, as used by color pickers, to the linear color space
+// used by the factors of the materials. The alpha component is not converted.
+func (c RGBA) ToLinear() RGBA {
+	return RGBA{srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B), c.A}
+}
+
+// ToSRGB returns the color converted from the linear color space to the sRGB color space.
+// The alpha component is not converted.
+func (c RGBA) ToSRGB() RGBA {
+	return RGBA{linearToSRGB(c.R), linearToSRGB(c.G), linearToSRGB(c.B), c.A}
+}
+
 // The RGB components of a color.
 // Each element must be greater than or equal to 0 and less than or equal to 1.
 type RGB struct {
@@ -512,6 +650,16 @@ func (c *RGB) MarshalJSON() ([]byte, error) {
 	return json.Marshal([3]float64{c.R, c.G, c.B})
 }
 
+// ToLinear returns the color converted from the sRGB color space to the linear color space.
+func (c RGB) ToLinear() RGB {
+	return RGB{srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B)}
+}
+
+// ToSRGB returns the color converted from the linear color space to the sRGB color space.
+func (c RGB) ToSRGB() RGB {
+	return RGB{linearToSRGB(c.R), linearToSRGB(c.G), linearToSRGB(c.B)}
+}
+
 // PBRMetallicRoughness defines a set of parameter values that are used to define the metallic-roughness material model from Physically-Based Rendering (PBR) methodology.
 type PBRMetallicRoughness struct {
 	Extensions               Extensions   `json:"extensions,omitempty"`
@@ -594,6 +742,30 @@ type Texture struct {
 	Source     *uint32     `json:"source,omitempty"`
 }
 
+// A TextureSource is a texture extension that defines an alternative image for the texture,
+// usually encoded in a format that is not supported by the core specification.
+type TextureSource interface {
+	ImageIndex() uint32
+}
+
+// A MaterialReferrer is implemented by the primitive extensions that reference materials,
+// such as KHR_materials_variants, so the functions that reindex the materials of a document can update them.
+type MaterialReferrer interface {
+	MaterialIndices() []*uint32 // Pointers to the material indices stored in the extension.
+}
+
+// ResolveSource returns the index of the image that should be used by a client that supports the given extensions.
+// The extensions are checked in order of preference and the first one defined in the texture is picked,
+// falling back to the core source if none of them is defined.
+func (t *Texture) ResolveSource(supported ...string) *uint32 {
+	for _, ext := range supported {
+		if src, ok := t.Extensions[ext].(TextureSource); ok {
+			return Index(src.ImageIndex())
+		}
+	}
+	return t.Source
+}
+
 // Sampler of a texture for filtering and wrapping modes.
 type Sampler struct {
 	Extensions Extensions   `json:"extensions,omitempty"`
@@ -612,26 +784,43 @@ type Image struct {
 	Extras     interface{} `json:"extras,omitempty"`
 	Name       string      `json:"name,omitempty"`
 	URI        string      `json:"uri,omitempty" validate:"omitempty"`
-	MimeType   string      `json:"mimeType,omitempty" validate:"omitempty,oneof=image/jpeg image/png"` // Manadatory if BufferView is defined.
-	BufferView uint32      `json:"bufferView,omitempty"`                                               // Use this instead of the image's uri property.
+	MimeType   string      `json:"mimeType,omitempty" validate:"omitempty,oneof=image/jpeg image/png image/avif image/ktx2"` // Manadatory if BufferView is defined.
+	BufferView uint32      `json:"bufferView,omitempty"`                                                                     // Use this instead of the image's uri property.
 }
 
 // IsEmbeddedResource returns true if the buffer points to an embedded resource.
 func (im *Image) IsEmbeddedResource() bool {
-	return strings.HasPrefix(im.URI, mimetypeImagePNG) || strings.HasPrefix(im.URI, mimetypeImageJPG)
+	return im.embeddedMimetype() != ""
+}
+
+func (im *Image) embeddedMimetype() string {
+	for _, mimetype := range []string{mimetypeImagePNG, mimetypeImageJPG, mimetypeImageAVIF, mimetypeImageKTX2} {
+		if strings.HasPrefix(im.URI, mimetype) {
+			return mimetype
+		}
+	}
+	return ""
 }
 
 // MarshalData decode the image from the URI. If the image is not en embedded resource the returned array will be empty.
 func (im *Image) MarshalData() ([]uint8, error) {
-	if !im.IsEmbeddedResource() {
+	mimetype := im.embeddedMimetype()
+	if mimetype == "" {
 		return []uint8{}, nil
 	}
-	mimetype := mimetypeImagePNG
-	if strings.HasPrefix(im.URI, mimetypeImageJPG) {
-		mimetype = mimetypeImageJPG
-	}
 	startPos := len(mimetype) + 1
-	return base64.StdEncoding.DecodeString(im.URI[startPos:])
+	return decodeBase64(im.URI[startPos:])
+}
+
+// decodeBase64 decodes the data of a data URI.
+// Besides the standard encoding, it accepts the URL-safe alphabet and missing padding,
+// which some tools produce.
+func decodeBase64(s string) ([]byte, error) {
+	enc := base64.RawStdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.RawURLEncoding
+	}
+	return enc.DecodeString(strings.TrimRight(s, "="))
 }
 
 // An Animation keyframe.
@@ -679,13 +868,26 @@ type Extensions map[string]interface{}
 
 type envelope map[string]json.RawMessage
 
-var extensions = make(map[string]func() json.Unmarshaler)
+var (
+	extMu      sync.RWMutex
+	extensions = make(map[string]func() json.Unmarshaler)
+)
 
 // RegisterExtension registers a function that returns a new extension of the given
 // byte array. This is intended to be called from the init function in
-// packages that implement extensions.
+// packages that implement extensions, but it is safe to call concurrently with decoding.
 func RegisterExtension(key string, f func() json.Unmarshaler) {
+	extMu.Lock()
 	extensions[key] = f
+	extMu.Unlock()
+}
+
+// extensionFactory returns the function registered for the extension key, if any.
+func extensionFactory(key string) (func() json.Unmarshaler, bool) {
+	extMu.RLock()
+	f, ok := extensions[key]
+	extMu.RUnlock()
+	return f, ok
 }
 
 // UnmarshalJSON unmarshal the extensions with the supported extensions initialized.
@@ -697,7 +899,7 @@ func (ext *Extensions) UnmarshalJSON(data []byte) error {
 	err := json.Unmarshal(data, &raw)
 	if err == nil {
 		for key, value := range raw {
-			if extFactory, ok := extensions[key]; ok {
+			if extFactory, ok := extensionFactory(key); ok {
 				n := extFactory()
 				err := json.Unmarshal(value, n)
 				if err != nil {
@@ -714,6 +916,64 @@ func (ext *Extensions) UnmarshalJSON(data []byte) error {
 	return err
 }
 
+// MarshalJSON marshal the extensions sorted by identifier.
+// The payloads stored as json.RawMessage are encoded again with the keys of their objects sorted
+// and without insignificant whitespace, so the output only depends on their content.
+// Numbers are kept exactly as they were written.
+func (ext Extensions) MarshalJSON() ([]byte, error) {
+	if ext == nil {
+		return []byte("null"), nil
+	}
+	keys := make([]string, 0, len(ext))
+	for key := range ext {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		value := ext[key]
+		if raw, ok := value.(json.RawMessage); ok {
+			value = sortedRawMessage(raw)
+		}
+		payload, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(payload)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// sortedRawMessage returns the raw JSON encoded again, which sorts the keys of its objects.
+// Invalid JSON is returned as it is.
+func sortedRawMessage(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return raw
+	}
+	var v interface{}
+	jd := json.NewDecoder(bytes.NewReader(raw))
+	jd.UseNumber()
+	if err := jd.Decode(&v); err != nil {
+		return raw
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return raw
+	}
+	return out
+}
+
 func removeProperty(str []byte, b []byte) []byte {
 	b = bytes.Replace(b, str, []byte(""), 1)
 	return bytes.Replace(b, []byte(`,,`), []byte(","), 1)
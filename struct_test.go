@@ -1,11 +1,31 @@
 package gltf
 
 import (
+	"bytes"
 	"encoding/json"
+	"math"
 	"reflect"
+	"sync"
 	"testing"
+
+	"github.com/go-test/deep"
 )
 
+func TestNewDocument(t *testing.T) {
+	doc := NewDocument()
+	want := &Document{
+		Asset:  Asset{Generator: "qmuntal/gltf", Version: "2.0"},
+		Scene:  Index(0),
+		Scenes: []Scene{{}},
+	}
+	if diff := deep.Equal(doc, want); diff != nil {
+		t.Errorf("NewDocument() = %v", diff)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Errorf("NewDocument().Validate() error = %v", err)
+	}
+}
+
 func TestBuffer_IsEmbeddedResource(t *testing.T) {
 	tests := []struct {
 		name string
@@ -50,6 +70,8 @@ func TestImage_IsEmbeddedResource(t *testing.T) {
 	}{
 		{"png", &Image{URI: "data:image/png;base64,dsjdsaGGUDXGA"}, true},
 		{"jpg", &Image{URI: "data:image/png;base64,dsjdsaGGUDXGA"}, true},
+		{"avif", &Image{URI: "data:image/avif;base64,dsjdsaGGUDXGA"}, true},
+		{"ktx2", &Image{URI: "data:image/ktx2;base64,dsjdsaGGUDXGA"}, true},
 		{"external", &Image{URI: "https://web.com/a"}, false},
 	}
 	for _, tt := range tests {
@@ -73,7 +95,10 @@ func TestImage_MarshalData(t *testing.T) {
 		{"empty", &Image{URI: "data:image/png;base64,"}, []uint8{}, false},
 		{"empty", &Image{URI: "data:image/jpeg;base64,"}, []uint8{}, false},
 		{"test", &Image{URI: "data:image/png;base64,TEST"}, []uint8{76, 68, 147}, false},
+		{"avif", &Image{URI: "data:image/avif;base64,TEST"}, []uint8{76, 68, 147}, false},
+		{"ktx2", &Image{URI: "data:image/ktx2;base64,TEST"}, []uint8{76, 68, 147}, false},
 		{"complex", &Image{URI: "data:image/png;base64,YW55IGNhcm5hbCBwbGVhcw=="}, []uint8{97, 110, 121, 32, 99, 97, 114, 110, 97, 108, 32, 112, 108, 101, 97, 115}, false},
+		{"urlSafeNoPadding", &Image{URI: "data:image/png;base64,-_8"}, []uint8{251, 255}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -89,6 +114,79 @@ func TestImage_MarshalData(t *testing.T) {
 	}
 }
 
+type textureSource uint32
+
+func (t textureSource) ImageIndex() uint32 { return uint32(t) }
+
+func TestTexture_ResolveSource(t *testing.T) {
+	tex := &Texture{Source: Index(0), Extensions: Extensions{"EXT_a": textureSource(1), "EXT_b": textureSource(2), "EXT_raw": json.RawMessage(`{"source":3}`)}}
+	tests := []struct {
+		name      string
+		t         *Texture
+		supported []string
+		want      *uint32
+	}{
+		{"none", tex, nil, Index(0)},
+		{"unknown", tex, []string{"EXT_c"}, Index(0)},
+		{"raw", tex, []string{"EXT_raw"}, Index(0)},
+		{"first", tex, []string{"EXT_a", "EXT_b"}, Index(1)},
+		{"preference", tex, []string{"EXT_c", "EXT_b", "EXT_a"}, Index(2)},
+		{"noSource", &Texture{}, []string{"EXT_a"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.t.ResolveSource(tt.supported...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Texture.ResolveSource() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type textureReferrer []uint32
+
+func (t textureReferrer) WalkTextures(fn func(index, texCoord *uint32)) {
+	for i := range t {
+		fn(&t[i], new(uint32))
+	}
+}
+
+func TestMaterial_WalkTextures(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Material
+		want [][2]uint32
+	}{
+		{"empty", &Material{}, nil},
+		{"core", &Material{
+			PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 1}, MetallicRoughnessTexture: &TextureInfo{Index: 2, TexCoord: 1}},
+			NormalTexture:        &NormalTexture{Index: Index(3)},
+			OcclusionTexture:     &OcclusionTexture{Index: Index(4), TexCoord: 2},
+			EmissiveTexture:      &TextureInfo{Index: 5},
+		}, [][2]uint32{{1, 0}, {2, 1}, {3, 0}, {4, 2}, {5, 0}}},
+		{"noIndex", &Material{NormalTexture: &NormalTexture{}, OcclusionTexture: &OcclusionTexture{}}, nil},
+		{"extensions", &Material{
+			EmissiveTexture: &TextureInfo{Index: 0},
+			Extensions:      Extensions{"EXT_b": textureReferrer{3}, "EXT_a": textureReferrer{1, 2}, "EXT_raw": json.RawMessage(`{"index":4}`)},
+		}, [][2]uint32{{0, 0}, {1, 0}, {2, 0}, {3, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][2]uint32
+			tt.m.WalkTextures(func(index, texCoord *uint32) {
+				got = append(got, [2]uint32{*index, *texCoord})
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Material.WalkTextures() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	m := &Material{EmissiveTexture: &TextureInfo{Index: 1}}
+	m.WalkTextures(func(index, _ *uint32) { *index = 7 })
+	if m.EmissiveTexture.Index != 7 {
+		t.Errorf("Material.WalkTextures() did not update the index, got %d", m.EmissiveTexture.Index)
+	}
+}
+
 func TestNode_UnmarshalJSON(t *testing.T) {
 	type args struct {
 		data []byte
@@ -218,6 +316,47 @@ func TestOcclusionTexture_UnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestRGBA_ToLinear(t *testing.T) {
+	tests := []struct {
+		name string
+		c    RGBA
+		want RGBA
+	}{
+		{"black", RGBA{0, 0, 0, 0.5}, RGBA{0, 0, 0, 0.5}},
+		{"white", RGBA{1, 1, 1, 1}, RGBA{1, 1, 1, 1}},
+		{"linearSegment", RGBA{0.04, 0.02, 0, 1}, RGBA{0.04 / 12.92, 0.02 / 12.92, 0, 1}},
+		{"mid", RGBA{0.5, 0.5, 0.5, 0.5}, RGBA{0.214041, 0.214041, 0.214041, 0.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.c.ToLinear()
+			if !rgbaAlmostEqual(got, tt.want) {
+				t.Errorf("RGBA.ToLinear() = %v, want %v", got, tt.want)
+			}
+			if back := got.ToSRGB(); !rgbaAlmostEqual(back, tt.c) {
+				t.Errorf("RGBA.ToSRGB() = %v, want %v", back, tt.c)
+			}
+		})
+	}
+}
+
+func TestRGB_ToLinear(t *testing.T) {
+	c := RGB{0.2, 0.5, 0.8}
+	got := c.ToLinear()
+	want := RGB{0.033105, 0.214041, 0.603827}
+	if !rgbaAlmostEqual(RGBA{got.R, got.G, got.B, 1}, RGBA{want.R, want.G, want.B, 1}) {
+		t.Errorf("RGB.ToLinear() = %v, want %v", got, want)
+	}
+	if back := got.ToSRGB(); !rgbaAlmostEqual(RGBA{back.R, back.G, back.B, 1}, RGBA{c.R, c.G, c.B, 1}) {
+		t.Errorf("RGB.ToSRGB() = %v, want %v", back, c)
+	}
+}
+
+func rgbaAlmostEqual(a, b RGBA) bool {
+	const tolerance = 1e-6
+	return math.Abs(a.R-b.R) < tolerance && math.Abs(a.G-b.G) < tolerance && math.Abs(a.B-b.B) < tolerance && a.A == b.A
+}
+
 func TestPBRMetallicRoughness_UnmarshalJSON(t *testing.T) {
 	type args struct {
 		data []byte
@@ -306,6 +445,10 @@ func TestMaterial_MarshalJSON(t *testing.T) {
 		{"default", &Material{AlphaCutoff: Float64(0.5), AlphaMode: Opaque}, []byte(`{}`), false},
 		{"empty", &Material{AlphaMode: Blend}, []byte(`{"alphaMode":"BLEND"}`), false},
 		{"nodefault", &Material{AlphaCutoff: Float64(1), AlphaMode: Blend}, []byte(`{"alphaMode":"BLEND","alphaCutoff":1}`), false},
+		{"emissive", &Material{EmissiveFactor: [3]float64{1, 0, 0.5}}, []byte(`{"emissiveFactor":[1,0,0.5]}`), false},
+		{"emissiveDefault", &Material{Name: "a", EmissiveFactor: [3]float64{0, 0, 0}, DoubleSided: true}, []byte(`{"name":"a","doubleSided":true}`), false},
+		{"defaultsInExtras", &Material{Extras: map[string]interface{}{"emissiveFactor": []int{0, 0, 0}, "alphaCutoff": 0.5}, AlphaCutoff: Float64(0.5)},
+			[]byte(`{"extras":{"alphaCutoff":0.5,"emissiveFactor":[0,0,0]}}`), false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -404,10 +547,16 @@ func TestBuffer_marshalData(t *testing.T) {
 		wantErr bool
 	}{
 		{"error", &Buffer{URI: "data:application/octet-stream;base64,_"}, nil, true},
+		{"noSeparator", &Buffer{URI: "data:application/octet-stream;base64"}, nil, true},
 		{"external", &Buffer{URI: "http://web.com"}, nil, false},
 		{"empty", &Buffer{URI: "data:application/octet-stream;base64,"}, nil, false},
 		{"test", &Buffer{URI: "data:application/octet-stream;base64,TEST"}, []uint8{76, 68, 147}, false},
 		{"complex", &Buffer{URI: "data:application/octet-stream;base64,YW55IGNhcm5hbCBwbGVhcw=="}, []uint8{97, 110, 121, 32, 99, 97, 114, 110, 97, 108, 32, 112, 108, 101, 97, 115}, false},
+		{"noPadding", &Buffer{URI: "data:application/octet-stream;base64,YW55IGNhcm5hbCBwbGVhcw"}, []uint8{97, 110, 121, 32, 99, 97, 114, 110, 97, 108, 32, 112, 108, 101, 97, 115}, false},
+		{"std", &Buffer{URI: "data:application/octet-stream;base64,+/8="}, []uint8{251, 255}, false},
+		{"urlSafe", &Buffer{URI: "data:application/octet-stream;base64,-_8="}, []uint8{251, 255}, false},
+		{"urlSafeNoPadding", &Buffer{URI: "data:application/octet-stream;base64,-_8"}, []uint8{251, 255}, false},
+		{"mixedAlphabet", &Buffer{URI: "data:application/octet-stream;base64,+_8="}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -484,6 +633,27 @@ func TestNode_RotationOrDefault(t *testing.T) {
 	}
 }
 
+func TestNode_NormalizeRotation(t *testing.T) {
+	tests := []struct {
+		name string
+		n    *Node
+		want [4]float64
+	}{
+		{"default", &Node{Rotation: DefaultRotation}, DefaultRotation},
+		{"zeros", &Node{Rotation: emptyRotation}, emptyRotation},
+		{"other", &Node{Rotation: [4]float64{0, 0, 3, 4}}, [4]float64{0, 0, 0.6, 0.8}},
+		{"negative", &Node{Rotation: [4]float64{0, -3, 0, 4}}, [4]float64{0, -0.6, 0, 0.8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.n.NormalizeRotation()
+			if diff := deep.Equal(tt.n.Rotation, tt.want); diff != nil {
+				t.Errorf("Node.NormalizeRotation() = %v", diff)
+			}
+		})
+	}
+}
+
 func TestNode_ScaleOrDefault(t *testing.T) {
 	tests := []struct {
 		name string
@@ -643,6 +813,35 @@ func (f *fakeExt) UnmarshalJSON(data []byte) error {
 	return err
 }
 
+func TestExtensions_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		ext     Extensions
+		want    string
+		wantErr bool
+	}{
+		{"nil", nil, "null", false},
+		{"empty", Extensions{}, "{}", false},
+		{"sorted", Extensions{
+			"EXT_c": nil,
+			"EXT_b": json.RawMessage(`{"b": 1, "a": {"d": 1.50, "c": [ 2 ]}}`),
+			"EXT_a": map[string]interface{}{"y": 1, "x": true},
+		}, `{"EXT_a":{"x":true,"y":1},"EXT_b":{"a":{"c":[2],"d":1.50},"b":1},"EXT_c":null}`, false},
+		{"invalidRaw", Extensions{"EXT_a": json.RawMessage(`{"a":`)}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.ext)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Extensions.MarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Extensions.MarshalJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestExtensions_UnmarshalJSON(t *testing.T) {
 	RegisterExtension("fake_ext", func() json.Unmarshaler { return new(fakeExt) })
 	type args struct {
@@ -678,3 +877,58 @@ func TestExtensions_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterExtension_concurrent(t *testing.T) {
+	// Run with -race to detect unsynchronized access to the registry.
+	data := []byte(`{"fake_ext_concurrent": {"a":2}}`)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterExtension("fake_ext_concurrent", func() json.Unmarshaler { return new(fakeExt) })
+		}()
+		go func() {
+			defer wg.Done()
+			ext := Extensions{}
+			if err := ext.UnmarshalJSON(data); err != nil {
+				t.Errorf("Extensions.UnmarshalJSON() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	ext := Extensions{}
+	if err := ext.UnmarshalJSON(data); err != nil {
+		t.Fatalf("Extensions.UnmarshalJSON() error = %v", err)
+	}
+	if _, ok := ext["fake_ext_concurrent"].(*fakeExt); !ok {
+		t.Errorf("Extensions.UnmarshalJSON() = %v, want registered extension", ext)
+	}
+}
+
+func TestDocument_Reset(t *testing.T) {
+	first := `{"asset": {"version": "2.0", "generator": "first"}, "scene": 0, "scenes": [{"nodes": [0, 1]}],
+	"nodes": [{"name": "a", "mesh": 0}, {"name": "b"}], "materials": [{"name": "m"}], "extensions": {"EXT_a": {}}}`
+	second := `{"asset": {"version": "2.0"}, "nodes": [{"name": "c"}]}`
+	doc := new(Document)
+	if err := NewDecoder(bytes.NewBufferString(first), nil).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	nodes := doc.Nodes
+	doc.Reset()
+	if len(doc.Nodes) != 0 || cap(doc.Nodes) != cap(nodes) || nodes[0].Name != "" || nodes[1].Mesh != nil {
+		t.Errorf("Document.Reset() did not clear the nodes keeping their capacity")
+	}
+	if err := NewDecoder(bytes.NewBufferString(second), nil).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	if &doc.Nodes[0] != &nodes[0] {
+		t.Error("Document.Reset() nodes were not reused")
+	}
+	if doc.Nodes[0].Name != "c" || doc.Nodes[0].Mesh != nil || len(doc.Nodes) != 1 {
+		t.Errorf("Document.Reset() stale node = %v", doc.Nodes[0])
+	}
+	if len(doc.Materials) != 0 || len(doc.Scenes) != 0 || doc.Scene != nil || doc.Asset.Generator != "" || len(doc.Extensions) != 0 {
+		t.Errorf("Document.Reset() stale document = %+v", doc)
+	}
+}
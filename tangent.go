@@ -0,0 +1,117 @@
+package gltf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// RepairTangentSigns recomputes the handedness stored in the w component of the TANGENT attribute of a primitive.
+// The bitangent of each vertex is derived from the gradient of the texture coordinates used by the normal texture
+// of the material, or TEXCOORD_0 if there is none, and w is set to -1 when it points against normal × tangent and to 1 otherwise.
+// Vertices that do not belong to any triangle with non-degenerate texture coordinates keep the sign of their w component.
+// The tangent data is updated in place, so the accessor must be a non-sparse VEC4 of floats.
+// The return value is the number of vertices whose w component changed.
+func (d *Document) RepairTangentSigns(meshIndex, primitiveIndex uint32) (int, error) {
+	prim, err := d.primitive(meshIndex, primitiveIndex)
+	if err != nil {
+		return 0, err
+	}
+	var set uint32
+	if prim.Material != nil && int(*prim.Material) < len(d.Materials) {
+		if normal := d.Materials[*prim.Material].NormalTexture; normal != nil {
+			set = normal.TexCoord
+		}
+	}
+	read := func(semantic string, accessorType AccessorType) ([]float64, error) {
+		index, ok := prim.Attributes[semantic]
+		if !ok {
+			return nil, fmt.Errorf("gltf: primitive without %s", semantic)
+		}
+		if int(index) >= len(d.Accessors) {
+			return nil, fmt.Errorf("gltf: accessor index %d out of range", index)
+		}
+		if d.Accessors[index].Type != accessorType {
+			return nil, fmt.Errorf("gltf: %s accessor has an invalid type", semantic)
+		}
+		return d.ReadAccessor(index)
+	}
+	positions, err := read("POSITION", Vec3)
+	if err != nil {
+		return 0, err
+	}
+	normals, err := read("NORMAL", Vec3)
+	if err != nil {
+		return 0, err
+	}
+	texCoords, err := read(fmt.Sprintf("TEXCOORD_%d", set), Vec2)
+	if err != nil {
+		return 0, err
+	}
+	tangents, err := read("TANGENT", Vec4)
+	if err != nil {
+		return 0, err
+	}
+	acc := &d.Accessors[prim.Attributes["TANGENT"]]
+	if acc.ComponentType != Float || acc.Sparse != nil {
+		return 0, errors.New("gltf: tangent accessor must be a non-sparse accessor of floats")
+	}
+	count := len(tangents) / 4
+	if len(positions)/3 != count || len(normals)/3 != count || len(texCoords)/2 != count {
+		return 0, errors.New("gltf: all the attributes of a primitive must have the same count")
+	}
+	vec3 := func(values []float64, i uint32) [3]float64 {
+		return [3]float64{values[3*i], values[3*i+1], values[3*i+2]}
+	}
+	bitangents := make([][3]float64, count)
+	err = d.WalkTriangles(meshIndex, primitiveIndex, func(a, b, c uint32) error {
+		if int(a) >= count || int(b) >= count || int(c) >= count {
+			return errors.New("gltf: vertex index out of range")
+		}
+		e1, e2 := sub(vec3(positions, b), vec3(positions, a)), sub(vec3(positions, c), vec3(positions, a))
+		du1, dv1 := texCoords[2*b]-texCoords[2*a], texCoords[2*b+1]-texCoords[2*a+1]
+		du2, dv2 := texCoords[2*c]-texCoords[2*a], texCoords[2*c+1]-texCoords[2*a+1]
+		det := du1*dv2 - du2*dv1
+		if det == 0 {
+			return nil
+		}
+		var bitangent [3]float64
+		for i := range bitangent {
+			bitangent[i] = (e2[i]*du1 - e1[i]*du2) / det
+		}
+		for _, v := range []uint32{a, b, c} {
+			for i := range bitangent {
+				bitangents[v][i] += bitangent[i]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	view, stride, err := d.accessorView(acc)
+	if err != nil {
+		return 0, err
+	}
+	changed := 0
+	for v := 0; v < count; v++ {
+		w := tangents[4*v+3]
+		sign := 1.0
+		if bitangents[v] == [3]float64{} {
+			if w < 0 {
+				sign = -1
+			}
+		} else {
+			tangent := [3]float64{tangents[4*v], tangents[4*v+1], tangents[4*v+2]}
+			if dot(cross(vec3(normals, uint32(v)), tangent), bitangents[v]) < 0 {
+				sign = -1
+			}
+		}
+		if w != sign {
+			binary.LittleEndian.PutUint32(view[uint32(v)*stride+12:], math.Float32bits(float32(sign)))
+			changed++
+		}
+	}
+	return changed, nil
+}
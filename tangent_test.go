@@ -0,0 +1,57 @@
+package gltf
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func newTangentDoc(v float64, w []float64) *Document {
+	doc := &Document{Asset: Asset{Version: "2.0"}}
+	attrs, _ := doc.AddInterleaved([]InterleavedAttribute{
+		{Semantic: "POSITION", Type: Vec3, ComponentType: Float, Data: []float64{0, 0, 0, 1, 0, 0, 0, 1, 0}},
+		{Semantic: "NORMAL", Type: Vec3, ComponentType: Float, Data: []float64{0, 0, 1, 0, 0, 1, 0, 0, 1}},
+		{Semantic: "TANGENT", Type: Vec4, ComponentType: Float, Data: []float64{1, 0, 0, w[0], 1, 0, 0, w[1], 1, 0, 0, w[2]}},
+		{Semantic: "TEXCOORD_0", Type: Vec2, ComponentType: Float, Data: []float64{0, 0, 1, 0, 0, v}},
+	})
+	doc.Meshes = []Mesh{{Primitives: []Primitive{{Attributes: attrs}}}}
+	return doc
+}
+
+func TestDocument_RepairTangentSigns(t *testing.T) {
+	noNormals := newTangentDoc(1, []float64{1, 1, 1})
+	delete(noNormals.Meshes[0].Primitives[0].Attributes, "NORMAL")
+	degenerate := newTangentDoc(0, []float64{1, -1, 0.5})
+	tests := []struct {
+		name    string
+		doc     *Document
+		want    int
+		wantW   []float64
+		wantErr bool
+	}{
+		{"valid", newTangentDoc(1, []float64{1, 1, 1}), 0, []float64{1, 1, 1}, false},
+		{"broken", newTangentDoc(1, []float64{1, -1, 0.3}), 2, []float64{1, 1, 1}, false},
+		{"mirrored", newTangentDoc(-1, []float64{1, -1, 0}), 2, []float64{-1, -1, -1}, false},
+		{"degenerate", degenerate, 1, []float64{1, -1, 1}, false},
+		{"noNormals", noNormals, 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.doc.RepairTangentSigns(0, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.RepairTangentSigns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Document.RepairTangentSigns() = %v, want %v", got, tt.want)
+			}
+			tangents, _ := tt.doc.ReadAccessor(tt.doc.Meshes[0].Primitives[0].Attributes["TANGENT"])
+			w := []float64{tangents[3], tangents[7], tangents[11]}
+			if diff := deep.Equal(w, tt.wantW); diff != nil {
+				t.Errorf("Document.RepairTangentSigns() w = %v", diff)
+			}
+		})
+	}
+}
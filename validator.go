@@ -1,20 +1,860 @@
 package gltf
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
 	val "github.com/go-playground/validator"
 )
 
+// Named errors reported by Validate when the elements of a document are not consistent between them.
+var (
+	// ErrIndicesTarget is reported when the bufferView of an indices accessor does not target ELEMENT_ARRAY_BUFFER.
+	ErrIndicesTarget = errors.New("gltf: indices accessor bufferView target must be ELEMENT_ARRAY_BUFFER")
+	// ErrIndicesComponentType is reported when an indices accessor is not a scalar of UNSIGNED_BYTE, UNSIGNED_SHORT or UNSIGNED_INT.
+	ErrIndicesComponentType = errors.New("gltf: indices accessor must be a scalar of unsigned integers")
+	// ErrAttributeTarget is reported when the bufferView of a vertex attribute accessor does not target ARRAY_BUFFER.
+	ErrAttributeTarget = errors.New("gltf: vertex attribute accessor bufferView target must be ARRAY_BUFFER")
+	// ErrMissingTarget is reported by ValidateWarnings when the bufferView of an indices or vertex attribute accessor
+	// does not define its target, which the spec recommends so the data is uploaded to the right GPU buffer.
+	ErrMissingTarget = errors.New("gltf: indices or vertex attribute accessor bufferView does not define its target")
+	// ErrAttributeComponentType is reported when a vertex attribute accessor uses UNSIGNED_INT, which is only allowed for indices.
+	ErrAttributeComponentType = errors.New("gltf: vertex attribute accessor cannot use UNSIGNED_INT")
+	// ErrAccessorAlignment is reported when the offset of an accessor, or the stride of its bufferView, is not a multiple of the component size.
+	ErrAccessorAlignment = errors.New("gltf: accessor data is not aligned to its component size")
+	// ErrRotationNotUnit is reported by ValidateWarnings when a node rotation is not a unit quaternion,
+	// which renderers may interpret differently. NormalizeRotations fixes it.
+	ErrRotationNotUnit = errors.New("gltf: node rotation is not a unit quaternion")
+	// ErrNodeMultipleParents is reported when a node is the child of more than one node.
+	ErrNodeMultipleParents = errors.New("gltf: node has more than one parent")
+	// ErrSceneRootNotRoot is reported when a scene root node is the child of another node.
+	ErrSceneRootNotRoot = errors.New("gltf: scene root node is the child of another node")
+	// ErrTextureNPOT is reported by ValidateWarnings when a texture with a non-power-of-two image
+	// is sampled with mipmaps and repeat wrapping, which is valid glTF but WebGL 1 does not support.
+	ErrTextureNPOT = errors.New("gltf: non-power-of-two texture sampled with mipmaps and repeat wrapping")
+	// ErrChannelDuplicateTarget is reported when two channels of the same animation target the same node and path.
+	ErrChannelDuplicateTarget = errors.New("gltf: animation channels with the same target")
+	// ErrIndexOutOfRange is reported when a property references an element that does not exist.
+	ErrIndexOutOfRange = errors.New("gltf: index out of range")
+	// ErrTexCoordClamped is reported by ValidateWarnings when a primitive has texture coordinates out of the [0, 1] range
+	// used to sample a texture whose sampler clamps them to the edge, which stretches the border texels.
+	ErrTexCoordClamped = errors.New("gltf: texture coordinates out of the [0, 1] range sampled with clamp to edge")
+	// ErrColorOutOfRange is reported by the decoders with strict colors when a color component is not in the [0, 1] range.
+	ErrColorOutOfRange = errors.New("gltf: color component out of the [0, 1] range")
+	// ErrSamplerOutputCount is reported when the output accessor of an animation sampler does not have
+	// one element per keyframe, or three with CUBICSPLINE interpolation, multiplied by the number of morph targets
+	// when it animates weights.
+	ErrSamplerOutputCount = errors.New("gltf: animation sampler output count does not match its input count")
+	// ErrTangentHandedness is reported when the w component of a vertex tangent, which holds the handedness
+	// of the tangent space, is not 1 or -1.
+	ErrTangentHandedness = errors.New("gltf: tangent w component is not 1 or -1")
+	// ErrMissingPosition is reported by ValidateWarnings when a primitive without extensions
+	// does not have a POSITION attribute, so clients skip its rendering.
+	ErrMissingPosition = errors.New("gltf: primitive does not have a POSITION attribute")
+	// ErrAlphaCutoffUnused is reported by ValidateWarnings when a material sets an alphaCutoff
+	// but its alphaMode is not MASK, so the cutoff is ignored.
+	ErrAlphaCutoffUnused = errors.New("gltf: alphaCutoff is only used with MASK alpha mode")
+	// ErrChannelOutputType is reported when the output accessor of the sampler of an animation channel does not have
+	// the type required by the target path: VEC3 for translation and scale, VEC4 for rotation and SCALAR for weights.
+	ErrChannelOutputType = errors.New("gltf: animation sampler output type does not match the channel target path")
+	// ErrEmbeddedBufferLength is reported when the data encoded in the URI of an embedded buffer
+	// does not have byteLength bytes.
+	ErrEmbeddedBufferLength = errors.New("gltf: embedded buffer data length does not match its byteLength")
+	// ErrBufferViewOverlap is reported by ValidateWarnings when a bufferView shares bytes of its buffer
+	// with a previous one, unless both have the same byteStride and target, as interleaved vertex data may do.
+	ErrBufferViewOverlap = errors.New("gltf: bufferView overlaps another bufferView")
+	// ErrSparseIndicesOrder is reported when the indices of a sparse accessor are not strictly increasing.
+	ErrSparseIndicesOrder = errors.New("gltf: sparse accessor indices are not strictly increasing")
+	// ErrSparseIndexOutOfRange is reported when an index of a sparse accessor is not less than the accessor count.
+	ErrSparseIndexOutOfRange = errors.New("gltf: sparse accessor index is not less than the accessor count")
+	// ErrSparseDataLength is reported when the bufferView of the sparse indices or values
+	// is too short to hold the sparse count elements.
+	ErrSparseDataLength = errors.New("gltf: sparse accessor data does not have count elements")
+	// ErrDuplicateName is reported by ValidateNames when an element has the same name as a previous one of its kind.
+	ErrDuplicateName = errors.New("gltf: duplicate name")
+	// ErrNodeZeroScale is reported by ValidateWarnings when a component of a node scale is zero,
+	// which collapses the geometry of the node and makes its transform not invertible.
+	ErrNodeZeroScale = errors.New("gltf: node scale has a zero component")
+	// ErrNodeNegativeScale is reported by ValidateWarnings when a component of a node scale is negative,
+	// which mirrors the node and flips the winding order of its triangles.
+	// Mirroring may be intended, so callers can ignore it by checking the Err of each ValidationError.
+	ErrNodeNegativeScale = errors.New("gltf: node scale has a negative component")
+)
+
+// A SchemaError describes a property that does not follow the glTF schema.
+type SchemaError struct {
+	Tag   string // Validation rule that failed, i.e. gte.
+	Param string // Parameter of the rule, if any.
+}
+
+func (e *SchemaError) Error() string {
+	if e.Param == "" {
+		return fmt.Sprintf("gltf: property does not satisfy the '%s' rule", e.Tag)
+	}
+	return fmt.Sprintf("gltf: property does not satisfy the '%s=%s' rule", e.Tag, e.Param)
+}
+
+// A ValidationError describes a property that does not follow the schema
+// or that is not coherent with the rest of the document.
+type ValidationError struct {
+	Pointer string // JSON pointer to the property, i.e. /meshes/0/primitives/0/indices.
+	Err     error  // A SchemaError or one of the named errors.
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%v at %s", e.Err, e.Pointer)
+}
+
+// ValidationErrors is an array of ValidationError's for use in custom error messages post validation.
+type ValidationErrors []*ValidationError
+
+func (v ValidationErrors) Error() string {
+	s := make([]string, len(v))
+	for i, e := range v {
+		s[i] = e.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+func (v *ValidationErrors) report(err error, format string, a ...interface{}) {
+	*v = append(*v, &ValidationError{Pointer: fmt.Sprintf(format, a...), Err: err})
+}
+
+// colorComponents maps the RGB and RGBA fields to their position in the JSON array.
+var colorComponents = map[string]string{"R": "0", "G": "1", "B": "2", "A": "3"}
+
+// jsonFieldName returns the name of the struct field in the JSON document.
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		if i, ok := colorComponents[field.Name]; ok {
+			return i
+		}
+		return field.Name
+	}
+	return name
+}
+
+// namespaceToPointer converts a validator namespace built with JSON field names,
+// i.e. Document.meshes[0].primitives[0].attributes[POSITION], into a JSON pointer.
+func namespaceToPointer(namespace string) string {
+	var b strings.Builder
+	// The first element is the name of the validated struct.
+	if i := strings.IndexAny(namespace, ".["); i >= 0 {
+		namespace = namespace[i:]
+	} else {
+		return ""
+	}
+	for len(namespace) > 0 {
+		var token string
+		switch namespace[0] {
+		case '[':
+			end := strings.IndexByte(namespace, ']')
+			if end < 0 {
+				end = len(namespace)
+			}
+			token = namespace[1:end]
+			if end < len(namespace) {
+				end++
+			}
+			namespace = namespace[end:]
+		default:
+			namespace = namespace[1:]
+			end := strings.IndexAny(namespace, ".[")
+			if end < 0 {
+				end = len(namespace)
+			}
+			token = namespace[:end]
+			namespace = namespace[end:]
+		}
+		b.WriteByte('/')
+		b.WriteString(escapePointer(token))
+	}
+	return b.String()
+}
+
+// escapePointer escapes a JSON pointer reference token as defined in RFC 6901.
+func escapePointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
 // Validate ensures that a document follows the glTF 2.0 specs.
+// The properties are first validated against the schema and,
+// only if they are valid, the document coherence is checked.
+// Both kinds of errors are reported as ValidationErrors,
+// pointing to the offending property with a JSON pointer.
 func (d *Document) Validate() error {
 	validate := val.New()
+	validate.RegisterTagNameFunc(jsonFieldName)
 	validate.RegisterStructValidation(imageValidation, Image{})
-	return validate.Struct(d)
+	var errs ValidationErrors
+	if err := validate.Struct(d); err != nil {
+		fieldErrs, ok := err.(val.ValidationErrors)
+		if !ok {
+			return err
+		}
+		for _, fe := range fieldErrs {
+			errs = append(errs, &ValidationError{
+				Pointer: namespaceToPointer(fe.Namespace()),
+				Err:     &SchemaError{Tag: fe.Tag(), Param: fe.Param()},
+			})
+		}
+		return errs
+	}
+	d.validateReferences(&errs)
+	d.validateTargets(&errs)
+	d.validateAlignment(&errs)
+	d.validateHierarchy(&errs)
+	d.validateAnimations(&errs)
+	d.validateTangents(&errs)
+	d.validateSparse(&errs)
+	d.validateEmbeddedBuffers(&errs)
+	d.validateExtras(&errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+var (
+	extrasMu         sync.RWMutex
+	extrasValidators = make(map[reflect.Type]func(extras interface{}) error)
+)
+
+// RegisterExtrasValidator registers a function that checks the extras of every property
+// of the same type as obj, which is a value such as Node{} or Material{}, when the document is validated.
+// fn receives the decoded extras, which are nil if the property does not have them,
+// and the error it returns is reported by Validate at the extras of the property.
+// A nil fn removes the validator. This is intended to be called from an init function,
+// but it is safe to call concurrently with validation.
+func RegisterExtrasValidator(obj interface{}, fn func(extras interface{}) error) {
+	t := reflect.TypeOf(obj)
+	extrasMu.Lock()
+	if fn == nil {
+		delete(extrasValidators, t)
+	} else {
+		extrasValidators[t] = fn
+	}
+	extrasMu.Unlock()
+}
+
+// extrasValidator returns the extras validator registered for the type t, if any.
+func extrasValidator(t reflect.Type) (func(extras interface{}) error, bool) {
+	extrasMu.RLock()
+	fn, ok := extrasValidators[t]
+	extrasMu.RUnlock()
+	return fn, ok
 }
 
+// validateExtras runs the registered extras validators on the properties of the document,
+// including the ones stored in extensions.
+func (d *Document) validateExtras(errs *ValidationErrors) {
+	extrasMu.RLock()
+	n := len(extrasValidators)
+	extrasMu.RUnlock()
+	if n > 0 {
+		walkValidateExtras(reflect.ValueOf(d).Elem(), "", errs)
+	}
+}
+
+func walkValidateExtras(v reflect.Value, pointer string, errs *ValidationErrors) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			walkValidateExtras(v.Elem(), pointer, errs)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		if fn, ok := extrasValidator(t); ok {
+			var extras interface{}
+			if f := v.FieldByName("Extras"); f.IsValid() && f.CanInterface() {
+				extras = f.Interface()
+			}
+			if err := fn(extras); err != nil {
+				errs.report(err, "%s/extras", pointer)
+			}
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if name := jsonFieldName(field); name != "" && name != "extras" && field.PkgPath == "" {
+				walkValidateExtras(v.Field(i), pointer+"/"+escapePointer(name), errs)
+			}
+		}
+	case reflect.Slice:
+		// Slices of numbers, such as the buffer data, can not hold properties.
+		switch v.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		default:
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			walkValidateExtras(v.Index(i), fmt.Sprintf("%s/%d", pointer, i), errs)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			walkValidateExtras(v.MapIndex(key), pointer+"/"+escapePointer(key.String()), errs)
+		}
+	}
+}
+
+// ValidateNames checks that the named elements of each kind, such as nodes or materials, have different names,
+// as otherwise looking them up by name is ambiguous. Unnamed elements are ignored.
+// It is not part of Validate, as the specification does not require names to be unique.
+// The returned error is a ValidationErrors that reports every element whose name was already used.
+func (d *Document) ValidateNames() error {
+	var errs ValidationErrors
+	v := reflect.ValueOf(d).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.Slice || f.Type().Elem().Kind() != reflect.Struct {
+			continue
+		}
+		if _, ok := f.Type().Elem().FieldByName("Name"); !ok {
+			continue
+		}
+		seen := make(map[string]bool)
+		for j := 0; j < f.Len(); j++ {
+			name := f.Index(j).FieldByName("Name").String()
+			if name == "" {
+				continue
+			}
+			if seen[name] {
+				errs.report(ErrDuplicateName, "/%s/%d/name", jsonFieldName(v.Type().Field(i)), j)
+			}
+			seen[name] = true
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateWarnings checks the properties that do not make the document invalid
+// but have no effect or are likely to be rendered incorrectly, which usually means that they were set by mistake:
+//   - materials with an alphaCutoff whose alphaMode is not MASK.
+//   - bufferViews that overlap a previous one, except when both have the same byteStride and target.
+//   - node rotations that are not unit quaternions.
+//   - non-power-of-two textures sampled with mipmaps and repeat wrapping, which WebGL 1 does not support.
+//     Only the embedded images whose format has a registered image decoder are checked.
+//   - texture coordinates out of the [0, 1] range used with samplers that clamp them to the edge.
+//   - nodes with a zero or negative scale component. The empty scale stands for the default one and is not reported.
+//   - primitives without POSITION attribute nor extensions that may provide it, which clients do not render.
+//   - indices and vertex attribute accessors whose bufferView does not define its target.
+//
+// As the decoder sets the omitted alphaCutoff to its default value of 0.5, that value is never reported.
+// It is not part of Validate. The returned error is a ValidationErrors that reports every offending property.
+func (d *Document) ValidateWarnings() error {
+	var errs ValidationErrors
+	for i, mat := range d.Materials {
+		if mat.AlphaMode != Mask && mat.AlphaCutoff != nil && *mat.AlphaCutoff != 0.5 {
+			errs.report(ErrAlphaCutoffUnused, "/materials/%d/alphaCutoff", i)
+		}
+	}
+	d.validateOverlaps(&errs)
+	d.validateRotations(&errs)
+	d.validateTextures(&errs)
+	d.validateTexCoords(&errs)
+	for i, node := range d.Nodes {
+		if node.Scale == emptyScale {
+			continue
+		}
+		for _, s := range node.Scale {
+			if s == 0 {
+				errs.report(ErrNodeZeroScale, "/nodes/%d/scale", i)
+				break
+			}
+		}
+		for _, s := range node.Scale {
+			if s < 0 {
+				errs.report(ErrNodeNegativeScale, "/nodes/%d/scale", i)
+				break
+			}
+		}
+	}
+	for i, mesh := range d.Meshes {
+		for j, prim := range mesh.Primitives {
+			// Extensions, such as KHR_draco_mesh_compression, may provide the positions by other means.
+			if _, ok := prim.Attributes["POSITION"]; !ok && len(prim.Extensions) == 0 {
+				errs.report(ErrMissingPosition, "/meshes/%d/primitives/%d/attributes", i, j)
+			}
+		}
+	}
+	d.validateMissingTargets(&errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateOverlaps reports each bufferView that overlaps a bufferView with a lower index in the same buffer.
+// Views with the same non-zero byteStride and target are considered interleaved on purpose.
+func (d *Document) validateOverlaps(errs *ValidationErrors) {
+	views := make([]int, len(d.BufferViews))
+	for i := range views {
+		views[i] = i
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		a, b := d.BufferViews[views[i]], d.BufferViews[views[j]]
+		if a.Buffer != b.Buffer {
+			return a.Buffer < b.Buffer
+		}
+		return a.ByteOffset < b.ByteOffset
+	})
+	overlapped := make([]bool, len(d.BufferViews))
+	for i, vi := range views {
+		a := d.BufferViews[vi]
+		end := uint64(a.ByteOffset) + uint64(a.ByteLength)
+		for _, vj := range views[i+1:] {
+			b := d.BufferViews[vj]
+			if b.Buffer != a.Buffer || uint64(b.ByteOffset) >= end {
+				break
+			}
+			if b.ByteLength == 0 || a.ByteLength == 0 || (a.ByteStride != 0 && a.ByteStride == b.ByteStride && a.Target == b.Target) {
+				continue
+			}
+			if vi > vj {
+				overlapped[vi] = true
+			} else {
+				overlapped[vj] = true
+			}
+		}
+	}
+	for i, ok := range overlapped {
+		if ok {
+			errs.report(ErrBufferViewOverlap, "/bufferViews/%d", i)
+		}
+	}
+}
+
+// imageValidation requires the mimeType of the images stored in a bufferView, which is needed to decode them.
 func imageValidation(sl val.StructLevel) {
 	image := sl.Current().Interface().(Image)
 
 	if image.URI == "" && image.MimeType == "" {
-		sl.ReportError(image.MimeType, "MimeType", "mimeType", "", "")
+		sl.ReportError(image.MimeType, "mimeType", "MimeType", "required", "")
+	}
+}
+
+// validateReferences checks that the indices used to reference other elements are in range.
+func (d *Document) validateReferences(errs *ValidationErrors) {
+	for i, tex := range d.Textures {
+		if tex.Sampler != nil && int(*tex.Sampler) >= len(d.Samplers) {
+			errs.report(ErrIndexOutOfRange, "/textures/%d/sampler", i)
+		}
+		if tex.Source != nil && int(*tex.Source) >= len(d.Images) {
+			errs.report(ErrIndexOutOfRange, "/textures/%d/source", i)
+		}
+	}
+}
+
+// validateTargets checks that the accessors used by the primitives are bound to the right GPU buffer.
+// An undefined target is allowed, as the spec does not require it, and reported by ValidateWarnings instead.
+func (d *Document) validateTargets(errs *ValidationErrors) {
+	for i, mesh := range d.Meshes {
+		for j, prim := range mesh.Primitives {
+			if prim.Indices != nil && int(*prim.Indices) < len(d.Accessors) {
+				acc := d.Accessors[*prim.Indices]
+				if acc.Type != Scalar || (acc.ComponentType != UnsignedByte && acc.ComponentType != UnsignedShort && acc.ComponentType != UnsignedInt) {
+					errs.report(ErrIndicesComponentType, "/meshes/%d/primitives/%d/indices", i, j)
+				}
+				if target, ok := d.accessorTarget(acc); ok && target != ElementArrayBuffer {
+					errs.report(ErrIndicesTarget, "/meshes/%d/primitives/%d/indices", i, j)
+				}
+			}
+			for _, name := range sortedAttributes(prim.Attributes) {
+				index := prim.Attributes[name]
+				if int(index) >= len(d.Accessors) {
+					continue
+				}
+				acc := d.Accessors[index]
+				if acc.ComponentType == UnsignedInt {
+					errs.report(ErrAttributeComponentType, "/meshes/%d/primitives/%d/attributes/%s", i, j, escapePointer(name))
+				}
+				if target, ok := d.accessorTarget(acc); ok && target != ArrayBuffer {
+					errs.report(ErrAttributeTarget, "/meshes/%d/primitives/%d/attributes/%s", i, j, escapePointer(name))
+				}
+			}
+		}
+	}
+}
+
+// validateAlignment checks that the accessor components are aligned to their size inside the buffer.
+func (d *Document) validateAlignment(errs *ValidationErrors) {
+	for i, acc := range d.Accessors {
+		if acc.BufferView == nil || int(*acc.BufferView) >= len(d.BufferViews) {
+			continue
+		}
+		bv := d.BufferViews[*acc.BufferView]
+		size := acc.ComponentType.ByteSize()
+		if (uint64(bv.ByteOffset)+uint64(acc.ByteOffset))%uint64(size) != 0 || bv.ByteStride%size != 0 {
+			errs.report(ErrAccessorAlignment, "/accessors/%d/byteOffset", i)
+		}
+	}
+}
+
+// unitTolerance is the maximum deviation from 1 allowed for unit values, such as the length of unit quaternions.
+const unitTolerance = 0.001
+
+// validateRotations checks that the node rotations are unit quaternions.
+// Small deviations produced by float precision are tolerated.
+func (d *Document) validateRotations(errs *ValidationErrors) {
+	for i, node := range d.Nodes {
+		if node.Rotation == emptyRotation {
+			continue
+		}
+		if math.Abs(quaternionLength(node.Rotation)-1) > unitTolerance {
+			errs.report(ErrRotationNotUnit, "/nodes/%d/rotation", i)
+		}
+	}
+}
+
+// validateTangents checks that the handedness of the vertex tangents is either 1 or -1.
+// Tangents that cannot be read are not checked.
+func (d *Document) validateTangents(errs *ValidationErrors) {
+	for i, mesh := range d.Meshes {
+		for j, prim := range mesh.Primitives {
+			index, ok := prim.Attributes["TANGENT"]
+			if !ok || int(index) >= len(d.Accessors) || d.Accessors[index].Type != Vec4 {
+				continue
+			}
+			tangents, err := d.ReadAccessor(index)
+			if err != nil {
+				continue
+			}
+			for k := 3; k < len(tangents); k += 4 {
+				if math.Abs(math.Abs(tangents[k])-1) > unitTolerance {
+					errs.report(ErrTangentHandedness, "/meshes/%d/primitives/%d/attributes/TANGENT", i, j)
+					break
+				}
+			}
+		}
+	}
+}
+
+// validateEmbeddedBuffers checks that the data URI of each embedded buffer encodes byteLength bytes.
+func (d *Document) validateEmbeddedBuffers(errs *ValidationErrors) {
+	for i := range d.Buffers {
+		buffer := &d.Buffers[i]
+		if !buffer.IsEmbeddedResource() {
+			continue
+		}
+		if n, ok := buffer.embeddedLength(); !ok || n != int(buffer.ByteLength) {
+			errs.report(ErrEmbeddedBufferLength, "/buffers/%d/byteLength", i)
+		}
+	}
+}
+
+// validateSparse checks that the sparse indices and values have count elements,
+// and that the indices are strictly increasing and point to elements of the accessor.
+// Sparse data whose buffer is not loaded is not checked.
+func (d *Document) validateSparse(errs *ValidationErrors) {
+	for i := range d.Accessors {
+		acc := &d.Accessors[i]
+		if acc.Sparse == nil {
+			continue
+		}
+		indices, values := sparseAccessors(acc)
+		if !d.validateSparseData(errs, &values, "/accessors/%d/sparse/values", i) {
+			continue
+		}
+		if !d.validateSparseData(errs, &indices, "/accessors/%d/sparse/indices", i) {
+			continue
+		}
+		positions, err := d.readAccessor(&indices)
+		if err != nil {
+			continue
+		}
+		for j, pos := range positions {
+			if pos >= float64(acc.Count) {
+				errs.report(ErrSparseIndexOutOfRange, "/accessors/%d/sparse/indices", i)
+				break
+			}
+			if j > 0 && pos <= positions[j-1] {
+				errs.report(ErrSparseIndicesOrder, "/accessors/%d/sparse/indices", i)
+				break
+			}
+		}
+	}
+}
+
+// validateSparseData reports the sparse indices or values that reference a missing bufferView
+// or do not fit in it, and returns whether they can be read.
+func (d *Document) validateSparseData(errs *ValidationErrors, acc *Accessor, format string, i int) bool {
+	bv := *acc.BufferView
+	if int(bv) >= len(d.BufferViews) {
+		errs.report(ErrIndexOutOfRange, format+"/bufferView", i)
+		return false
+	}
+	size := uint64(elementSize(acc.ComponentType, acc.Type))
+	if uint64(acc.ByteOffset)+size*uint64(acc.Count) > uint64(d.BufferViews[bv].ByteLength) {
+		errs.report(ErrSparseDataLength, format, i)
+		return false
+	}
+	_, err := d.bufferViewData(bv)
+	return err == nil
+}
+
+// validateHierarchy checks that the nodes form a set of disjoint trees whose roots are the scene nodes.
+func (d *Document) validateHierarchy(errs *ValidationErrors) {
+	parents := make([]int, len(d.Nodes))
+	for i := range parents {
+		parents[i] = -1
+	}
+	for i, node := range d.Nodes {
+		for j, child := range node.Children {
+			if int(child) >= len(d.Nodes) {
+				continue
+			}
+			if parents[child] != -1 {
+				errs.report(ErrNodeMultipleParents, "/nodes/%d/children/%d", i, j)
+				continue
+			}
+			parents[child] = i
+		}
+	}
+	for i, scene := range d.Scenes {
+		for j, root := range scene.Nodes {
+			if int(root) < len(d.Nodes) && parents[root] != -1 {
+				errs.report(ErrSceneRootNotRoot, "/scenes/%d/nodes/%d", i, j)
+			}
+		}
+	}
+}
+
+// Images whose dimensions can not be read, such as the external ones or those whose format has no registered decoder, are not checked.
+// Images whose dimensions can not be read, such as the external ones, are not checked.
+func (d *Document) validateTextures(errs *ValidationErrors) {
+	for i, tex := range d.Textures {
+		if tex.Sampler == nil || tex.Source == nil || int(*tex.Sampler) >= len(d.Samplers) {
+			continue
+		}
+		sampler := d.Samplers[*tex.Sampler]
+		switch sampler.MinFilter {
+		case MinNearestMipMapNearest, MinNearestMipMapLinear, MinLinearMipMapNearest, MinLinearMipMapLinear:
+		default:
+			continue
+		}
+		if sampler.WrapS == ClampToEdge && sampler.WrapT == ClampToEdge {
+			continue
+		}
+		width, height, err := d.ImageSize(*tex.Source)
+		if err == nil && (!isPowerOfTwo(width) || !isPowerOfTwo(height)) {
+			errs.report(ErrTextureNPOT, "/textures/%d", i)
+		}
+	}
+}
+
+// validateAnimations checks that the channels of each animation target different node properties.
+func (d *Document) validateAnimations(errs *ValidationErrors) {
+	for i, anim := range d.Animations {
+		targets := anim.ChannelsByTarget()
+		for j, channel := range anim.Channels {
+			if channel.Target.Node == nil || channel.Target.Path == Pointer {
+				continue
+			}
+			if first := targets[AnimationTarget{Node: *channel.Target.Node, Path: channel.Target.Path}]; first != uint32(j) {
+				errs.report(ErrChannelDuplicateTarget, "/animations/%d/channels/%d/target", i, j)
+			}
+			if output, ok := d.channelOutput(&anim, &channel); ok && output.Type != channelOutputType(channel.Target.Path) {
+				errs.report(ErrChannelOutputType, "/animations/%d/channels/%d/target/path", i, j)
+			}
+		}
+		for j, sampler := range anim.Samplers {
+			if sampler.Input == nil || sampler.Output == nil || int(*sampler.Input) >= len(d.Accessors) || int(*sampler.Output) >= len(d.Accessors) {
+				continue
+			}
+			elements, ok := d.samplerElements(&anim, uint32(j))
+			if !ok {
+				continue
+			}
+			if sampler.Interpolation == CubicSpline {
+				elements *= 3
+			}
+			if d.Accessors[*sampler.Output].Count != d.Accessors[*sampler.Input].Count*elements {
+				errs.report(ErrSamplerOutputCount, "/animations/%d/samplers/%d/output", i, j)
+			}
+		}
+	}
+}
+
+// channelOutput returns the output accessor of the sampler used by an animation channel.
+// The boolean is false if the sampler or the accessor do not exist.
+func (d *Document) channelOutput(anim *Animation, channel *Channel) (*Accessor, bool) {
+	if channel.Sampler == nil || int(*channel.Sampler) >= len(anim.Samplers) {
+		return nil, false
+	}
+	output := anim.Samplers[*channel.Sampler].Output
+	if output == nil || int(*output) >= len(d.Accessors) {
+		return nil, false
+	}
+	return &d.Accessors[*output], true
+}
+
+// channelOutputType returns the accessor type of the output values that animate a node property.
+func channelOutputType(path TRSProperty) AccessorType {
+	switch path {
+	case Rotation:
+		return Vec4
+	case Weights:
+		return Scalar
+	default:
+		return Vec3
+	}
+}
+
+// samplerElements returns the number of output elements of each keyframe of an animation sampler,
+// which is the number of morph targets when a channel uses it to animate weights.
+// The boolean is false if the number of morph targets cannot be determined.
+func (d *Document) samplerElements(anim *Animation, sampler uint32) (uint32, bool) {
+	for _, channel := range anim.Channels {
+		if channel.Sampler == nil || *channel.Sampler != sampler || channel.Target.Path != Weights {
+			continue
+		}
+		node := channel.Target.Node
+		if node == nil || int(*node) >= len(d.Nodes) {
+			return 0, false
+		}
+		mesh := d.Nodes[*node].Mesh
+		if mesh == nil || int(*mesh) >= len(d.Meshes) || len(d.Meshes[*mesh].Primitives) == 0 {
+			return 0, false
+		}
+		targets := len(d.Meshes[*mesh].Primitives[0].Targets)
+		return uint32(targets), targets > 0
+	}
+	return 1, true
+}
+
+// validateColors checks that the components of the material color factors are in the [0, 1] range.
+// It is not part of Validate, as the schema validation already covers these properties.
+func (d *Document) validateColors(errs *ValidationErrors) {
+	for i := range d.Materials {
+		validateMaterialColors(&d.Materials[i], i, errs)
+	}
+}
+
+// validateMaterialColors checks the color factors of the material at the given index, as validateColors does.
+func validateMaterialColors(mat *Material, index int, errs *ValidationErrors) {
+	check := func(components []float64, format string, a ...interface{}) {
+		for i, c := range components {
+			if c < 0 || c > 1 {
+				errs.report(ErrColorOutOfRange, format+"/%d", append(a, i)...)
+			}
+		}
+	}
+	if pbr := mat.PBRMetallicRoughness; pbr != nil && pbr.BaseColorFactor != nil {
+		c := pbr.BaseColorFactor
+		check([]float64{c.R, c.G, c.B, c.A}, "/materials/%d/pbrMetallicRoughness/baseColorFactor", index)
+	}
+	check(mat.EmissiveFactor[:], "/materials/%d/emissiveFactor", index)
+}
+
+// validateTexCoords checks that the texture coordinates used with clamped samplers are in the [0, 1] range.
+// Primitives whose data can not be read are not checked.
+func (d *Document) validateTexCoords(errs *ValidationErrors) {
+	for i, mesh := range d.Meshes {
+		for j, prim := range mesh.Primitives {
+			if prim.Material == nil || int(*prim.Material) >= len(d.Materials) {
+				continue
+			}
+			reported := make(map[uint32]bool)
+			for _, ref := range materialTextures(&d.Materials[*prim.Material]) {
+				if reported[ref.texCoord] || int(ref.index) >= len(d.Textures) {
+					continue
+				}
+				tex := d.Textures[ref.index]
+				if tex.Sampler == nil || int(*tex.Sampler) >= len(d.Samplers) {
+					continue
+				}
+				sampler := d.Samplers[*tex.Sampler]
+				clampS, clampT := sampler.WrapS == ClampToEdge, sampler.WrapT == ClampToEdge
+				if !clampS && !clampT {
+					continue
+				}
+				min, max, err := d.TexCoordBounds(uint32(i), uint32(j), ref.texCoord)
+				if err != nil {
+					continue
+				}
+				if (clampS && (min[0] < 0 || max[0] > 1)) || (clampT && (min[1] < 0 || max[1] > 1)) {
+					errs.report(ErrTexCoordClamped, "/meshes/%d/primitives/%d/attributes/TEXCOORD_%d", i, j, ref.texCoord)
+					reported[ref.texCoord] = true
+				}
+			}
+		}
+	}
+}
+
+// textureRef is a reference from a material to a texture and the texture coordinates set used to sample it.
+type textureRef struct {
+	index, texCoord uint32
+}
+
+// materialTextures returns the textures referenced by the material.
+func materialTextures(mat *Material) []textureRef {
+	var refs []textureRef
+	mat.WalkTextures(func(index, texCoord *uint32) {
+		refs = append(refs, textureRef{*index, *texCoord})
+	})
+	return refs
+}
+
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+// validateMissingTargets reports the indices and vertex attribute accessors of the primitives
+// whose bufferView does not define its target.
+func (d *Document) validateMissingTargets(errs *ValidationErrors) {
+	missing := func(index uint32) bool {
+		if int(index) >= len(d.Accessors) {
+			return false
+		}
+		acc := d.Accessors[index]
+		return acc.BufferView != nil && int(*acc.BufferView) < len(d.BufferViews) && d.BufferViews[*acc.BufferView].Target == None
+	}
+	for i, mesh := range d.Meshes {
+		for j, prim := range mesh.Primitives {
+			if prim.Indices != nil && missing(*prim.Indices) {
+				errs.report(ErrMissingTarget, "/meshes/%d/primitives/%d/indices", i, j)
+			}
+			for _, name := range sortedAttributes(prim.Attributes) {
+				if missing(prim.Attributes[name]) {
+					errs.report(ErrMissingTarget, "/meshes/%d/primitives/%d/attributes/%s", i, j, name)
+				}
+			}
+		}
+	}
+}
+
+// accessorTarget returns the target of the bufferView referenced by the accessor.
+// The boolean is false if the accessor has no valid bufferView or the target is undefined.
+func (d *Document) accessorTarget(acc Accessor) (Target, bool) {
+	if acc.BufferView == nil || int(*acc.BufferView) >= len(d.BufferViews) {
+		return None, false
+	}
+	target := d.BufferViews[*acc.BufferView].Target
+	return target, target != None
+}
+
+// sortedAttributes returns the attribute semantics in lexicographical order so reports are deterministic.
+func sortedAttributes(attrs Attribute) []string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
 }
@@ -1,9 +1,12 @@
 package gltf
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
 	"testing"
-
-	val "github.com/go-playground/validator"
 )
 
 func TestValidateDocument(t *testing.T) {
@@ -12,96 +15,96 @@ func TestValidateDocument(t *testing.T) {
 		doc     *Document
 		wantErr bool
 	}{
-		{"Document.Asset.Version", new(Document), true},
-		{"Document.Accessors[0].ComponentType", &Document{Asset: Asset{Version: "1.0"},
+		{"/asset/version", new(Document), true},
+		{"/accessors/0/componentType", &Document{Asset: Asset{Version: "1.0"},
 			Accessors: []Accessor{{ComponentType: 10, Count: 1}}}, true},
-		{"Document.Accessors[0].Count", &Document{Asset: Asset{Version: "1.0"},
+		{"/accessors/0/count", &Document{Asset: Asset{Version: "1.0"},
 			Accessors: []Accessor{{ComponentType: Byte, Count: 0}}}, true},
-		{"Document.Accessors[0].Type", &Document{Asset: Asset{Version: "1.0"},
+		{"/accessors/0/type", &Document{Asset: Asset{Version: "1.0"},
 			Accessors: []Accessor{{ComponentType: Byte, Count: 1, Type: 10}}}, true},
-		{"Document.Accessors[0].Max", &Document{Asset: Asset{Version: "1.0"},
+		{"/accessors/0/max", &Document{Asset: Asset{Version: "1.0"},
 			Accessors: []Accessor{{ComponentType: Byte, Count: 1, Max: make([]float64, 17)}}}, true},
-		{"Document.Accessors[0].Min", &Document{Asset: Asset{Version: "1.0"},
+		{"/accessors/0/min", &Document{Asset: Asset{Version: "1.0"},
 			Accessors: []Accessor{{ComponentType: Byte, Count: 1, Min: make([]float64, 17)}}}, true},
-		{"Document.Accessors[0].Sparse.Count", &Document{Asset: Asset{Version: "1.0"},
+		{"/accessors/0/sparse/count", &Document{Asset: Asset{Version: "1.0"},
 			Accessors: []Accessor{{ComponentType: Byte, Count: 1,
 				Sparse: &Sparse{Count: 0}}}}, true},
-		{"Document.Accessors[0].Sparse.Indices.ComponentType", &Document{Asset: Asset{Version: "1.0"},
+		{"/accessors/0/sparse/indices/componentType", &Document{Asset: Asset{Version: "1.0"},
 			Accessors: []Accessor{{ComponentType: Byte, Count: 1,
 				Sparse: &Sparse{Count: 1, Indices: SparseIndices{ComponentType: 1}}}}}, true},
-		{"Document.Buffers[0].URI", &Document{Asset: Asset{Version: "1.0"},
+		{"/buffers/0/uri", &Document{Asset: Asset{Version: "1.0"},
 			Buffers: []Buffer{{ByteLength: 1, URI: "a.bin"}}}, false},
-		{"Document.Buffers[0].ByteLength", &Document{Asset: Asset{Version: "1.0"},
+		{"/buffers/0/byteLength", &Document{Asset: Asset{Version: "1.0"},
 			Buffers: []Buffer{{ByteLength: 0, URI: "http://web.com"}}}, true},
-		{"Document.BufferViews[0].ByteLength", &Document{Asset: Asset{Version: "1.0"},
+		{"/bufferViews/0/byteLength", &Document{Asset: Asset{Version: "1.0"},
 			BufferViews: []BufferView{{ByteLength: 0}}}, true},
-		{"Document.BufferViews[0].ByteStride", &Document{Asset: Asset{Version: "1.0"},
+		{"/bufferViews/0/byteStride", &Document{Asset: Asset{Version: "1.0"},
 			BufferViews: []BufferView{{ByteLength: 1, ByteStride: 3}}}, true},
-		{"Document.BufferViews[0].ByteStride", &Document{Asset: Asset{Version: "1.0"},
+		{"/bufferViews/0/byteStride", &Document{Asset: Asset{Version: "1.0"},
 			BufferViews: []BufferView{{ByteLength: 1, ByteStride: 253}}}, true},
-		{"Document.BufferViews[0].Target", &Document{Asset: Asset{Version: "1.0"},
+		{"/bufferViews/0/target", &Document{Asset: Asset{Version: "1.0"},
 			BufferViews: []BufferView{{ByteLength: 1, ByteStride: 4, Target: 2}}}, true},
-		{"Document.Scenes[0].Nodes", &Document{Asset: Asset{Version: "1.0"},
+		{"/scenes/0/nodes", &Document{Asset: Asset{Version: "1.0"},
 			Scenes: []Scene{{Nodes: []uint32{1, 1}}}}, true},
-		{"Document.Nodes[0].Children", &Document{Asset: Asset{Version: "1.0"},
+		{"/nodes/0/children", &Document{Asset: Asset{Version: "1.0"},
 			Nodes: []Node{{Children: []uint32{1, 1}}}}, true},
-		{"Document.Nodes[0].Rotation[0]", &Document{Asset: Asset{Version: "1.0"},
+		{"/nodes/0/rotation/0", &Document{Asset: Asset{Version: "1.0"},
 			Nodes: []Node{{Rotation: [4]float64{2, 1, 1, 1}}}}, true},
-		{"Document.Nodes[0].Rotation[1]", &Document{Asset: Asset{Version: "1.0"},
+		{"/nodes/0/rotation/1", &Document{Asset: Asset{Version: "1.0"},
 			Nodes: []Node{{Rotation: [4]float64{1, -2, 1, 1}}}}, true},
-		{"Document.Skins[0].Joints", &Document{Asset: Asset{Version: "1.0"},
+		{"/skins/0/joints", &Document{Asset: Asset{Version: "1.0"},
 			Skins: []Skin{{Joints: []uint32{1, 1}}}}, true},
-		{"Document.Cameras[0].Orthographic.Znear", &Document{Asset: Asset{Version: "1.0"},
+		{"/cameras/0/orthographic/znear", &Document{Asset: Asset{Version: "1.0"},
 			Cameras: []Camera{{Orthographic: &Orthographic{Znear: -1, Zfar: 1}}}}, true},
-		{"Document.Cameras[0].Orthographic.Zfar", &Document{Asset: Asset{Version: "1.0"},
+		{"/cameras/0/orthographic/zfar", &Document{Asset: Asset{Version: "1.0"},
 			Cameras: []Camera{{Orthographic: &Orthographic{Znear: 1, Zfar: 1}}}}, true},
-		{"Document.Meshes[0].Primitives", &Document{Asset: Asset{Version: "1.0"},
+		{"/meshes/0/primitives", &Document{Asset: Asset{Version: "1.0"},
 			Meshes: []Mesh{{Primitives: make([]Primitive, 0)}}}, true},
-		{"Document.Meshes[0].Primitives[0].Mode", &Document{Asset: Asset{Version: "1.0"},
+		{"/meshes/0/primitives/0/mode", &Document{Asset: Asset{Version: "1.0"},
 			Meshes: []Mesh{{Primitives: []Primitive{{Mode: 7}}}}}, true},
-		{"Document.Meshes[0].Primitives[0].Targets[0][OTHER]", &Document{Asset: Asset{Version: "1.0"},
+		{"/meshes/0/primitives/0/targets/0/OTHER", &Document{Asset: Asset{Version: "1.0"},
 			Meshes: []Mesh{{Primitives: []Primitive{{Targets: []Attribute{{"OTHER": 1}}}}}}}, true},
-		{"Document.Materials[0].EmissiveFactor[0]", &Document{Asset: Asset{Version: "1.0"},
+		{"/materials/0/emissiveFactor/0", &Document{Asset: Asset{Version: "1.0"},
 			Materials: []Material{{EmissiveFactor: [3]float64{-1, 1, 1}}}}, true},
-		{"Document.Materials[0].EmissiveFactor[1]", &Document{Asset: Asset{Version: "1.0"},
+		{"/materials/0/emissiveFactor/1", &Document{Asset: Asset{Version: "1.0"},
 			Materials: []Material{{AlphaMode: Opaque, EmissiveFactor: [3]float64{1, 2, 1}}}}, true},
-		{"Document.Materials[0].AlphaMode", &Document{Asset: Asset{Version: "1.0"},
+		{"/materials/0/alphaMode", &Document{Asset: Asset{Version: "1.0"},
 			Materials: []Material{{AlphaMode: 5}}}, true},
-		{"Document.Materials[0].PBRMetallicRoughness.BaseColorFactor.R", &Document{Asset: Asset{Version: "1.0"},
+		{"/materials/0/pbrMetallicRoughness/baseColorFactor/0", &Document{Asset: Asset{Version: "1.0"},
 			Materials: []Material{{AlphaMode: Opaque,
 				PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorFactor: &RGBA{R: -0.1, G: 0.8, B: 0.8, A: 1}}}}}, true},
-		{"Document.Materials[0].PBRMetallicRoughness.BaseColorFactor.G", &Document{Asset: Asset{Version: "1.0"},
+		{"/materials/0/pbrMetallicRoughness/baseColorFactor/1", &Document{Asset: Asset{Version: "1.0"},
 			Materials: []Material{{AlphaMode: Opaque,
 				PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorFactor: &RGBA{R: 1, G: 1.1, B: 0, A: 0}}}}}, true},
-		{"Document.Materials[0].PBRMetallicRoughness.MetallicFactor", &Document{Asset: Asset{Version: "1.0"},
+		{"/materials/0/pbrMetallicRoughness/metallicFactor", &Document{Asset: Asset{Version: "1.0"},
 			Materials: []Material{{AlphaMode: Opaque,
 				PBRMetallicRoughness: &PBRMetallicRoughness{MetallicFactor: Float64(2)}}}}, true},
-		{"Document.Materials[0].PBRMetallicRoughness.MetallicFactor", &Document{Asset: Asset{Version: "1.0"},
+		{"/materials/0/pbrMetallicRoughness/metallicFactor", &Document{Asset: Asset{Version: "1.0"},
 			Materials: []Material{{AlphaMode: Opaque,
 				PBRMetallicRoughness: &PBRMetallicRoughness{MetallicFactor: Float64(-1)}}}}, true},
-		{"Document.Materials[0].PBRMetallicRoughness.RoughnessFactor", &Document{Asset: Asset{Version: "1.0"},
+		{"/materials/0/pbrMetallicRoughness/roughnessFactor", &Document{Asset: Asset{Version: "1.0"},
 			Materials: []Material{{AlphaMode: Opaque,
 				PBRMetallicRoughness: &PBRMetallicRoughness{RoughnessFactor: Float64(2)}}}}, true},
-		{"Document.Materials[0].PBRMetallicRoughness.RoughnessFactor", &Document{Asset: Asset{Version: "1.0"},
+		{"/materials/0/pbrMetallicRoughness/roughnessFactor", &Document{Asset: Asset{Version: "1.0"},
 			Materials: []Material{{AlphaMode: Opaque,
 				PBRMetallicRoughness: &PBRMetallicRoughness{RoughnessFactor: Float64(-1)}}}}, true},
-		{"Document.Samplers[0].MagFilter", &Document{Asset: Asset{Version: "1.0"},
+		{"/samplers/0/magFilter", &Document{Asset: Asset{Version: "1.0"},
 			Samplers: []Sampler{{MagFilter: 10, MinFilter: MinLinear, WrapS: ClampToEdge, WrapT: ClampToEdge}}}, true},
-		{"Document.Samplers[0].MinFilter", &Document{Asset: Asset{Version: "1.0"},
+		{"/samplers/0/minFilter", &Document{Asset: Asset{Version: "1.0"},
 			Samplers: []Sampler{{MagFilter: MagLinear, MinFilter: 10, WrapS: ClampToEdge, WrapT: ClampToEdge}}}, true},
-		{"Document.Samplers[0].WrapS", &Document{Asset: Asset{Version: "1.0"},
+		{"/samplers/0/wrapS", &Document{Asset: Asset{Version: "1.0"},
 			Samplers: []Sampler{{MagFilter: MagLinear, MinFilter: MinLinear, WrapS: 10, WrapT: ClampToEdge}}}, true},
-		{"Document.Samplers[0].WrapT", &Document{Asset: Asset{Version: "1.0"},
+		{"/samplers/0/wrapT", &Document{Asset: Asset{Version: "1.0"},
 			Samplers: []Sampler{{MagFilter: MagLinear, MinFilter: MinLinear, WrapS: ClampToEdge, WrapT: 10}}}, true},
-		{"Document.Images[0].URI", &Document{Asset: Asset{Version: "1.0"},
+		{"/images/0/uri", &Document{Asset: Asset{Version: "1.0"},
 			Images: []Image{{URI: "a.png"}}}, false},
-		{"Document.Images[0].MimeType", &Document{Asset: Asset{Version: "1.0"},
+		{"/images/0/mimeType", &Document{Asset: Asset{Version: "1.0"},
 			Images: []Image{{BufferView: 1}}}, true},
-		{"Document.Animations[0].Channels", &Document{Asset: Asset{Version: "1.0"},
+		{"/animations/0/channels", &Document{Asset: Asset{Version: "1.0"},
 			Animations: []Animation{{Samplers: []AnimationSampler{{}}}}}, true},
-		{"Document.Animations[0].Channels[0].Target.Path", &Document{Asset: Asset{Version: "1.0"},
+		{"/animations/0/channels/0/target/path", &Document{Asset: Asset{Version: "1.0"},
 			Animations: []Animation{{Channels: []Channel{{Target: ChannelTarget{Path: 10}}}, Samplers: []AnimationSampler{{}}}}}, true},
-		{"Document.Animations[0].Samplers[0].Interpolation", &Document{Asset: Asset{Version: "1.0"},
+		{"/animations/0/samplers/0/interpolation", &Document{Asset: Asset{Version: "1.0"},
 			Animations: []Animation{{Channels: []Channel{{Target: ChannelTarget{Path: Translation}}},
 				Samplers: []AnimationSampler{{Interpolation: 10}}}}}, true},
 		{"ok", &Document{
@@ -118,7 +121,7 @@ func TestValidateDocument(t *testing.T) {
 				return
 			}
 			if tt.wantErr {
-				errVal := err.(val.ValidationErrors)[0].Namespace()
+				errVal := err.(ValidationErrors)[0].Pointer
 				if errVal != tt.name {
 					t.Errorf("Document.Validate() error = %v, wantErr %v", errVal, tt.name)
 				}
@@ -126,3 +129,914 @@ func TestValidateDocument(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDocument_Targets(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"ok", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{
+				{BufferView: Index(0), ComponentType: UnsignedShort, Count: 3, Type: Scalar},
+				{BufferView: Index(1), ComponentType: Float, Count: 3, Type: Vec3},
+			},
+			BufferViews: []BufferView{{ByteLength: 6, Target: ElementArrayBuffer}, {ByteLength: 36, Target: ArrayBuffer}},
+			Meshes:      []Mesh{{Primitives: []Primitive{{Indices: Index(0), Attributes: Attribute{"POSITION": 1}}}}},
+		}, nil},
+		{"undefined", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{
+				{BufferView: Index(0), ComponentType: UnsignedShort, Count: 3, Type: Scalar},
+				{BufferView: Index(1), ComponentType: Float, Count: 3, Type: Vec3},
+			},
+			BufferViews: []BufferView{{ByteLength: 6}, {ByteLength: 36}},
+			Meshes:      []Mesh{{Primitives: []Primitive{{Indices: Index(0), Attributes: Attribute{"POSITION": 1}}}}},
+		}, nil},
+		{"indicesTarget", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{
+				{BufferView: Index(0), ComponentType: UnsignedShort, Count: 3, Type: Scalar},
+				{BufferView: Index(1), ComponentType: Float, Count: 3, Type: Vec3},
+			},
+			BufferViews: []BufferView{{ByteLength: 6, Target: ArrayBuffer}, {ByteLength: 36, Target: ArrayBuffer}},
+			Meshes:      []Mesh{{Primitives: []Primitive{{Indices: Index(0), Attributes: Attribute{"POSITION": 1}}}}},
+		}, []*ValidationError{
+			{"/meshes/0/primitives/0/indices", ErrIndicesTarget},
+		}},
+		{"indicesComponentType", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{
+				{BufferView: Index(0), ComponentType: Float, Count: 3, Type: Scalar},
+				{BufferView: Index(1), ComponentType: Float, Count: 3, Type: Vec3},
+			},
+			BufferViews: []BufferView{{ByteLength: 6, Target: ElementArrayBuffer}, {ByteLength: 36, Target: ArrayBuffer}},
+			Meshes:      []Mesh{{Primitives: []Primitive{{Indices: Index(0), Attributes: Attribute{"POSITION": 1}}}}},
+		}, []*ValidationError{
+			{"/meshes/0/primitives/0/indices", ErrIndicesComponentType},
+		}},
+		{"attributeTarget", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{
+				{BufferView: Index(0), ComponentType: UnsignedShort, Count: 3, Type: Scalar},
+				{BufferView: Index(1), ComponentType: Float, Count: 3, Type: Vec3},
+			},
+			BufferViews: []BufferView{{ByteLength: 6, Target: ElementArrayBuffer}, {ByteLength: 36, Target: ElementArrayBuffer}},
+			Meshes:      []Mesh{{Primitives: []Primitive{{Indices: Index(0), Attributes: Attribute{"POSITION": 1}}}}},
+		}, []*ValidationError{
+			{"/meshes/0/primitives/0/attributes/POSITION", ErrAttributeTarget},
+		}},
+		{"attributeComponentType", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{
+				{BufferView: Index(0), ComponentType: UnsignedInt, Count: 3, Type: Scalar},
+				{BufferView: Index(1), ComponentType: UnsignedInt, Count: 3, Type: Vec3},
+			},
+			BufferViews: []BufferView{{ByteLength: 6, Target: ElementArrayBuffer}, {ByteLength: 36, Target: ArrayBuffer}},
+			Meshes:      []Mesh{{Primitives: []Primitive{{Indices: Index(0), Attributes: Attribute{"POSITION": 1}}}}},
+		}, []*ValidationError{
+			{"/meshes/0/primitives/0/attributes/POSITION", ErrAttributeComponentType},
+		}},
+		{"swapped", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{
+				{BufferView: Index(0), ComponentType: UnsignedByte, Count: 3, Type: Scalar},
+				{BufferView: Index(1), ComponentType: Float, Count: 3, Type: Vec3},
+			},
+			BufferViews: []BufferView{{ByteLength: 6, Target: ArrayBuffer}, {ByteLength: 36, Target: ElementArrayBuffer}},
+			Meshes:      []Mesh{{Primitives: []Primitive{{Indices: Index(0), Attributes: Attribute{"POSITION": 1}}}}},
+		}, []*ValidationError{
+			{"/meshes/0/primitives/0/indices", ErrIndicesTarget},
+			{"/meshes/0/primitives/0/attributes/POSITION", ErrAttributeTarget},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.Validate() = %v", diff)
+			}
+		})
+	}
+}
+
+// diffValidationErrors compares err with the expected ValidationErrors by pointer and error.
+// deep.Equal can not be used, as it panics when comparing slices that implement error.
+func diffValidationErrors(err error, want []*ValidationError) []string {
+	got, ok := err.(ValidationErrors)
+	if err != nil && !ok {
+		return []string{fmt.Sprintf("error = %v, want ValidationErrors", err)}
+	}
+	if len(got) != len(want) {
+		return []string{fmt.Sprintf("%v != %v", got, ValidationErrors(want))}
+	}
+	var diff []string
+	for i := range got {
+		if got[i].Pointer != want[i].Pointer || !reflect.DeepEqual(got[i].Err, want[i].Err) {
+			diff = append(diff, fmt.Sprintf("[%d]: %v != %v", i, got[i], want[i]))
+		}
+	}
+	return diff
+}
+
+func TestValidateDocument_Alignment(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"ok", &Document{Asset: Asset{Version: "2.0"},
+			Accessors:   []Accessor{{BufferView: Index(0), ByteOffset: 2, ComponentType: UnsignedShort, Count: 1}},
+			BufferViews: []BufferView{{ByteOffset: 4, ByteLength: 4}}}, nil},
+		{"offset", &Document{Asset: Asset{Version: "2.0"},
+			Accessors:   []Accessor{{BufferView: Index(0), ByteOffset: 1, ComponentType: UnsignedShort, Count: 1}},
+			BufferViews: []BufferView{{ByteOffset: 4, ByteLength: 4}}}, []*ValidationError{
+			{"/accessors/0/byteOffset", ErrAccessorAlignment},
+		}},
+		{"bufferViewOffset", &Document{Asset: Asset{Version: "2.0"},
+			Accessors:   []Accessor{{BufferView: Index(0), ComponentType: Float, Count: 1}},
+			BufferViews: []BufferView{{ByteOffset: 2, ByteLength: 4}}}, []*ValidationError{
+			{"/accessors/0/byteOffset", ErrAccessorAlignment},
+		}},
+		{"stride", &Document{Asset: Asset{Version: "2.0"},
+			Accessors:   []Accessor{{BufferView: Index(0), ComponentType: Float, Count: 1}},
+			BufferViews: []BufferView{{ByteLength: 4, ByteStride: 6}}}, []*ValidationError{
+			{"/accessors/0/byteOffset", ErrAccessorAlignment},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.Validate() = %v", diff)
+			}
+		})
+	}
+}
+
+func Test_namespaceToPointer(t *testing.T) {
+	tests := []struct {
+		namespace string
+		want      string
+	}{
+		{"Document", ""},
+		{"Document.asset.version", "/asset/version"},
+		{"Document.meshes[3].primitives[0].attributes[POSITION]", "/meshes/3/primitives/0/attributes/POSITION"},
+		{"Document.meshes[0].primitives[0].targets[0][a/b~c]", "/meshes/0/primitives/0/targets/0/a~1b~0c"},
+		{"Document.materials[0].pbrMetallicRoughness.baseColorFactor.0", "/materials/0/pbrMetallicRoughness/baseColorFactor/0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			if got := namespaceToPointer(tt.namespace); got != tt.want {
+				t.Errorf("namespaceToPointer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_ValidateWarnings_Rotations(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"ok", &Document{Asset: Asset{Version: "2.0"},
+			Nodes: []Node{{Rotation: DefaultRotation}, {Rotation: emptyRotation}, {Rotation: [4]float64{0, 0, 0.7071, 0.7071}}}}, nil},
+		{"notUnit", &Document{Asset: Asset{Version: "2.0"},
+			Nodes: []Node{{Rotation: DefaultRotation}, {Rotation: [4]float64{0, 0, 0.5, 0.5}}}}, []*ValidationError{
+			{"/nodes/1/rotation", ErrRotationNotUnit},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.ValidateWarnings()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.ValidateWarnings() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.ValidateWarnings() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateDocument_Hierarchy(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"ok", &Document{Asset: Asset{Version: "2.0"},
+			Nodes:  []Node{{Children: []uint32{1, 2}}, {Children: []uint32{3}}, {}, {}, {}},
+			Scenes: []Scene{{Nodes: []uint32{0}}, {Nodes: []uint32{0, 4}}}}, nil},
+		{"multipleParents", &Document{Asset: Asset{Version: "2.0"},
+			Nodes:  []Node{{Children: []uint32{1, 2}}, {Children: []uint32{2}}, {}},
+			Scenes: []Scene{{Nodes: []uint32{0}}}}, []*ValidationError{
+			{"/nodes/1/children/0", ErrNodeMultipleParents},
+		}},
+		{"sceneRoot", &Document{Asset: Asset{Version: "2.0"},
+			Nodes:  []Node{{Children: []uint32{1}}, {}},
+			Scenes: []Scene{{Nodes: []uint32{0}}, {Nodes: []uint32{0, 1}}}}, []*ValidationError{
+			{"/scenes/1/nodes/1", ErrSceneRootNotRoot},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.Validate() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_ValidateWarnings_Textures(t *testing.T) {
+	npot := "data:image/png;base64," + base64.StdEncoding.EncodeToString(encodePNG(3, 4))
+	pot := "data:image/png;base64," + base64.StdEncoding.EncodeToString(encodePNG(4, 4))
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"pot", &Document{Asset: Asset{Version: "2.0"},
+			Images:   []Image{{URI: pot}},
+			Samplers: []Sampler{{MinFilter: MinLinearMipMapLinear}},
+			Textures: []Texture{{Source: Index(0)}, {Sampler: Index(0), Source: Index(0)}},
+		}, nil},
+		{"noMipmaps", &Document{Asset: Asset{Version: "2.0"},
+			Images:   []Image{{URI: npot}},
+			Samplers: []Sampler{{MinFilter: MinLinear}},
+			Textures: []Texture{{Source: Index(0)}, {Sampler: Index(0), Source: Index(0)}},
+		}, nil},
+		{"clamp", &Document{Asset: Asset{Version: "2.0"},
+			Images:   []Image{{URI: npot}},
+			Samplers: []Sampler{{MinFilter: MinLinearMipMapLinear, WrapS: ClampToEdge, WrapT: ClampToEdge}},
+			Textures: []Texture{{Source: Index(0)}, {Sampler: Index(0), Source: Index(0)}},
+		}, nil},
+		{"external", &Document{Asset: Asset{Version: "2.0"},
+			Images:   []Image{{URI: "a.png"}},
+			Samplers: []Sampler{{MinFilter: MinLinearMipMapLinear}},
+			Textures: []Texture{{Source: Index(0)}, {Sampler: Index(0), Source: Index(0)}},
+		}, nil},
+		{"npot", &Document{Asset: Asset{Version: "2.0"},
+			Images:   []Image{{URI: npot}},
+			Samplers: []Sampler{{MinFilter: MinNearestMipMapNearest, WrapS: ClampToEdge, WrapT: MirroredRepeat}},
+			Textures: []Texture{{Source: Index(0)}, {Sampler: Index(0), Source: Index(0)}},
+		}, []*ValidationError{
+			{"/textures/1", ErrTextureNPOT},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.ValidateWarnings()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.ValidateWarnings() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.ValidateWarnings() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateDocument_Animations(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"unique", &Document{Asset: Asset{Version: "2.0"},
+			Nodes: []Node{{}, {}},
+			Animations: []Animation{{Samplers: []AnimationSampler{{}}, Channels: []Channel{
+				{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Translation}},
+				{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Rotation}},
+				{Sampler: Index(0), Target: ChannelTarget{Node: Index(1), Path: Translation}},
+			}}},
+		}, nil},
+		{"pointer", &Document{Asset: Asset{Version: "2.0"},
+			Nodes: []Node{{}, {}},
+			Animations: []Animation{{Samplers: []AnimationSampler{{}}, Channels: []Channel{
+				{Target: ChannelTarget{Path: Pointer}},
+				{Target: ChannelTarget{Path: Pointer}},
+			}}},
+		}, nil},
+		{"duplicate", &Document{Asset: Asset{Version: "2.0"},
+			Nodes: []Node{{}, {}},
+			Animations: []Animation{{Samplers: []AnimationSampler{{}}, Channels: []Channel{
+				{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Translation}},
+				{Sampler: Index(0), Target: ChannelTarget{Node: Index(1), Path: Scale}},
+				{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Translation}},
+				{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Translation}},
+			}}},
+		}, []*ValidationError{
+			{"/animations/0/channels/2/target", ErrChannelDuplicateTarget},
+			{"/animations/0/channels/3/target", ErrChannelDuplicateTarget},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.Validate() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateDocument_SamplerOutputCount(t *testing.T) {
+	wantErr := []*ValidationError{{"/animations/0/samplers/0/output", ErrSamplerOutputCount}}
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"linear", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 2, Type: Vec3}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{}, Targets: []Attribute{{}, {}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Translation}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1), Interpolation: Linear}},
+			}},
+		}, nil},
+		{"step", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 3, Type: Vec3}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{}, Targets: []Attribute{{}, {}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Translation}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1), Interpolation: Step}},
+			}},
+		}, wantErr},
+		{"cubicSpline", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 6, Type: Vec3}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{}, Targets: []Attribute{{}, {}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Translation}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1), Interpolation: CubicSpline}},
+			}},
+		}, nil},
+		{"cubicSplineValuesOnly", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 2, Type: Vec3}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{}, Targets: []Attribute{{}, {}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Translation}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1), Interpolation: CubicSpline}},
+			}},
+		}, wantErr},
+		{"weights", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 4, Type: Scalar}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{}, Targets: []Attribute{{}, {}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Weights}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1), Interpolation: Linear}},
+			}},
+		}, nil},
+		{"weightsCubicSpline", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 12, Type: Scalar}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{}, Targets: []Attribute{{}, {}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Weights}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1), Interpolation: CubicSpline}},
+			}},
+		}, nil},
+		{"weightsPerKeyframe", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 2, Type: Scalar}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{}, Targets: []Attribute{{}, {}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Weights}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1), Interpolation: Linear}},
+			}},
+		}, wantErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.Validate() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateDocument_Tangents(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"valid", newTangentDoc(1, []float64{1, -1, 1}), nil},
+		{"tolerance", newTangentDoc(1, []float64{1.0001, -0.9999, 1}), nil},
+		{"invalid", newTangentDoc(1, []float64{1, 0, 0.5}), []*ValidationError{
+			{"/meshes/0/primitives/0/attributes/TANGENT", ErrTangentHandedness},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.Validate() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateDocument_Sparse(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"valid", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 4, Type: Scalar, Sparse: &Sparse{Count: 3,
+				Indices: SparseIndices{BufferView: 0, ComponentType: UnsignedShort},
+				Values:  SparseValues{BufferView: 1},
+			}}},
+			BufferViews: []BufferView{{ByteLength: 6}, {ByteOffset: 8, ByteLength: 12}},
+			Buffers:     []Buffer{{ByteLength: 20, Data: encodeData([]uint16{0, 1, 3, 0}, []float32{1, 2, 3})}},
+		}, nil},
+		{"unordered", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 4, Type: Scalar, Sparse: &Sparse{Count: 3,
+				Indices: SparseIndices{BufferView: 0, ComponentType: UnsignedShort},
+				Values:  SparseValues{BufferView: 1},
+			}}},
+			BufferViews: []BufferView{{ByteLength: 6}, {ByteOffset: 8, ByteLength: 12}},
+			Buffers:     []Buffer{{ByteLength: 20, Data: encodeData([]uint16{0, 2, 1, 0}, []float32{1, 2, 3})}},
+		}, []*ValidationError{
+			{"/accessors/0/sparse/indices", ErrSparseIndicesOrder},
+		}},
+		{"repeated", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 4, Type: Scalar, Sparse: &Sparse{Count: 3,
+				Indices: SparseIndices{BufferView: 0, ComponentType: UnsignedShort},
+				Values:  SparseValues{BufferView: 1},
+			}}},
+			BufferViews: []BufferView{{ByteLength: 6}, {ByteOffset: 8, ByteLength: 12}},
+			Buffers:     []Buffer{{ByteLength: 20, Data: encodeData([]uint16{0, 1, 1, 0}, []float32{1, 2, 3})}},
+		}, []*ValidationError{
+			{"/accessors/0/sparse/indices", ErrSparseIndicesOrder},
+		}},
+		{"outOfRange", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 4, Type: Scalar, Sparse: &Sparse{Count: 3,
+				Indices: SparseIndices{BufferView: 0, ComponentType: UnsignedShort},
+				Values:  SparseValues{BufferView: 1},
+			}}},
+			BufferViews: []BufferView{{ByteLength: 6}, {ByteOffset: 8, ByteLength: 12}},
+			Buffers:     []Buffer{{ByteLength: 20, Data: encodeData([]uint16{0, 1, 4, 0}, []float32{1, 2, 3})}},
+		}, []*ValidationError{
+			{"/accessors/0/sparse/indices", ErrSparseIndexOutOfRange},
+		}},
+		{"shortValues", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 4, Type: Scalar, Sparse: &Sparse{Count: 3,
+				Indices: SparseIndices{BufferView: 0, ComponentType: UnsignedShort},
+				Values:  SparseValues{BufferView: 1},
+			}}},
+			BufferViews: []BufferView{{ByteLength: 6}, {ByteOffset: 8, ByteLength: 8}},
+			Buffers:     []Buffer{{ByteLength: 20, Data: encodeData([]uint16{0, 1, 2, 0}, []float32{1, 2, 3})}},
+		}, []*ValidationError{
+			{"/accessors/0/sparse/values", ErrSparseDataLength},
+		}},
+		{"missingValues", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 4, Type: Scalar, Sparse: &Sparse{Count: 3,
+				Indices: SparseIndices{BufferView: 0, ComponentType: UnsignedShort},
+				Values:  SparseValues{BufferView: 2},
+			}}},
+			BufferViews: []BufferView{{ByteLength: 6}, {ByteOffset: 8, ByteLength: 12}},
+			Buffers:     []Buffer{{ByteLength: 20, Data: encodeData([]uint16{0, 1, 2, 0}, []float32{1, 2, 3})}},
+		}, []*ValidationError{
+			{"/accessors/0/sparse/values/bufferView", ErrIndexOutOfRange},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.Validate() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateDocument_References(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"valid", &Document{Asset: Asset{Version: "2.0"},
+			Images:   []Image{{URI: "a.png"}},
+			Samplers: []Sampler{{}},
+			Textures: []Texture{{}, {Sampler: Index(0), Source: Index(0)}},
+		}, nil},
+		{"dangling", &Document{Asset: Asset{Version: "2.0"},
+			Images:   []Image{{URI: "a.png"}},
+			Textures: []Texture{{Sampler: Index(0), Source: Index(0)}, {Source: Index(1)}},
+		}, []*ValidationError{
+			{"/textures/0/sampler", ErrIndexOutOfRange},
+			{"/textures/1/source", ErrIndexOutOfRange},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.Validate() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_ValidateWarnings_TexCoords(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"repeat", &Document{Asset: Asset{Version: "2.0"},
+			Accessors:   []Accessor{{BufferView: Index(0), ComponentType: Float, Count: 2, Type: Vec2}},
+			BufferViews: []BufferView{{ByteLength: 16, Target: ArrayBuffer}},
+			Buffers:     []Buffer{{ByteLength: 16, Data: encodeData([]float32{0, 0, 2, -1})}},
+			Materials: []Material{{
+				PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 0}},
+				EmissiveTexture:      &TextureInfo{Index: 0},
+			}},
+			Meshes:   []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0, "TEXCOORD_0": 0}, Material: Index(0)}}}},
+			Samplers: []Sampler{{}},
+			Textures: []Texture{{Sampler: Index(0)}},
+		}, nil},
+		{"inRange", &Document{Asset: Asset{Version: "2.0"},
+			Accessors:   []Accessor{{BufferView: Index(0), ComponentType: Float, Count: 2, Type: Vec2}},
+			BufferViews: []BufferView{{ByteLength: 16, Target: ArrayBuffer}},
+			Buffers:     []Buffer{{ByteLength: 16, Data: encodeData([]float32{0, 0, 1, 0.5})}},
+			Materials: []Material{{
+				PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 0}},
+				EmissiveTexture:      &TextureInfo{Index: 0},
+			}},
+			Meshes:   []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0, "TEXCOORD_0": 0}, Material: Index(0)}}}},
+			Samplers: []Sampler{{WrapS: ClampToEdge, WrapT: ClampToEdge}},
+			Textures: []Texture{{Sampler: Index(0)}},
+		}, nil},
+		{"clampOtherAxis", &Document{Asset: Asset{Version: "2.0"},
+			Accessors:   []Accessor{{BufferView: Index(0), ComponentType: Float, Count: 2, Type: Vec2}},
+			BufferViews: []BufferView{{ByteLength: 16, Target: ArrayBuffer}},
+			Buffers:     []Buffer{{ByteLength: 16, Data: encodeData([]float32{0, 0, 2, 1})}},
+			Materials: []Material{{
+				PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 0}},
+				EmissiveTexture:      &TextureInfo{Index: 0},
+			}},
+			Meshes:   []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0, "TEXCOORD_0": 0}, Material: Index(0)}}}},
+			Samplers: []Sampler{{WrapT: ClampToEdge}},
+			Textures: []Texture{{Sampler: Index(0)}},
+		}, nil},
+		{"clamped", &Document{Asset: Asset{Version: "2.0"},
+			Accessors:   []Accessor{{BufferView: Index(0), ComponentType: Float, Count: 2, Type: Vec2}},
+			BufferViews: []BufferView{{ByteLength: 16, Target: ArrayBuffer}},
+			Buffers:     []Buffer{{ByteLength: 16, Data: encodeData([]float32{0, 0, 2, 1})}},
+			Materials: []Material{{
+				PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorTexture: &TextureInfo{Index: 0}},
+				EmissiveTexture:      &TextureInfo{Index: 0},
+			}},
+			Meshes:   []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0, "TEXCOORD_0": 0}, Material: Index(0)}}}},
+			Samplers: []Sampler{{WrapS: ClampToEdge}},
+			Textures: []Texture{{Sampler: Index(0)}},
+		}, []*ValidationError{
+			{"/meshes/0/primitives/0/attributes/TEXCOORD_0", ErrTexCoordClamped},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.ValidateWarnings()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.ValidateWarnings() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.ValidateWarnings() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocument_ValidateWarnings(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"empty", &Document{}, nil},
+		{"mask", &Document{Materials: []Material{{AlphaMode: Mask, AlphaCutoff: Float64(0.2)}}}, nil},
+		{"default", &Document{Materials: []Material{{AlphaMode: Blend, AlphaCutoff: Float64(0.5)}, {}}}, nil},
+		{"unused", &Document{Materials: []Material{{AlphaMode: Mask, AlphaCutoff: Float64(0.2)}, {AlphaMode: Opaque, AlphaCutoff: Float64(0.2)}, {AlphaMode: Blend, AlphaCutoff: Float64(0)}}}, []*ValidationError{
+			{"/materials/1/alphaCutoff", ErrAlphaCutoffUnused},
+			{"/materials/2/alphaCutoff", ErrAlphaCutoffUnused},
+		}},
+		{"adjacentViews", &Document{BufferViews: []BufferView{
+			{ByteLength: 8}, {ByteOffset: 8, ByteLength: 4}, {Buffer: 1, ByteLength: 8},
+		}}, nil},
+		{"interleavedViews", &Document{BufferViews: []BufferView{
+			{ByteLength: 32, ByteStride: 16, Target: ArrayBuffer}, {ByteOffset: 12, ByteLength: 20, ByteStride: 16, Target: ArrayBuffer},
+		}}, nil},
+		{"overlappingViews", &Document{BufferViews: []BufferView{
+			{ByteOffset: 8, ByteLength: 8, Target: ElementArrayBuffer},
+			{ByteLength: 12, ByteStride: 12, Target: ArrayBuffer},
+			{ByteOffset: 4, ByteLength: 4},
+			{ByteOffset: 16, ByteLength: 4},
+		}}, []*ValidationError{
+			{"/bufferViews/1", ErrBufferViewOverlap},
+			{"/bufferViews/2", ErrBufferViewOverlap},
+		}},
+		{"defaultScale", &Document{Nodes: []Node{{}, {Scale: [3]float64{1, 2, 3}}}}, nil},
+		{"badScale", &Document{Nodes: []Node{{Scale: [3]float64{1, 0, 1}}, {Scale: [3]float64{-1, 1, 1}}, {Scale: [3]float64{0, -1, 0}}}}, []*ValidationError{
+			{"/nodes/0/scale", ErrNodeZeroScale},
+			{"/nodes/1/scale", ErrNodeNegativeScale},
+			{"/nodes/2/scale", ErrNodeZeroScale},
+			{"/nodes/2/scale", ErrNodeNegativeScale},
+		}},
+		{"missingPosition", &Document{Meshes: []Mesh{{Primitives: []Primitive{
+			{Attributes: Attribute{"POSITION": 0}},
+			{Attributes: Attribute{"NORMAL": 0}},
+			{Attributes: Attribute{}, Extensions: Extensions{"KHR_draco_mesh_compression": nil}},
+		}}}}, []*ValidationError{
+			{"/meshes/0/primitives/1/attributes", ErrMissingPosition},
+		}},
+		{"missingTarget", &Document{
+			Accessors: []Accessor{
+				{BufferView: Index(0), ComponentType: UnsignedShort, Count: 3, Type: Scalar},
+				{BufferView: Index(1), ComponentType: Float, Count: 3, Type: Vec3},
+				{BufferView: Index(2), ComponentType: Float, Count: 3, Type: Vec3},
+				{ComponentType: Float, Count: 3, Type: Vec2},
+			},
+			BufferViews: []BufferView{{ByteLength: 6}, {ByteOffset: 8, ByteLength: 36, Target: ArrayBuffer}, {ByteOffset: 44, ByteLength: 36}},
+			Meshes: []Mesh{{Primitives: []Primitive{
+				{Indices: Index(0), Attributes: Attribute{"POSITION": 1, "NORMAL": 2, "TEXCOORD_0": 3}},
+			}}},
+		}, []*ValidationError{
+			{"/meshes/0/primitives/0/indices", ErrMissingTarget},
+			{"/meshes/0/primitives/0/attributes/NORMAL", ErrMissingTarget},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.ValidateWarnings()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.ValidateWarnings() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.ValidateWarnings() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateDocument_ChannelOutputType(t *testing.T) {
+	wantErr := []*ValidationError{{"/animations/0/channels/0/target/path", ErrChannelOutputType}}
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"translation", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 2, Type: Vec3}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}, Targets: []Attribute{{}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Translation}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1)}},
+			}},
+		}, nil},
+		{"rotation", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 2, Type: Vec4}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}, Targets: []Attribute{{}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Rotation}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1)}},
+			}},
+		}, nil},
+		{"scale", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 2, Type: Vec3}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}, Targets: []Attribute{{}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Scale}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1)}},
+			}},
+		}, nil},
+		{"weights", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 2, Type: Scalar}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}, Targets: []Attribute{{}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Weights}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1)}},
+			}},
+		}, nil},
+		{"translationVec4", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 2, Type: Vec4}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}, Targets: []Attribute{{}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Translation}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1)}},
+			}},
+		}, wantErr},
+		{"rotationVec3", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 2, Type: Vec3}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}, Targets: []Attribute{{}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Rotation}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1)}},
+			}},
+		}, wantErr},
+		{"scaleScalar", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 2, Type: Scalar}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}, Targets: []Attribute{{}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Scale}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1)}},
+			}},
+		}, wantErr},
+		{"weightsVec3", &Document{Asset: Asset{Version: "2.0"},
+			Accessors: []Accessor{{ComponentType: Float, Count: 2, Type: Scalar}, {ComponentType: Float, Count: 2, Type: Vec3}},
+			Meshes:    []Mesh{{Primitives: []Primitive{{Attributes: Attribute{"POSITION": 0}, Targets: []Attribute{{}}}}}},
+			Nodes:     []Node{{Mesh: Index(0)}},
+			Animations: []Animation{{
+				Channels: []Channel{{Sampler: Index(0), Target: ChannelTarget{Node: Index(0), Path: Weights}}},
+				Samplers: []AnimationSampler{{Input: Index(0), Output: Index(1)}},
+			}},
+		}, wantErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.Validate() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateDocument_EmbeddedBufferLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		wantErr []*ValidationError
+	}{
+		{"valid", &Document{Asset: Asset{Version: "2.0"}, Buffers: []Buffer{
+			{ByteLength: 3, URI: "data:application/octet-stream;base64,AQID"},
+			{ByteLength: 5, URI: "data:application/octet-stream;base64,AQIDBAU="},
+			{ByteLength: 4, URI: "a.bin"},
+		}}, nil},
+		{"mismatch", &Document{Asset: Asset{Version: "2.0"}, Buffers: []Buffer{
+			{ByteLength: 3, URI: "data:application/octet-stream;base64,AQID"},
+			{ByteLength: 4, URI: "data:application/octet-stream;base64,AQIDBAU="},
+		}}, []*ValidationError{
+			{"/buffers/1/byteLength", ErrEmbeddedBufferLength},
+		}},
+		{"noSeparator", &Document{Asset: Asset{Version: "2.0"}, Buffers: []Buffer{
+			{ByteLength: 1, URI: "data:application/octet-stream;base64"},
+		}}, []*ValidationError{
+			{"/buffers/0/byteLength", ErrEmbeddedBufferLength},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Document.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if diff := diffValidationErrors(err, tt.wantErr); diff != nil {
+				t.Errorf("Document.Validate() = %v", diff)
+			}
+		})
+	}
+}
+
+type extrasExtension struct {
+	Extras interface{} `json:"extras,omitempty"`
+}
+
+func TestValidateDocument_Extras(t *testing.T) {
+	errMissingUUID := errors.New("missing uuid")
+	RegisterExtrasValidator(Node{}, func(extras interface{}) error {
+		if m, ok := extras.(map[string]interface{}); ok {
+			if _, ok := m["uuid"].(string); ok {
+				return nil
+			}
+		}
+		return errMissingUUID
+	})
+	defer RegisterExtrasValidator(Node{}, nil)
+	RegisterExtrasValidator(extrasExtension{}, func(extras interface{}) error {
+		if extras == nil {
+			return errMissingUUID
+		}
+		return nil
+	})
+	defer RegisterExtrasValidator(extrasExtension{}, nil)
+	doc := &Document{Asset: Asset{Version: "2.0"},
+		Nodes: []Node{
+			{Extras: map[string]interface{}{"uuid": "a"}},
+			{Extras: map[string]interface{}{"uuid": 1}},
+			{},
+		},
+		Materials: []Material{{Extensions: Extensions{"a/b": &extrasExtension{}}}},
+	}
+	want := ValidationErrors{
+		{"/materials/0/extensions/a~1b/extras", errMissingUUID},
+		{"/nodes/1/extras", errMissingUUID},
+		{"/nodes/2/extras", errMissingUUID},
+	}
+	if diff := diffValidationErrors(doc.Validate(), want); diff != nil {
+		t.Errorf("Document.Validate() = %v", diff)
+	}
+}
+
+func TestRegisterExtrasValidator_concurrent(t *testing.T) {
+	// Run with -race to detect unsynchronized access to the registry.
+	defer RegisterExtrasValidator(extrasExtension{}, nil)
+	doc := &Document{Asset: Asset{Version: "2.0"}, Materials: []Material{{Extensions: Extensions{"a": &extrasExtension{}}}}}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterExtrasValidator(extrasExtension{}, func(interface{}) error { return nil })
+		}()
+		go func() {
+			defer wg.Done()
+			if err := doc.Validate(); err != nil {
+				t.Errorf("Document.Validate() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
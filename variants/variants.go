@@ -0,0 +1,117 @@
+package variants
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qmuntal/gltf"
+)
+
+const (
+	// ExtMaterialsVariants defines the MaterialsVariants unique key.
+	ExtMaterialsVariants = "KHR_materials_variants"
+)
+
+// New returns a new variants.MaterialsVariants.
+func New() json.Unmarshaler {
+	return new(MaterialsVariants)
+}
+
+func init() {
+	gltf.RegisterExtension(ExtMaterialsVariants, New)
+}
+
+// A Variant is a named material configuration of the asset.
+type Variant struct {
+	Name   string      `json:"name"`
+	Extras interface{} `json:"extras,omitempty"`
+}
+
+// A Mapping defines the material used by a primitive when any of the Variants is active.
+type Mapping struct {
+	Material uint32      `json:"material"`
+	Variants []uint32    `json:"variants"`
+	Name     string      `json:"name,omitempty"`
+	Extras   interface{} `json:"extras,omitempty"`
+}
+
+// MaterialsVariants defines the KHR_materials_variants extension, which is used at two levels.
+// In the document extensions it defines the Variants array,
+// and in the extensions of a primitive it defines the Mappings to the material of each variant.
+type MaterialsVariants struct {
+	Variants []Variant `json:"variants,omitempty"`
+	Mappings []Mapping `json:"mappings,omitempty"`
+}
+
+// UnmarshalJSON unmarshal the materials variants extension.
+func (m *MaterialsVariants) UnmarshalJSON(data []byte) error {
+	type alias MaterialsVariants
+	return json.Unmarshal(data, (*alias)(m))
+}
+
+// MaterialIndices returns pointers to the materials of the mappings,
+// so they are updated when the materials of the document are reindexed.
+func (m *MaterialsVariants) MaterialIndices() []*uint32 {
+	indices := make([]*uint32, len(m.Mappings))
+	for i := range m.Mappings {
+		indices[i] = &m.Mappings[i].Material
+	}
+	return indices
+}
+
+// Names returns the names of the variants defined in the document, in index order.
+func Names(doc *gltf.Document) []string {
+	var names []string
+	if root, ok := doc.Extensions[ExtMaterialsVariants].(*MaterialsVariants); ok {
+		for _, v := range root.Variants {
+			names = append(names, v.Name)
+		}
+	}
+	return names
+}
+
+// Material returns the index of the material used by the primitive when the variant is active,
+// which is the primitive material if it has no mapping for the variant.
+func Material(prim *gltf.Primitive, variant uint32) *uint32 {
+	if ext, ok := prim.Extensions[ExtMaterialsVariants].(*MaterialsVariants); ok {
+		for _, mapping := range ext.Mappings {
+			for _, v := range mapping.Variants {
+				if v == variant {
+					return gltf.Index(mapping.Material)
+				}
+			}
+		}
+	}
+	return prim.Material
+}
+
+// Apply activates the variant with the given name by replacing the material of every primitive
+// that has a mapping for it. The primitives without a mapping are left untouched,
+// so it should be applied to a document whose materials are in their default state.
+// It fails if the document does not define the variant or a mapping references a material out of range.
+func Apply(doc *gltf.Document, name string) error {
+	variant := -1
+	for i, n := range Names(doc) {
+		if n == name {
+			variant = i
+			break
+		}
+	}
+	if variant < 0 {
+		return fmt.Errorf("gltf: material variant %s not found", name)
+	}
+	for i := range doc.Meshes {
+		for j := range doc.Meshes[i].Primitives {
+			prim := &doc.Meshes[i].Primitives[j]
+			material := Material(prim, uint32(variant))
+			if material == nil || material == prim.Material {
+				continue
+			}
+			if int(*material) >= len(doc.Materials) {
+				return fmt.Errorf("gltf: material index %d out of range", *material)
+			}
+			prim.Material = material
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package variants
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/qmuntal/gltf"
+)
+
+func TestMaterialsVariants_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    *MaterialsVariants
+		wantErr bool
+	}{
+		{"default", []byte("{}"), new(MaterialsVariants), false},
+		{"variants", []byte(`{"variants": [{"name": "red"}, {"name": "blue", "extras": 1}]}`), &MaterialsVariants{
+			Variants: []Variant{{Name: "red"}, {Name: "blue", Extras: float64(1)}},
+		}, false},
+		{"mappings", []byte(`{"mappings": [{"material": 2, "variants": [0, 1], "name": "paint"}]}`), &MaterialsVariants{
+			Mappings: []Mapping{{Material: 2, Variants: []uint32{0, 1}, Name: "paint"}},
+		}, false},
+		{"invalid", []byte(`{"variants": 1}`), new(MaterialsVariants), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(MaterialsVariants)
+			if err := got.UnmarshalJSON(tt.data); (err != nil) != tt.wantErr {
+				t.Errorf("MaterialsVariants.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MaterialsVariants.UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	data := []byte(`{"asset": {"version": "2.0"},
+	"extensionsUsed": ["KHR_materials_variants"],
+	"extensions": {"KHR_materials_variants": {"variants": [{"name": "red"}, {"name": "blue"}, {"name": "broken"}]}},
+	"materials": [{"name": "default"}, {"name": "red"}, {"name": "blue"}],
+	"meshes": [{"primitives": [
+		{"attributes": {}, "material": 0, "extensions": {"KHR_materials_variants": {"mappings": [{"material": 1, "variants": [0]}, {"material": 2, "variants": [1]}, {"material": 9, "variants": [2]}]}}},
+		{"attributes": {}, "material": 0}
+	]}]}`)
+	tests := []struct {
+		name    string
+		variant string
+		want    []*uint32
+		wantErr bool
+	}{
+		{"red", "red", []*uint32{gltf.Index(1), gltf.Index(0)}, false},
+		{"blue", "blue", []*uint32{gltf.Index(2), gltf.Index(0)}, false},
+		{"outOfRange", "broken", nil, true},
+		{"unknown", "green", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := new(gltf.Document)
+			if err := gltf.NewDecoder(bytes.NewReader(data), nil).Decode(doc); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			if got := Names(doc); !reflect.DeepEqual(got, []string{"red", "blue", "broken"}) {
+				t.Errorf("Names() = %v", got)
+			}
+			err := Apply(doc, tt.variant)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for i, want := range tt.want {
+				if got := doc.Meshes[0].Primitives[i].Material; !reflect.DeepEqual(got, want) {
+					t.Errorf("Apply() primitive %d material = %v, want %v", i, *got, *want)
+				}
+			}
+		})
+	}
+}
+
+func TestMaterialsVariants_RemapMaterial(t *testing.T) {
+	var _ gltf.MaterialReferrer = new(MaterialsVariants)
+	ext := &MaterialsVariants{Mappings: []Mapping{{Material: 1, Variants: []uint32{0}}, {Material: 2, Variants: []uint32{1}}}}
+	doc := &gltf.Document{
+		Materials: []gltf.Material{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+		Meshes: []gltf.Mesh{{Primitives: []gltf.Primitive{
+			{Material: gltf.Index(2), Extensions: gltf.Extensions{ExtMaterialsVariants: ext}},
+		}}},
+	}
+	if err := doc.RemapMaterial(1, 0, true); err != nil {
+		t.Fatalf("Document.RemapMaterial() error = %v", err)
+	}
+	want := []Mapping{{Material: 0, Variants: []uint32{0}}, {Material: 1, Variants: []uint32{1}}}
+	if !reflect.DeepEqual(ext.Mappings, want) {
+		t.Errorf("Document.RemapMaterial() mappings = %v, want %v", ext.Mappings, want)
+	}
+	if got := *doc.Meshes[0].Primitives[0].Material; got != 1 {
+		t.Errorf("Document.RemapMaterial() material = %d, want 1", got)
+	}
+}
@@ -0,0 +1,209 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// vertexField links an attribute accessor with the struct field that receives its elements.
+type vertexField struct {
+	semantic string
+	acc      *Accessor
+	field    reflect.StructField
+}
+
+// ReadVertices fills dst, which must be a pointer to a slice of structs, with one element per vertex.
+// fields maps each attribute semantic to the name of the exported struct field that receives it,
+// which must be a number or an array of numbers with one item per accessor component.
+// The slice is resized to the vertex count, which must be the same for all the mapped attributes.
+//
+// When all the attributes are interleaved in the same bufferView the struct size must match the byte stride.
+// If in addition every struct field is mapped and has the same offset, type and size as its attribute,
+// the bufferView data is decoded directly into the slice.
+// Otherwise each attribute is read with ReadAccessor and converted to the field type.
+func (d *Document) ReadVertices(attributes Attribute, fields map[string]string, dst interface{}) error {
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Ptr || ptr.Elem().Kind() != reflect.Slice || ptr.Elem().Type().Elem().Kind() != reflect.Struct {
+		return errors.New("gltf: vertex destination must be a pointer to a slice of structs")
+	}
+	if len(fields) == 0 {
+		return errors.New("gltf: no vertex fields mapped")
+	}
+	elem := ptr.Elem().Type().Elem()
+	vfs, err := d.vertexFields(attributes, fields, elem)
+	if err != nil {
+		return err
+	}
+	count := vfs[0].acc.Count
+	slice := reflect.MakeSlice(ptr.Elem().Type(), int(count), int(count))
+	if stride, shared := d.sharedStride(vfs); shared {
+		if uint32(elem.Size()) != stride {
+			return fmt.Errorf("gltf: vertex struct size %d does not match byte stride %d", elem.Size(), stride)
+		}
+		if ok, err := d.readVerticesRaw(vfs, elem, slice); ok || err != nil {
+			if err == nil {
+				ptr.Elem().Set(slice)
+			}
+			return err
+		}
+	}
+	for _, vf := range vfs {
+		values, err := d.readAccessor(vf.acc)
+		if err != nil {
+			return err
+		}
+		n := int(vf.acc.Type.Components())
+		for i := 0; i < int(count); i++ {
+			setVertexField(slice.Index(i).FieldByIndex(vf.field.Index), values[i*n:(i+1)*n])
+		}
+	}
+	ptr.Elem().Set(slice)
+	return nil
+}
+
+// vertexFields resolves the accessors and struct fields of the mapping, sorted by semantic.
+func (d *Document) vertexFields(attributes Attribute, fields map[string]string, elem reflect.Type) ([]vertexField, error) {
+	vfs := make([]vertexField, 0, len(fields))
+	for semantic, name := range fields {
+		index, ok := attributes[semantic]
+		if !ok {
+			return nil, fmt.Errorf("gltf: attribute %s not found", semantic)
+		}
+		if int(index) >= len(d.Accessors) {
+			return nil, fmt.Errorf("gltf: accessor index %d out of range", index)
+		}
+		acc := &d.Accessors[index]
+		field, ok := elem.FieldByName(name)
+		if !ok || field.PkgPath != "" {
+			return nil, fmt.Errorf("gltf: vertex struct has no exported field %s", name)
+		}
+		n, kind := 1, field.Type.Kind()
+		if kind == reflect.Array {
+			n, kind = field.Type.Len(), field.Type.Elem().Kind()
+		}
+		if !isNumberKind(kind) || uint32(n) != acc.Type.Components() {
+			return nil, fmt.Errorf("gltf: vertex field %s cannot hold %s elements", name, semantic)
+		}
+		vfs = append(vfs, vertexField{semantic: semantic, acc: acc, field: field})
+	}
+	sort.Slice(vfs, func(i, j int) bool { return vfs[i].semantic < vfs[j].semantic })
+	for _, vf := range vfs[1:] {
+		if vf.acc.Count != vfs[0].acc.Count {
+			return nil, fmt.Errorf("gltf: attribute %s count %d does not match %s count %d", vf.semantic, vf.acc.Count, vfs[0].semantic, vfs[0].acc.Count)
+		}
+	}
+	return vfs, nil
+}
+
+// sharedStride returns the byte stride of the bufferView if all the attributes are interleaved in it.
+func (d *Document) sharedStride(vfs []vertexField) (uint32, bool) {
+	for _, vf := range vfs {
+		if vf.acc.BufferView == nil || *vf.acc.BufferView != *vfs[0].acc.BufferView {
+			return 0, false
+		}
+	}
+	if int(*vfs[0].acc.BufferView) >= len(d.BufferViews) {
+		return 0, false
+	}
+	stride := d.BufferViews[*vfs[0].acc.BufferView].ByteStride
+	return stride, stride != 0
+}
+
+// readVerticesRaw decodes the bufferView data directly into the slice when the struct layout matches it.
+// It reports false without error when the layout does not match.
+func (d *Document) readVerticesRaw(vfs []vertexField, elem reflect.Type, slice reflect.Value) (bool, error) {
+	if elem.NumField() != len(vfs) {
+		return false, nil
+	}
+	base := vfs[0].acc.ByteOffset
+	for _, vf := range vfs[1:] {
+		if vf.acc.ByteOffset < base {
+			base = vf.acc.ByteOffset
+		}
+	}
+	var size uintptr
+	for _, vf := range vfs {
+		kind := vf.field.Type.Kind()
+		if kind == reflect.Array {
+			kind = vf.field.Type.Elem().Kind()
+		}
+		if vf.acc.Sparse != nil || vf.acc.Normalized || kind != componentKind(vf.acc.ComponentType) ||
+			vf.field.Offset != uintptr(vf.acc.ByteOffset-base) || isMatrix(vf.acc.Type) {
+			return false, nil
+		}
+		size += vf.field.Type.Size()
+	}
+	if size != elem.Size() {
+		// The struct has padding that is not present in the binary layout.
+		return false, nil
+	}
+	view, err := d.bufferViewData(*vfs[0].acc.BufferView)
+	if err != nil {
+		return false, err
+	}
+	n := uint64(slice.Len()) * uint64(size)
+	if uint64(base)+n > uint64(len(view)) {
+		// The last element is not padded up to the stride.
+		return false, nil
+	}
+	return true, binary.Read(bytes.NewReader(view[base:uint64(base)+n]), binary.LittleEndian, slice.Interface())
+}
+
+func isMatrix(t AccessorType) bool {
+	return t == Mat2 || t == Mat3 || t == Mat4
+}
+
+// componentKind returns the reflect kind that matches the binary representation of the component type.
+func componentKind(componentType ComponentType) reflect.Kind {
+	switch componentType {
+	case Byte:
+		return reflect.Int8
+	case UnsignedByte:
+		return reflect.Uint8
+	case Short:
+		return reflect.Int16
+	case UnsignedShort:
+		return reflect.Uint16
+	case UnsignedInt:
+		return reflect.Uint32
+	default:
+		return reflect.Float32
+	}
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// setVertexField stores the components in a number or an array of numbers.
+func setVertexField(v reflect.Value, components []float64) {
+	if v.Kind() != reflect.Array {
+		setNumber(v, components[0])
+		return
+	}
+	for i, c := range components {
+		setNumber(v.Index(i), c)
+	}
+}
+
+func setNumber(v reflect.Value, f float64) {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(f)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(math.Round(f)))
+	default:
+		v.SetUint(uint64(math.Round(f)))
+	}
+}
@@ -0,0 +1,91 @@
+package gltf
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDocument_ReadVertices(t *testing.T) {
+	type rawVertex struct {
+		Position [3]float32
+		UV       [2]uint16
+	}
+	type vertex struct {
+		Position [3]float64
+		U        float32
+		UV       [2]int
+	}
+	position := InterleavedAttribute{Semantic: "POSITION", Type: Vec3, ComponentType: Float, Data: []float64{1, 2, 3, 4, 5, 6}}
+	uv := InterleavedAttribute{Semantic: "TEXCOORD_0", Type: Vec2, ComponentType: UnsignedShort, Data: []float64{7, 8, 9, 10}}
+	interleaved := &Document{}
+	interleavedAttrs, err := interleaved.AddInterleaved([]InterleavedAttribute{position, uv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	separate := &Document{}
+	separateAttrs, err := separate.AddInterleaved([]InterleavedAttribute{position})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uvAttrs, err := separate.AddInterleaved([]InterleavedAttribute{uv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	separateAttrs["TEXCOORD_0"] = uvAttrs["TEXCOORD_0"]
+	rawFields := map[string]string{"POSITION": "Position", "TEXCOORD_0": "UV"}
+	wantRaw := []rawVertex{{[3]float32{1, 2, 3}, [2]uint16{7, 8}}, {[3]float32{4, 5, 6}, [2]uint16{9, 10}}}
+
+	t.Run("interleaved", func(t *testing.T) {
+		var got []rawVertex
+		if err := interleaved.ReadVertices(interleavedAttrs, rawFields, &got); err != nil {
+			t.Fatalf("Document.ReadVertices() error = %v", err)
+		}
+		if diff := deep.Equal(got, wantRaw); diff != nil {
+			t.Errorf("Document.ReadVertices() = %v", diff)
+		}
+	})
+	t.Run("separate", func(t *testing.T) {
+		var got []rawVertex
+		if err := separate.ReadVertices(separateAttrs, rawFields, &got); err != nil {
+			t.Fatalf("Document.ReadVertices() error = %v", err)
+		}
+		if diff := deep.Equal(got, wantRaw); diff != nil {
+			t.Errorf("Document.ReadVertices() = %v", diff)
+		}
+	})
+	t.Run("converted", func(t *testing.T) {
+		var got []vertex
+		if err := separate.ReadVertices(separateAttrs, rawFields, &got); err != nil {
+			t.Fatalf("Document.ReadVertices() error = %v", err)
+		}
+		want := []vertex{{Position: [3]float64{1, 2, 3}, UV: [2]int{7, 8}}, {Position: [3]float64{4, 5, 6}, UV: [2]int{9, 10}}}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Errorf("Document.ReadVertices() = %v", diff)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		doc    *Document
+		attrs  Attribute
+		fields map[string]string
+		dst    interface{}
+	}{
+		{"notPointer", separate, separateAttrs, rawFields, []rawVertex{}},
+		{"notStruct", separate, separateAttrs, rawFields, &[]float32{}},
+		{"noFields", separate, separateAttrs, nil, &[]rawVertex{}},
+		{"missingAttribute", separate, separateAttrs, map[string]string{"NORMAL": "Position"}, &[]rawVertex{}},
+		{"missingField", separate, separateAttrs, map[string]string{"POSITION": "Normal"}, &[]rawVertex{}},
+		{"components", separate, separateAttrs, map[string]string{"POSITION": "UV"}, &[]rawVertex{}},
+		{"scalar", separate, separateAttrs, map[string]string{"POSITION": "U"}, &[]vertex{}},
+		{"stride", interleaved, interleavedAttrs, rawFields, &[]vertex{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.doc.ReadVertices(tt.attrs, tt.fields, tt.dst); err == nil {
+				t.Error("Document.ReadVertices() expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package xmp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qmuntal/gltf"
+)
+
+const (
+	// ExtXMPJSONLD defines the XMP unique key.
+	ExtXMPJSONLD = "KHR_xmp_json_ld"
+	// ExtXMP defines the key of the draft version of the extension, which has the same structure.
+	ExtXMP = "KHR_xmp"
+)
+
+// New returns a new xmp.XMP.
+func New() json.Unmarshaler {
+	return new(XMP)
+}
+
+func init() {
+	gltf.RegisterExtension(ExtXMPJSONLD, New)
+	gltf.RegisterExtension(ExtXMP, New)
+}
+
+// A Packet is an XMP metadata packet serialized as JSON-LD, such as {"@context": {...}, "dc:creator": ...}.
+type Packet = map[string]interface{}
+
+// XMP defines the KHR_xmp_json_ld extension, which is used at two levels.
+// In the document extensions it defines the Packets array,
+// and in the extensions of any other property it references with Packet the packet that applies to it.
+type XMP struct {
+	Packets []Packet `json:"packets,omitempty"`
+	Packet  *uint32  `json:"packet,omitempty"`
+}
+
+// UnmarshalJSON unmarshal the XMP extension.
+func (x *XMP) UnmarshalJSON(data []byte) error {
+	type alias XMP
+	return json.Unmarshal(data, (*alias)(x))
+}
+
+// Resolve returns the metadata packet referenced by a property with the given extensions,
+// which can be the document ones to get the packet that applies to the whole asset.
+// The return value is nil if the property does not reference any packet.
+// It fails if the referenced packet is not defined in the document.
+func Resolve(doc *gltf.Document, extensions gltf.Extensions) (Packet, error) {
+	ref := lookup(extensions)
+	if ref == nil || ref.Packet == nil {
+		return nil, nil
+	}
+	var packets []Packet
+	if root := lookup(doc.Extensions); root != nil {
+		packets = root.Packets
+	}
+	if int(*ref.Packet) >= len(packets) {
+		return nil, fmt.Errorf("gltf: xmp packet index %d out of range", *ref.Packet)
+	}
+	return packets[*ref.Packet], nil
+}
+
+func lookup(extensions gltf.Extensions) *XMP {
+	for _, key := range []string{ExtXMPJSONLD, ExtXMP} {
+		if x, ok := extensions[key].(*XMP); ok {
+			return x
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package xmp
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/qmuntal/gltf"
+)
+
+func TestXMP_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    *XMP
+		wantErr bool
+	}{
+		{"default", []byte("{}"), new(XMP), false},
+		{"packets", []byte(`{"packets": [{"@context": {"dc": "http://purl.org/dc/elements/1.1/"}, "dc:creator": "me"}]}`), &XMP{Packets: []Packet{
+			{"@context": map[string]interface{}{"dc": "http://purl.org/dc/elements/1.1/"}, "dc:creator": "me"},
+		}}, false},
+		{"packet", []byte(`{"packet": 1}`), &XMP{Packet: gltf.Index(1)}, false},
+		{"invalid", []byte(`{"packets": 1}`), new(XMP), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(XMP)
+			if err := got.UnmarshalJSON(tt.data); (err != nil) != tt.wantErr {
+				t.Errorf("XMP.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("XMP.UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	data := []byte(`{"asset": {"version": "2.0", "extensions": {"KHR_xmp_json_ld": {"packet": 0}}},
+	"extensionsUsed": ["KHR_xmp_json_ld"],
+	"extensions": {"KHR_xmp_json_ld": {"packets": [{"dc:title": "doc"}, {"dc:title": "mesh"}]}},
+	"meshes": [{"primitives": [{"attributes": {}}], "extensions": {"KHR_xmp_json_ld": {"packet": 1}}},
+	{"primitives": [{"attributes": {}}], "extensions": {"KHR_xmp": {"packet": 2}}},
+	{"primitives": [{"attributes": {}}]}]}`)
+	doc := new(gltf.Document)
+	if err := gltf.NewDecoder(bytes.NewReader(data), nil).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	tests := []struct {
+		name       string
+		extensions gltf.Extensions
+		want       Packet
+		wantErr    bool
+	}{
+		{"asset", doc.Asset.Extensions, Packet{"dc:title": "doc"}, false},
+		{"mesh", doc.Meshes[0].Extensions, Packet{"dc:title": "mesh"}, false},
+		{"outOfRange", doc.Meshes[1].Extensions, nil, true},
+		{"none", doc.Meshes[2].Extensions, nil, false},
+		{"document", doc.Extensions, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(doc, tt.extensions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}